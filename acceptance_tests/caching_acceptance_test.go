@@ -16,6 +16,7 @@ import (
 	"ai-meal-planner/internal/llm/llmtest"
 	"ai-meal-planner/internal/metrics"
 	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/rating"
 	"ai-meal-planner/internal/recipe"
 
 	_ "modernc.org/sqlite"
@@ -41,6 +42,22 @@ func (m *mockGhostClient) CreatePost(title, html string, tags []string, publish
 	return &ghost.Post{ID: "new-id", Title: title, HTML: html}, nil
 }
 
+func (m *mockGhostClient) GetPostBySlug(slug string) (*ghost.Post, error) {
+	return nil, nil
+}
+
+func (m *mockGhostClient) UpdatePost(id string, update ghost.PostUpdate) (*ghost.Post, error) {
+	return nil, nil
+}
+
+func (m *mockGhostClient) DeletePost(id string) error {
+	return nil
+}
+
+func (m *mockGhostClient) AddTags(id string, tags []string) (*ghost.Post, error) {
+	return nil, nil
+}
+
 // --- Mock LLM Client ---
 type MockTextGenerator struct {
 	generateContentCalls int
@@ -130,12 +147,13 @@ func TestFullWorkflow(t *testing.T) {
 	vectorRepo := llm.NewVectorRepository(db.SQL)
 	planRepo := planner.NewPlanRepository(db.SQL)
 	auditRepo := audit.NewAuditRepository(db.SQL)
+	ratingRepo := rating.NewRepository(db.SQL)
 
 	metricsStore := metrics.NewStore(db.SQL)
 
-	recipeSearchService := recipe.NewSearchService(recipeRepo, vectorRepo, mockEmbeddingGenerator)
-	mealPlanner := planner.NewPlanner(recipeSearchService, planRepo, mockTextGenerator, mockTextGenerator, mockTextGenerator)
-	recipeClipper := clipper.NewClipper(ghostClient, mockTextGenerator)
+	recipeSearchService := recipe.NewSearchService(recipeRepo, vectorRepo, mockEmbeddingGenerator, ratingRepo, planRepo)
+	mealPlanner := planner.NewPlanner(recipeSearchService, recipeSearchService, planRepo, mockTextGenerator, mockTextGenerator, mockTextGenerator)
+	recipeClipper := clipper.NewClipper(ghostClient, mockTextGenerator, nil)
 	application := app.NewApp(ghostClient, mockTextGenerator, mockTextGenerator, mockEmbeddingGenerator, metricsStore, mealPlanner, recipeClipper, &config.Config{
 		DefaultAdults:           2,
 		DefaultCookingFrequency: 7,
@@ -16,6 +16,7 @@ import (
 	"ai-meal-planner/internal/llm"
 	"ai-meal-planner/internal/metrics"
 	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/rating"
 	"ai-meal-planner/internal/recipe" // New import
 )
 
@@ -50,13 +51,14 @@ func main() {
 	vectorRepo := llm.NewVectorRepository(db.SQL)
 	planRepo := planner.NewPlanRepository(db.SQL)
 	auditRepo := audit.NewAuditRepository(db.SQL)
+	ratingRepo := rating.NewRepository(db.SQL)
 
 	metricsStore := metrics.NewStore(db.SQL)
 	defer metricsStore.Close()
 
-	recipeSearchService := recipe.NewSearchService(recipeRepo, vectorRepo, embedClient)
-	mealPlanner := planner.NewPlanner(recipeSearchService, planRepo, analystModel, chefModel, reviewerModel)
-	recipeClipper := clipper.NewClipper(ghostClient, normalizerModel)
+	recipeSearchService := recipe.NewSearchService(recipeRepo, vectorRepo, embedClient, ratingRepo, planRepo)
+	mealPlanner := planner.NewPlanner(recipeSearchService, recipeSearchService, planRepo, analystModel, chefModel, reviewerModel)
+	recipeClipper := clipper.NewClipper(ghostClient, normalizerModel, nil)
 
 	application := app.NewApp(
 		ghostClient,
@@ -123,6 +125,15 @@ func main() {
 		if err != nil {
 			log.Fatalf("Retagging failed: %v", err)
 		}
+	case "backfill":
+		if err := application.BackfillRecipes(ctx); err != nil {
+			log.Fatalf("Backfill failed: %v", err)
+		}
+	case "tags":
+		admin := recipe.NewTagAdmin(recipeRepo, vectorRepo, embedClient)
+		if err := runTagsCommand(ctx, admin, recipe.NewTaxonomy(db.SQL), os.Args[2:]); err != nil {
+			log.Fatalf("Tags command failed: %v", err)
+		}
 	case "plan":
 		planCmd := flag.NewFlagSet("plan", flag.ExitOnError)
 		request := planCmd.String("request", "", "What would you like to eat?")
@@ -187,6 +198,8 @@ func printUsage() {
 	fmt.Println("  ingest             Fetch and normalize recipes from Ghost")
 	fmt.Println("  reingest           Re-normalize one recipe by Ghost ID")
 	fmt.Println("  retag              Regenerate tags for one recipe or all recipes")
+	fmt.Println("  backfill           Re-extract recipes normalized by an older extractor")
+	fmt.Println("  tags               Review, list, merge, rename, prune, export or import tags")
 	fmt.Println("  migrate            Run database migrations")
 	fmt.Println("  metrics-cleanup    Remove old metric records")
 }
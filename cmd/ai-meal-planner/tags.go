@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"ai-meal-planner/internal/recipe"
+)
+
+const tagsUsage = `Usage: ai-meal-planner tags <subcommand> [arguments]
+
+Subcommands:
+  list                      Show every tag with the number of recipes using it
+  review                    Show the tags proposed by the tagger that wait for review
+  approve <tag> <category> [parent]
+                            Add a proposed tag to the taxonomy; category is protein,
+                            cuisine, diet, meal-type, ingredient or technique
+  map <tag> <to>            Record a proposed tag as a synonym of an existing one
+  reject <tag>              Drop a proposed tag
+  merge <from> <to>         Replace a tag with an existing one on every recipe
+  rename <from> <to>        Rename a tag to a name that is not in use yet
+  prune -min-count <n>      Remove tags used by fewer than n recipes
+  export [-file <path>]     Write the taxonomy and recipe tags as JSON (stdout by default)
+  import <path>             Apply a JSON file written by export`
+
+// runTagsCommand dispatches the tags subcommands. Curation changes re-embed the affected
+// recipes; review decisions apply to recipes on the next retag.
+func runTagsCommand(ctx context.Context, admin *recipe.TagAdmin, taxonomy *recipe.Taxonomy, args []string) error {
+	if len(args) == 0 {
+		return errTagsUsage("missing subcommand")
+	}
+
+	switch args[0] {
+	case "list":
+		counts, err := admin.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, c := range counts {
+			fmt.Printf("%5d  %s\n", c.Recipes, c.Tag)
+		}
+	case "review":
+		suggestions, err := taxonomy.Suggestions(ctx)
+		if err != nil {
+			return err
+		}
+		if len(suggestions) == 0 {
+			fmt.Println("No tags waiting for review.")
+		}
+		for _, s := range suggestions {
+			fmt.Printf("%-25s %-25s %3dx  (first seen on recipe %s)\n", s.Tag, s.LabelPt, s.Occurrences, s.RecipeID)
+		}
+	case "approve", "map", "reject":
+		var err error
+		switch {
+		case args[0] == "approve" && (len(args) == 3 || len(args) == 4):
+			parent := ""
+			if len(args) == 4 {
+				parent = args[3]
+			}
+			err = taxonomy.Approve(ctx, args[1], args[2], parent)
+		case args[0] == "map" && len(args) == 3:
+			err = taxonomy.MapSuggestion(ctx, args[1], args[2])
+		case args[0] == "reject" && len(args) == 2:
+			err = taxonomy.Reject(ctx, args[1])
+		default:
+			return errTagsUsage("wrong arguments for " + args[0])
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println("Done. Run `retag -all` to apply the change to existing recipes.")
+	case "merge", "rename":
+		if len(args) != 3 {
+			return errTagsUsage("wrong arguments for " + args[0])
+		}
+		apply := admin.Merge
+		if args[0] == "rename" {
+			apply = admin.Rename
+		}
+		changed, err := apply(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Updated %d recipes.\n", changed)
+	case "prune":
+		pruneCmd := flag.NewFlagSet("tags prune", flag.ContinueOnError)
+		minCount := pruneCmd.Int("min-count", 2, "Remove tags used by fewer than this many recipes")
+		if err := pruneCmd.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		pruned, err := admin.Prune(ctx, *minCount)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d tags.\n", len(pruned))
+		for _, tag := range pruned {
+			fmt.Printf("  %s\n", tag)
+		}
+	case "export":
+		exportCmd := flag.NewFlagSet("tags export", flag.ContinueOnError)
+		file := exportCmd.String("file", "", "Write to this file instead of stdout")
+		if err := exportCmd.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		export, err := admin.Export(ctx)
+		if err != nil {
+			return err
+		}
+		out := os.Stdout
+		if *file != "" {
+			if out, err = os.Create(*file); err != nil {
+				return fmt.Errorf("failed to create %s: %w", *file, err)
+			}
+			defer out.Close()
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(export)
+	case "import":
+		if len(args) != 2 {
+			return errTagsUsage("wrong arguments for import")
+		}
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[1], err)
+		}
+		var export recipe.TagExport
+		if err := json.Unmarshal(data, &export); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", args[1], err)
+		}
+		changed, err := admin.Import(ctx, export)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d taxonomy tags and updated %d recipes.\n", len(export.Taxonomy), changed)
+	default:
+		return errTagsUsage("unknown subcommand " + args[0])
+	}
+	return nil
+}
+
+// errTagsUsage reports a malformed tags command together with the usage text.
+func errTagsUsage(problem string) error {
+	return fmt.Errorf("%s\n\n%s", problem, tagsUsage)
+}
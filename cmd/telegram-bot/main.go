@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Reminder timezones resolve even without system zoneinfo
 
 	"ai-meal-planner/internal/audit"
 	"ai-meal-planner/internal/clipper"
@@ -16,8 +17,10 @@ import (
 	"ai-meal-planner/internal/ghost"
 	"ai-meal-planner/internal/llm"
 	"ai-meal-planner/internal/metrics"
-	"ai-meal-planner/internal/planner"  // New import
-	"ai-meal-planner/internal/recipe"   // New import
+	"ai-meal-planner/internal/planner" // New import
+	"ai-meal-planner/internal/rating"
+	"ai-meal-planner/internal/recipe" // New import
+	"ai-meal-planner/internal/reminder"
 	"ai-meal-planner/internal/shopping" // New import
 	"ai-meal-planner/internal/telegram"
 )
@@ -34,6 +37,8 @@ func main() {
 	chefModel := llm.NewGroqClient(cfg, cfg.ChefModel, 0.1)
 	normalizerModel := llm.NewGroqClient(cfg, cfg.NormalizerModel, 0.1)
 	taggerModel := llm.NewGroqClient(cfg, cfg.TaggerModel, 0.0)
+	visionModel := llm.NewGroqClient(cfg, cfg.VisionModel, 0.1)
+	intentModel := llm.NewGroqClient(cfg, cfg.IntentModel, 0.0)
 
 	embedClient := llm.NewEmbeddingClient(cfg)
 	defer embedClient.Close()
@@ -51,6 +56,8 @@ func main() {
 	planRepo := planner.NewPlanRepository(db.SQL)
 	shoppingRepo := shopping.NewRepository(db.SQL)
 	auditRepo := audit.NewAuditRepository(db.SQL)
+	ratingRepo := rating.NewRepository(db.SQL)
+	reminderRepo := reminder.NewRepository(db.SQL)
 
 	// 3. Initialize Ghost Client
 	ghostClient := ghost.NewClient(cfg)
@@ -62,15 +69,15 @@ func main() {
 	// Create reviewer model (use same high-reasoning model as Analyst for plan revision)
 	reviewerModel := llm.NewGroqClient(cfg, cfg.ReviewerModel, 0.1)
 
-	recipeSearchService := recipe.NewSearchService(recipeRepo, vectorRepo, embedClient)
-	mealPlanner := planner.NewPlanner(recipeSearchService, planRepo, analystModel, chefModel, reviewerModel)
-	recipeClipper := clipper.NewClipper(ghostClient, normalizerModel)
+	recipeSearchService := recipe.NewSearchService(recipeRepo, vectorRepo, embedClient, ratingRepo, planRepo)
+	mealPlanner := planner.NewPlanner(recipeSearchService, recipeSearchService, planRepo, analystModel, chefModel, reviewerModel)
+	recipeClipper := clipper.NewClipper(ghostClient, normalizerModel, visionModel)
 
 	// 6. Initialize Session Repository for conversation state tracking
 	sessionRepo := telegram.NewSessionRepository(db.SQL)
 
 	// 7. Initialize Telegram Bot
-	bot, err := telegram.NewBot(cfg, mealPlanner, recipeClipper, metricsStore, normalizerModel, taggerModel, embedClient, planRepo, recipeRepo, vectorRepo, shoppingRepo, sessionRepo, auditRepo)
+	bot, err := telegram.NewBot(cfg, mealPlanner, recipeClipper, ghostClient, metricsStore, normalizerModel, taggerModel, intentModel, embedClient, planRepo, recipeRepo, vectorRepo, shoppingRepo, sessionRepo, auditRepo, recipe.NewTaxonomy(db.SQL), ratingRepo, reminderRepo)
 	if err != nil {
 		log.Fatalf("Failed to initialize Telegram Bot: %v", err)
 	}
@@ -83,6 +90,15 @@ func main() {
 
 	bot.RegisterHandlers()
 
+	promptCtx, stopPrompts := context.WithCancel(context.Background())
+	defer stopPrompts()
+	go bot.StartRatingPrompts(promptCtx)
+	go bot.StartReminders(promptCtx)
+	go bot.StartCookingTimers(promptCtx)
+	if cfg.TelegramMode == config.TelegramModePolling {
+		go bot.StartPolling(promptCtx)
+	}
+
 	srv := &http.Server{
 		Addr:    ":" + port,
 		Handler: nil,
@@ -106,6 +122,9 @@ func main() {
 	if err := srv.Shutdown(ctxShutdown); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	// Let messages already being handled finish before the database closes
+	stopPrompts()
+	bot.Wait()
 
 	log.Println("Server exiting")
 }
@@ -75,7 +75,7 @@ func NewApp(
 		planRepo:      planRepo,
 		auditRepo:     auditRepo,
 		extractor:     recipe.NewExtractor(textGen, embedGen, vectorRepo), // Initialize Extractor
-		tagger:        recipe.NewTagger(tagGen),
+		tagger:        recipe.NewTagger(tagGen, recipe.NewTaxonomy(db.SQL)),
 		retagDelay:    defaultBulkRetagDelay,
 	}
 }
@@ -253,6 +253,51 @@ func (a *App) retagRecipe(ctx context.Context, rec value.Recipe, post ghost.Post
 	return nil
 }
 
+// BackfillRecipes re-extracts recipes normalized by an older extractor so they gain
+// the fields added since (steps, minutes, yield, cuisine, equipment, image, source URL).
+// Ingestion never does this on its own, since re-extracting costs an LLM call per recipe.
+// Requests are spaced out like bulk retagging to stay within LLM rate limits.
+func (a *App) BackfillRecipes(ctx context.Context) error {
+	ids, err := a.recipeRepo.ListOutdatedIDs(ctx, recipe.ExtractionVersion)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Found %d recipes to backfill.\n", len(ids))
+
+	processed := 0
+	failed := 0
+	for i, id := range ids {
+		post, err := a.ghostClient.FetchRecipeByID(id)
+		if err == nil && post == nil {
+			err = fmt.Errorf("ghost returned no recipe for %s", id)
+		}
+		if err == nil {
+			log.Printf("Re-extracting '%s'...", post.Title)
+			err = ProcessAndSaveRecipe(ctx, a.extractor, a.tagger, a.recipeRepo, a.metricsStore, *post, true)
+		}
+		if err != nil {
+			failed++
+			log.Printf("Failed to backfill recipe %s: %v", id, err)
+		} else {
+			processed++
+		}
+
+		if a.retagDelay > 0 && i < len(ids)-1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(a.retagDelay):
+			}
+		}
+	}
+
+	fmt.Printf("Backfilled %d recipes.\n", processed)
+	if failed > 0 {
+		return fmt.Errorf("failed to backfill %d recipes", failed)
+	}
+	return nil
+}
+
 func ghostTagNames(tags []ghost.Tag) []string {
 	names := make([]string, 0, len(tags))
 	for _, tag := range tags {
@@ -289,7 +334,7 @@ func (a *App) GenerateMealPlan(ctx context.Context, userID string, request strin
 	}
 
 	// Save the generated meal plan to user memory
-	if _, err := a.planRepo.Save(ctx, userID, plan); err != nil {
+	if _, err := a.planRepo.SaveVersion(ctx, userID, plan, planner.VersionAuthorAnalyst, request); err != nil {
 		log.Printf("Warning: failed to save meal plan to user memory: %v", err)
 	}
 
@@ -74,7 +74,7 @@ func TestRetagRecipeByIDOnlyRegeneratesTagsAndEmbedding(t *testing.T) {
 				{"pt-BR":"brócolis","en":"broccoli"},
 				{"pt-BR":"fritadeira sem óleo","en":"air fryer"}
 			]
-		}`}),
+		}`}, nil),
 	}
 
 	if err := application.RetagRecipeByID(ctx, original.ID); err != nil {
@@ -132,7 +132,7 @@ func TestRetagAllRecipesUpdatesOnlyNormalizedRecipes(t *testing.T) {
 		extractor:    recipe.NewExtractor(nil, embGen, vectorRepo),
 		tagger: recipe.NewTagger(&llmtest.MockTextGenerator{Response: `{
 			"tags":[{"pt-BR":"receita","en":"recipe"}]
-		}`}),
+		}`}, nil),
 	}
 
 	if err := application.RetagAllRecipes(ctx); err != nil {
@@ -156,6 +156,22 @@ func (m *mockGhostClientForIngest) CreatePost(title, html string, tags []string,
 	return nil, nil
 }
 
+func (m *mockGhostClientForIngest) GetPostBySlug(slug string) (*ghost.Post, error) {
+	return nil, nil
+}
+
+func (m *mockGhostClientForIngest) UpdatePost(id string, update ghost.PostUpdate) (*ghost.Post, error) {
+	return nil, nil
+}
+
+func (m *mockGhostClientForIngest) DeletePost(id string) error {
+	return nil
+}
+
+func (m *mockGhostClientForIngest) AddTags(id string, tags []string) (*ghost.Post, error) {
+	return nil, nil
+}
+
 func TestIngestRecipes_Cleanup(t *testing.T) {
 	ctx := context.Background()
 
@@ -229,7 +245,7 @@ func TestIngestRecipes_Cleanup(t *testing.T) {
 		vectorRepo:   vectorRepo,
 		metricsStore: metricsStore,
 		extractor:    recipe.NewExtractor(textGen, embGen, vectorRepo),
-		tagger:       recipe.NewTagger(&llmtest.MockTextGenerator{Response: `{"tags":[{"pt-BR":"receita","en":"recipe"}]}`}),
+		tagger:       recipe.NewTagger(&llmtest.MockTextGenerator{Response: `{"tags":[{"pt-BR":"receita","en":"recipe"}]}`}, nil),
 	}
 
 	// 4. Run IngestRecipes
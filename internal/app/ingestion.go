@@ -37,7 +37,9 @@ func ProcessAndSaveRecipe(
 	return metricsStore.RecordMeta(meta)
 }
 
-// ensureRecipe retrieves a recipe from the repository or extracts it from the post if missing (or if forced).
+// ensureRecipe retrieves a recipe from the repository or extracts it from the post if missing,
+// updated in Ghost, or forced. Recipes from an older extractor are kept as they are; the
+// backfill command re-extracts them.
 func ensureRecipe(
 	ctx context.Context,
 	extractor *recipe.Extractor,
@@ -47,15 +49,15 @@ func ensureRecipe(
 	post ghost.Post,
 	force bool,
 ) (value.Recipe, error) {
-	if !force {
-		rec, err := recipeRepo.Get(ctx, post.ID)
-		if err == nil {
-			if !sourceWasUpdated(rec.UpdatedAt, post.UpdatedAt) {
-				return rec, nil
-			}
-		} else if !errors.Is(err, sql.ErrNoRows) {
-			return value.Recipe{}, fmt.Errorf("failed to get recipe from repo: %w", err)
-		}
+	stored, err := recipeRepo.Get(ctx, post.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return value.Recipe{}, fmt.Errorf("failed to get recipe from repo: %w", err)
+	}
+	exists := err == nil
+	sourceUpdated := !exists || sourceWasUpdated(stored.UpdatedAt, post.UpdatedAt)
+
+	if !force && !sourceUpdated {
+		return stored, nil
 	}
 
 	// Extraction required
@@ -65,11 +67,13 @@ func ensureRecipe(
 	}
 
 	res, err := extractor.ExtractRecipe(ctx, recipe.PostData{
-		ID:        post.ID,
-		Title:     post.Title,
-		UpdatedAt: post.UpdatedAt,
-		HTML:      post.HTML,
-		Tags:      tags,
+		ID:           post.ID,
+		Title:        post.Title,
+		URL:          post.URL,
+		UpdatedAt:    post.UpdatedAt,
+		HTML:         post.HTML,
+		FeatureImage: post.FeatureImage,
+		Tags:         tags,
 	})
 	if err != nil {
 		return value.Recipe{}, fmt.Errorf("failed to extract recipe: %w", err)
@@ -81,7 +85,13 @@ func ensureRecipe(
 	}
 	res.Recipe.Tags = tagResult.Tags
 
-	if err := recipeRepo.Save(ctx, res.Recipe); err != nil {
+	// Save skips rows whose Ghost timestamp did not move, so re-extracting the
+	// same revision (forced or backfilled) must overwrite explicitly.
+	save := recipeRepo.Save
+	if !sourceUpdated {
+		save = recipeRepo.Update
+	}
+	if err := save(ctx, res.Recipe); err != nil {
 		return value.Recipe{}, fmt.Errorf("failed to save recipe: %w", err)
 	}
 
@@ -12,6 +12,7 @@ import (
 	"ai-meal-planner/internal/llm/llmtest"
 	"ai-meal-planner/internal/metrics"
 	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/value"
 
 	_ "modernc.org/sqlite"
 )
@@ -53,7 +54,7 @@ func TestProcessAndSaveRecipe(t *testing.T) {
 	}}
 	embGen := &llmtest.MockEmbeddingGenerator{Values: []float32{0.1, 0.2}}
 	extractor := recipe.NewExtractor(textGen, embGen, vectorRepo)
-	tagger := recipe.NewTagger(&llmtest.MockTextGenerator{Response: `{"tags":[{"pt-BR":"teste","en":"test"}]}`})
+	tagger := recipe.NewTagger(&llmtest.MockTextGenerator{Response: `{"tags":[{"pt-BR":"teste","en":"test"}]}`}, nil)
 
 	post := ghost.Post{
 		ID:        "1",
@@ -131,4 +132,39 @@ func TestProcessAndSaveRecipe(t *testing.T) {
 			t.Errorf("recipe title = %q, want %q", rec.Title, recipeTitle)
 		}
 	})
+
+	t.Run("Outdated Extraction Waits For Backfill", func(t *testing.T) {
+		stale := value.Recipe{ID: "2", Title: "Old Recipe", UpdatedAt: post.UpdatedAt}
+		if err := recipeRepo.Save(ctx, stale); err != nil {
+			t.Fatalf("save stale recipe: %v", err)
+		}
+		outdated, err := recipeRepo.ListOutdatedIDs(ctx, recipe.ExtractionVersion)
+		if err != nil || !slices.Equal(outdated, []string{"2"}) {
+			t.Fatalf("ListOutdatedIDs() = %v, %v; want [2]", outdated, err)
+		}
+
+		recipeTitle = "Backfilled Recipe"
+		stalePost := post
+		stalePost.ID = "2"
+		calls := extractionCalls
+		if err := ProcessAndSaveRecipe(ctx, extractor, tagger, recipeRepo, metricsStore, stalePost, false); err != nil {
+			t.Fatalf("ProcessAndSaveRecipe failed: %v", err)
+		}
+		if extractionCalls != calls {
+			t.Fatalf("extraction calls = %d, want %d: ingestion must not re-extract outdated recipes", extractionCalls, calls)
+		}
+
+		// Backfill forces the re-extraction
+		if err := ProcessAndSaveRecipe(ctx, extractor, tagger, recipeRepo, metricsStore, stalePost, true); err != nil {
+			t.Fatalf("ProcessAndSaveRecipe failed: %v", err)
+		}
+
+		rec, err := recipeRepo.Get(ctx, "2")
+		if err != nil {
+			t.Fatalf("get backfilled recipe: %v", err)
+		}
+		if rec.Title != recipeTitle || rec.ExtractionVersion != recipe.ExtractionVersion {
+			t.Errorf("recipe = %q (v%d), want %q (v%d)", rec.Title, rec.ExtractionVersion, recipeTitle, recipe.ExtractionVersion)
+		}
+	})
 }
@@ -20,6 +20,28 @@ func (q *Queries) CleanupAuditLogs(ctx context.Context, createdAt time.Time) err
 	return err
 }
 
+const getAuditLog = `-- name: GetAuditLog :one
+SELECT id, user_id, plan_id, action_type, original_request, user_feedback, previous_state, new_state, created_at FROM audit_logs
+WHERE id = ?
+`
+
+func (q *Queries) GetAuditLog(ctx context.Context, id int64) (AuditLog, error) {
+	row := q.db.QueryRowContext(ctx, getAuditLog, id)
+	var i AuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PlanID,
+		&i.ActionType,
+		&i.OriginalRequest,
+		&i.UserFeedback,
+		&i.PreviousState,
+		&i.NewState,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const insertAuditLog = `-- name: InsertAuditLog :exec
 INSERT INTO audit_logs (
     user_id, plan_id, action_type, original_request, user_feedback, previous_state, new_state
@@ -48,3 +70,48 @@ func (q *Queries) InsertAuditLog(ctx context.Context, arg InsertAuditLogParams)
 	)
 	return err
 }
+
+const listAuditLogsByPlan = `-- name: ListAuditLogsByPlan :many
+SELECT id, user_id, plan_id, action_type, original_request, user_feedback, previous_state, new_state, created_at FROM audit_logs
+WHERE plan_id = ?
+ORDER BY id DESC
+LIMIT ?
+`
+
+type ListAuditLogsByPlanParams struct {
+	PlanID sql.NullInt64
+	Limit  int64
+}
+
+func (q *Queries) ListAuditLogsByPlan(ctx context.Context, arg ListAuditLogsByPlanParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditLogsByPlan, arg.PlanID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PlanID,
+			&i.ActionType,
+			&i.OriginalRequest,
+			&i.UserFeedback,
+			&i.PreviousState,
+			&i.NewState,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -10,6 +10,29 @@ import (
 	"ai-meal-planner/internal/audit/db"
 )
 
+// Action types recorded for meal plan changes.
+const (
+	ActionRevisePlan  = "RevisePlan"  // The PlanReviewer revised the plan from user feedback
+	ActionRestorePlan = "RestorePlan" // The user restored an earlier state from the history
+	ActionUndoChange  = "UndoChange"  // The user undid the latest change
+	ActionSwapDay     = "SwapDay"     // The user swapped one day's recipe for a suggested one
+	ActionToggleLock  = "ToggleLock"  // The user locked or unlocked a day against revisions
+)
+
+// Entry is one recorded plan change. The states are the JSON snapshots passed to
+// LogInteraction, left for the caller to decode.
+type Entry struct {
+	ID              int64
+	UserID          string
+	PlanID          int64
+	ActionType      string
+	OriginalRequest string
+	UserFeedback    string
+	PreviousState   json.RawMessage
+	NewState        json.RawMessage
+	CreatedAt       time.Time
+}
+
 // AuditRepository handles persistence of audit logs for LLM interactions.
 type AuditRepository struct {
 	queries *auditdb.Queries
@@ -63,3 +86,52 @@ func (r *AuditRepository) Cleanup(ctx context.Context, days int) error {
 	cutoff := time.Now().AddDate(0, 0, -days)
 	return r.queries.CleanupAuditLogs(ctx, cutoff)
 }
+
+// ListByPlan returns up to limit entries recorded for a plan, newest first.
+func (r *AuditRepository) ListByPlan(ctx context.Context, planID int64, limit int) ([]Entry, error) {
+	rows, err := r.queries.ListAuditLogsByPlan(ctx, auditdb.ListAuditLogsByPlanParams{
+		PlanID: sql.NullInt64{Int64: planID, Valid: true},
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs for plan %d: %w", planID, err)
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, entryFromRow(row))
+	}
+	return entries, nil
+}
+
+// GetByID retrieves a single entry, or nil if it does not exist.
+func (r *AuditRepository) GetByID(ctx context.Context, id int64) (*Entry, error) {
+	row, err := r.queries.GetAuditLog(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get audit log %d: %w", id, err)
+	}
+	entry := entryFromRow(row)
+	return &entry, nil
+}
+
+func entryFromRow(row auditdb.AuditLog) Entry {
+	entry := Entry{
+		ID:              row.ID,
+		UserID:          row.UserID,
+		PlanID:          row.PlanID.Int64,
+		ActionType:      row.ActionType,
+		OriginalRequest: row.OriginalRequest.String,
+		UserFeedback:    row.UserFeedback.String,
+		CreatedAt:       row.CreatedAt,
+	}
+	if row.PreviousState.Valid {
+		entry.PreviousState = json.RawMessage(row.PreviousState.String)
+	}
+	if row.NewState.Valid {
+		entry.NewState = json.RawMessage(row.NewState.String)
+	}
+	return entry
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
+	"io"
 	"net/http"
+	neturl "net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -18,8 +22,21 @@ import (
 type Clipper struct {
 	ghostClient ghost.Client
 	textGen     llm.TextGenerator
+	visionGen   llm.TextGenerator
 }
 
+// maxImageBytes matches the largest base64 image the vision API accepts.
+const maxImageBytes = 4 << 20
+
+// extractionSchema is the JSON shape both the page and the image prompts ask for.
+const extractionSchema = `{
+  "title": "Recipe Title",
+  "ingredients": ["item 1", "item 2", ...],
+  "steps": ["Step 1 description", "Step 2 description", ...],
+  "prep_time": "e.g. 30 mins",
+  "servings": "e.g. 4 people"
+}`
+
 // ExtractedRecipe represents the data structured by the AI.
 type ExtractedRecipe struct {
 	Title       string   `json:"title"`
@@ -27,88 +44,189 @@ type ExtractedRecipe struct {
 	Steps       []string `json:"steps"`
 	PrepTime    string   `json:"prep_time"`
 	Servings    string   `json:"servings"`
+
+	// Only filled from schema.org structured data
+	PrepMinutes  int             `json:"prep_minutes,omitempty"`
+	CookMinutes  int             `json:"cook_minutes,omitempty"`
+	TotalMinutes int             `json:"total_minutes,omitempty"`
+	Images       []string        `json:"images,omitempty"`
+	Nutrition    []NutritionFact `json:"nutrition,omitempty"`
 }
 
 // NewClipper creates a new Clipper instance.
-func NewClipper(ghostClient ghost.Client, textGen llm.TextGenerator) *Clipper {
+// visionGen must accept image input; it is only used for photo clips.
+func NewClipper(ghostClient ghost.Client, textGen, visionGen llm.TextGenerator) *Clipper {
 	return &Clipper{
 		ghostClient: ghostClient,
 		textGen:     textGen,
+		visionGen:   visionGen,
 	}
 }
 
-// ClipURL fetches the URL, extracts the recipe using AI, and saves it to Ghost.
-func (c *Clipper) ClipURL(ctx context.Context, url string, manualTags []string) (*ghost.Post, error) {
-	// 1. Fetch and Clean HTML
-	content, err := c.fetchAndCleanHTML(url)
+// ExtractURL fetches the URL and extracts the recipe without saving it, so it can be
+// reviewed first. Schema.org data published by the page is used when present; the AI
+// is only asked to read the page text when there is none.
+func (c *Clipper) ExtractURL(ctx context.Context, url string) (*ExtractedRecipe, error) {
+	// 1. Fetch HTML
+	doc, err := c.fetchDocument(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch content: %w", err)
 	}
 
-	// 2. Extract Data via Groq
+	// 2. Extract Data, preferring structured data over the LLM
+	if extracted, ok := extractStructuredRecipe(doc); ok {
+		return extracted, nil
+	}
+	return c.extractWithLLM(ctx, documentText(doc))
+}
+
+// Publish formats an extracted recipe and saves it to Ghost, either published or as a draft.
+// sourceURL may be empty for recipes that did not come from a web page.
+func (c *Clipper) Publish(ctx context.Context, extracted ExtractedRecipe, sourceURL string, manualTags []string, publish bool) (*ghost.Post, error) {
+	// 1. Format as Ghost HTML
+	postHTML := c.formatToHTML(extracted, sourceURL)
+
+	// 2. Use only Manual Tags (AI tags are ignored per user preference)
+	tagMap := make(map[string]struct{})
+	for _, t := range manualTags {
+		trimmed := strings.ToLower(strings.TrimSpace(t))
+		if trimmed != "" {
+			tagMap[trimmed] = struct{}{}
+		}
+	}
+
+	finalTags := make([]string, 0, len(tagMap))
+	for t := range tagMap {
+		finalTags = append(finalTags, t)
+	}
+
+	// 3. Save to Ghost
+	post, err := c.ghostClient.CreatePost(extracted.Title, postHTML, finalTags, publish)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save to ghost: %w", err)
+	}
+
+	return post, nil
+}
+
+// extractWithLLM asks the AI to structure the recipe from the page text.
+func (c *Clipper) extractWithLLM(ctx context.Context, content string) (*ExtractedRecipe, error) {
 	prompt := fmt.Sprintf(`
 You are a recipe extraction expert. Extract the recipe details from the following HTML content.
 Return the result strictly as a JSON object with this structure:
-{
-  "title": "Recipe Title",
-  "ingredients": ["item 1", "item 2", ...],
-  "steps": ["Step 1 description", "Step 2 description", ...],
-  "prep_time": "e.g. 30 mins",
-  "servings": "e.g. 4 people"
-}
+%s
 
 HTML Content:
 %s
-`, content)
+`, extractionSchema, content)
 
 	resp, err := c.textGen.GenerateContent(ctx, llm.Conversation{{Role: "user", Content: prompt}}, llm.NoTools)
 	if err != nil {
 		return nil, fmt.Errorf("ai extraction failed: %w", err)
 	}
 
-	// Sanitize LLM response (remove markdown code blocks if present)
-	jsonContent := strings.TrimSpace(resp.Message.Content)
-	jsonContent = strings.TrimPrefix(jsonContent, "```json")
-	jsonContent = strings.TrimPrefix(jsonContent, "```")
-	jsonContent = strings.TrimSuffix(jsonContent, "```")
-	jsonContent = strings.TrimSpace(jsonContent)
+	return parseExtractedRecipe(resp.Message.Content)
+}
 
-	var extracted ExtractedRecipe
-	if err := json.Unmarshal([]byte(jsonContent), &extracted); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w. Response: %s", err, resp.Message.Content)
+// ExtractFromImage reads a recipe from a photo, such as a handwritten card or a cookbook page.
+func (c *Clipper) ExtractFromImage(ctx context.Context, image llm.Image) (*ExtractedRecipe, error) {
+	if c.visionGen == nil {
+		return nil, fmt.Errorf("image clipping is not configured")
 	}
 
-	// 3. Format as Ghost HTML
-	html := c.formatToHTML(extracted, url)
+	prompt := fmt.Sprintf(`
+You are a recipe extraction expert. The attached image shows a recipe, possibly handwritten or printed in a cookbook.
+Transcribe it faithfully, keeping the original language and quantities. Do not invent missing steps or ingredients.
+Return the result strictly as a JSON object with this structure:
+%s
 
-	// 4. Use only Manual Tags (AI tags are ignored per user preference)
-	tagMap := make(map[string]struct{})
-	for _, t := range manualTags {
-		trimmed := strings.ToLower(strings.TrimSpace(t))
-		if trimmed != "" {
-			tagMap[trimmed] = struct{}{}
-		}
+If the image does not contain a recipe, return {"title": ""}.
+`, extractionSchema)
+
+	conversation := llm.Conversation{{Role: "user", Content: prompt, Images: []llm.Image{image}}}
+	resp, err := c.visionGen.GenerateContent(ctx, conversation, llm.NoTools)
+	if err != nil {
+		return nil, fmt.Errorf("ai image extraction failed: %w", err)
 	}
 
-	finalTags := make([]string, 0, len(tagMap))
-	for t := range tagMap {
-		finalTags = append(finalTags, t)
+	extracted, err := parseExtractedRecipe(resp.Message.Content)
+	if err != nil {
+		return nil, err
+	}
+	if extracted.Title == "" || len(extracted.Ingredients) == 0 {
+		return nil, fmt.Errorf("no recipe found in the image")
 	}
+	return extracted, nil
+}
 
-	// 5. Save to Ghost (Published)
-	post, err := c.ghostClient.CreatePost(extracted.Title, html, finalTags, true)
+// FetchImage downloads an image so it can be sent inline to the vision model.
+func (c *Clipper) FetchImage(ctx context.Context, url string) (llm.Image, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save to ghost: %w", err)
+		return llm.Image{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	return post, nil
+	resp, err := client.Do(req)
+	if err != nil {
+		return llm.Image{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return llm.Image{}, fmt.Errorf("failed to fetch image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return llm.Image{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxImageBytes {
+		return llm.Image{}, fmt.Errorf("image is larger than %d MB", maxImageBytes>>20)
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return llm.Image{}, fmt.Errorf("url does not point to an image (got %s)", mimeType)
+	}
+
+	return llm.Image{MIMEType: mimeType, Data: data}, nil
 }
 
-func (c *Clipper) fetchAndCleanHTML(url string) (string, error) {
+// IsImageURL reports whether a URL points straight at an image file.
+func IsImageURL(rawURL string) bool {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".jpg", ".jpeg", ".png", ".webp", ".gif":
+		return true
+	}
+	return false
+}
+
+func parseExtractedRecipe(content string) (*ExtractedRecipe, error) {
+	// Sanitize LLM response (remove markdown code blocks if present)
+	jsonContent := strings.TrimSpace(content)
+	jsonContent = strings.TrimPrefix(jsonContent, "```json")
+	jsonContent = strings.TrimPrefix(jsonContent, "```")
+	jsonContent = strings.TrimSuffix(jsonContent, "```")
+	jsonContent = strings.TrimSpace(jsonContent)
+
+	var extracted ExtractedRecipe
+	if err := json.Unmarshal([]byte(jsonContent), &extracted); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w. Response: %s", err, content)
+	}
+
+	return &extracted, nil
+}
+
+func (c *Clipper) fetchDocument(url string) (*goquery.Document, error) {
 	client := &http.Client{Timeout: 15 * time.Second}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set standard browser headers to avoid being blocked (406 Not Acceptable)
@@ -118,46 +236,64 @@ func (c *Clipper) fetchAndCleanHTML(url string) (string, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch URL: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("failed to fetch URL: status %d", resp.StatusCode)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return "", err
-	}
+	return goquery.NewDocumentFromReader(resp.Body)
+}
 
+// documentText strips noise from the page and returns its visible text.
+// It modifies doc, so structured data must be read before calling it.
+func documentText(doc *goquery.Document) string {
 	// Remove noise to save LLM tokens
 	doc.Find("script, style, nav, footer, iframe, ads, .ads, #ads").Each(func(i int, s *goquery.Selection) {
 		s.Remove()
 	})
 
 	// Return the text content and some structural tags (p, li, h1-h3)
-	return doc.Find("body").Text(), nil
+	return doc.Find("body").Text()
 }
 
 func (c *Clipper) formatToHTML(r ExtractedRecipe, sourceURL string) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("<p><i>Imported from: <a href=\"%s\">%s</a></i></p>", sourceURL, sourceURL))
+	if sourceURL != "" {
+		sb.WriteString(fmt.Sprintf("<p><i>Imported from: <a href=\"%s\">%s</a></i></p>", html.EscapeString(sourceURL), html.EscapeString(sourceURL)))
+	}
+
+	if len(r.Images) > 0 {
+		sb.WriteString(fmt.Sprintf("<figure><img src=\"%s\" alt=\"%s\"></figure>", html.EscapeString(r.Images[0]), html.EscapeString(r.Title)))
+	}
 
 	sb.WriteString("<h2>Ingredients</h2><ul>")
 	for _, ing := range r.Ingredients {
-		sb.WriteString(fmt.Sprintf("<li>%s</li>", ing))
+		sb.WriteString(fmt.Sprintf("<li>%s</li>", html.EscapeString(ing)))
 	}
 	sb.WriteString("</ul>")
 
 	sb.WriteString("<h2>Instructions</h2><ol>")
 	for _, step := range r.Steps {
-		sb.WriteString(fmt.Sprintf("<li>%s</li>", step))
+		sb.WriteString(fmt.Sprintf("<li>%s</li>", html.EscapeString(step)))
 	}
 	sb.WriteString("</ol>")
 
+	if len(r.Nutrition) > 0 {
+		sb.WriteString("<h2>Nutrition</h2><ul>")
+		for _, n := range r.Nutrition {
+			sb.WriteString(fmt.Sprintf("<li><strong>%s:</strong> %s</li>", html.EscapeString(n.Label), html.EscapeString(n.Value)))
+		}
+		sb.WriteString("</ul>")
+	}
+
 	sb.WriteString("<hr>")
-	sb.WriteString(fmt.Sprintf("<p><strong>Prep Time:</strong> %s | <strong>Servings:</strong> %s</p>", r.PrepTime, r.Servings))
+	sb.WriteString(fmt.Sprintf("<p><strong>Prep Time:</strong> %s | <strong>Servings:</strong> %s</p>", html.EscapeString(r.PrepTime), html.EscapeString(r.Servings)))
+	if r.PrepMinutes > 0 && r.CookMinutes > 0 {
+		sb.WriteString(fmt.Sprintf("<p><strong>Active:</strong> %s | <strong>Cooking:</strong> %s</p>", formatMinutes(r.PrepMinutes), formatMinutes(r.CookMinutes)))
+	}
 
 	return sb.String()
 }
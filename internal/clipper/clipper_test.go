@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"ai-meal-planner/internal/ghost"
+	"ai-meal-planner/internal/llm"
 	"ai-meal-planner/internal/llm/llmtest"
 )
 
@@ -38,6 +39,22 @@ func (m *MockGhostClient) CreatePost(title, html string, tags []string, publish
 	return m.CreatedPost, nil
 }
 
+func (m *MockGhostClient) GetPostBySlug(slug string) (*ghost.Post, error) {
+	return nil, nil
+}
+
+func (m *MockGhostClient) UpdatePost(id string, update ghost.PostUpdate) (*ghost.Post, error) {
+	return nil, nil
+}
+
+func (m *MockGhostClient) DeletePost(id string) error {
+	return nil
+}
+
+func (m *MockGhostClient) AddTags(id string, tags []string) (*ghost.Post, error) {
+	return nil, nil
+}
+
 // --- Tests ---
 
 func TestFetchAndCleanHTML(t *testing.T) {
@@ -59,15 +76,16 @@ func TestFetchAndCleanHTML(t *testing.T) {
 	defer ts.Close()
 
 	// 2. Initialize Clipper (deps don't matter for this private method test, but we need the struct)
-	c := NewClipper(&MockGhostClient{}, &llmtest.MockTextGenerator{})
+	c := NewClipper(&MockGhostClient{}, &llmtest.MockTextGenerator{}, nil)
 
 	// 3. Run the private method (using export_test.go trick or just testing public ClipURL if preferred)
 	// Since go doesn't allow testing private methods easily from external test package,
 	// we are in package clipper, so we can access it.
-	cleanText, err := c.fetchAndCleanHTML(ts.URL)
+	doc, err := c.fetchDocument(ts.URL)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
+	cleanText := documentText(doc)
 
 	// 4. Assertions
 	if strings.Contains(cleanText, "alert('bad')") {
@@ -88,7 +106,7 @@ func TestFetchAndCleanHTML(t *testing.T) {
 }
 
 func TestFormatToHTML(t *testing.T) {
-	c := NewClipper(nil, nil)
+	c := NewClipper(nil, nil, nil)
 
 	recipe := ExtractedRecipe{
 		Title:       "Pancakes",
@@ -114,13 +132,13 @@ func TestFormatToHTML(t *testing.T) {
 	}
 }
 
-func TestClipURL_Success(t *testing.T) {
+func TestExtractURLAndPublish(t *testing.T) {
 	// Mock AI Response
 	aiResponse := `{"title": "Mock Pie", "ingredients": ["Apple"], "steps": ["Bake"], "prep_time": "1h", "servings": "8"}`
 
 	mockGhost := &MockGhostClient{}
 	mockAI := &llmtest.MockTextGenerator{Response: aiResponse}
-	c := NewClipper(mockGhost, mockAI)
+	c := NewClipper(mockGhost, mockAI, nil)
 
 	// Mock Server for the URL fetch
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -128,9 +146,17 @@ func TestClipURL_Success(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	post, err := c.ClipURL(context.Background(), ts.URL, nil)
+	extracted, err := c.ExtractURL(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("ExtractURL failed: %v", err)
+	}
+	if mockGhost.CreatedPost != nil {
+		t.Fatal("Expected nothing to be saved before the recipe is confirmed")
+	}
+
+	post, err := c.Publish(context.Background(), *extracted, ts.URL, nil, true)
 	if err != nil {
-		t.Fatalf("ClipURL failed: %v", err)
+		t.Fatalf("Publish failed: %v", err)
 	}
 
 	if post.Title != "Mock Pie" {
@@ -143,3 +169,60 @@ func TestClipURL_Success(t *testing.T) {
 		t.Error("Expected HTML content to contain extracted ingredients")
 	}
 }
+
+func TestExtractFromImage(t *testing.T) {
+	var sentImages int
+	vision := &llmtest.MockTextGenerator{GenerateFn: func(conversation llm.Conversation) llm.ContentResponse {
+		sentImages = len(conversation[0].Images)
+		return llm.ContentResponse{Message: llm.Message{
+			Content: `{"title": "Bolo de Cenoura", "ingredients": ["3 cenouras"], "steps": ["Bata tudo"], "prep_time": "50 mins", "servings": "8"}`,
+		}}
+	}}
+	c := NewClipper(&MockGhostClient{}, nil, vision)
+
+	pngHeader := []byte("\x89PNG\r\n\x1a\n0000")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(pngHeader)
+	}))
+	defer ts.Close()
+
+	image, err := c.FetchImage(context.Background(), ts.URL+"/card.png")
+	if err != nil {
+		t.Fatalf("FetchImage failed: %v", err)
+	}
+	if image.MIMEType != "image/png" {
+		t.Errorf("MIMEType = %q, want image/png", image.MIMEType)
+	}
+
+	recipe, err := c.ExtractFromImage(context.Background(), image)
+	if err != nil {
+		t.Fatalf("ExtractFromImage failed: %v", err)
+	}
+	if recipe.Title != "Bolo de Cenoura" {
+		t.Errorf("Title = %q", recipe.Title)
+	}
+	if sentImages != 1 {
+		t.Errorf("expected the image to be attached to the prompt, got %d images", sentImages)
+	}
+}
+
+func TestExtractFromImage_NoRecipe(t *testing.T) {
+	c := NewClipper(nil, nil, &llmtest.MockTextGenerator{Response: `{"title": ""}`})
+	if _, err := c.ExtractFromImage(context.Background(), llm.Image{MIMEType: "image/jpeg"}); err == nil {
+		t.Error("expected an error when the image has no recipe")
+	}
+}
+
+func TestIsImageURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://example.com/recipe.JPG":          true,
+		"https://example.com/card.png?size=large": true,
+		"https://example.com/recipes/bolo":        false,
+		"https://example.com/page.html":           false,
+	}
+	for url, want := range cases {
+		if got := IsImageURL(url); got != want {
+			t.Errorf("IsImageURL(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
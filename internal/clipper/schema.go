@@ -0,0 +1,471 @@
+package clipper
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// NutritionFact is a single labelled value from a schema.org NutritionInformation object.
+type NutritionFact struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// nutritionFields lists the NutritionInformation properties we keep, in display order.
+var nutritionFields = []struct {
+	Property string
+	Label    string
+}{
+	{"calories", "Calories"},
+	{"proteinContent", "Protein"},
+	{"carbohydrateContent", "Carbohydrates"},
+	{"sugarContent", "Sugar"},
+	{"fiberContent", "Fiber"},
+	{"fatContent", "Fat"},
+	{"saturatedFatContent", "Saturated fat"},
+	{"cholesterolContent", "Cholesterol"},
+	{"sodiumContent", "Sodium"},
+}
+
+var (
+	isoDurationRe = regexp.MustCompile(`(?i)^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	htmlTagRe     = regexp.MustCompile(`<[^>]*>`)
+	spaceBeforeRe = regexp.MustCompile(`\s+([.,;:!?)])`)
+)
+
+// extractStructuredRecipe looks for a schema.org Recipe published as JSON-LD or microdata.
+// It must run before the document is cleaned, since cleaning removes the ld+json scripts.
+// The result is only usable when it has a title, ingredients and steps.
+func extractStructuredRecipe(doc *goquery.Document) (*ExtractedRecipe, bool) {
+	if r, ok := extractJSONLDRecipe(doc); ok {
+		return r, true
+	}
+	return extractMicrodataRecipe(doc)
+}
+
+func (r *ExtractedRecipe) isComplete() bool {
+	return r.Title != "" && len(r.Ingredients) > 0 && len(r.Steps) > 0
+}
+
+// --- JSON-LD ---
+
+func extractJSONLDRecipe(doc *goquery.Document) (*ExtractedRecipe, bool) {
+	var found *ExtractedRecipe
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var data interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &data); err != nil {
+			return true
+		}
+
+		obj := findRecipeObject(data)
+		if obj == nil {
+			return true
+		}
+
+		r := recipeFromJSONLD(obj)
+		if r.isComplete() {
+			found = r
+			return false
+		}
+		return true
+	})
+	return found, found != nil
+}
+
+// findRecipeObject walks arrays, @graph containers and nested objects until it finds a Recipe node.
+func findRecipeObject(v interface{}) map[string]interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			if obj := findRecipeObject(item); obj != nil {
+				return obj
+			}
+		}
+	case map[string]interface{}:
+		if isRecipeType(t["@type"]) {
+			return t
+		}
+		for _, key := range []string{"@graph", "mainEntity", "mainEntityOfPage"} {
+			if obj := findRecipeObject(t[key]); obj != nil {
+				return obj
+			}
+		}
+	}
+	return nil
+}
+
+func isRecipeType(v interface{}) bool {
+	for _, t := range jsonStrings(v) {
+		if schemaTypeName(t) == "Recipe" {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaTypeName strips the vocabulary prefix from types like "http://schema.org/Recipe" or "schema:Recipe".
+func schemaTypeName(t string) string {
+	if i := strings.LastIndexAny(t, "/:"); i >= 0 {
+		return t[i+1:]
+	}
+	return t
+}
+
+func recipeFromJSONLD(obj map[string]interface{}) *ExtractedRecipe {
+	r := &ExtractedRecipe{
+		Title:       cleanText(firstNonEmpty(jsonStrings(obj["name"]))),
+		Ingredients: cleanAll(jsonStrings(obj["recipeIngredient"])),
+		Steps:       instructionSteps(obj["recipeInstructions"]),
+		Servings:    pickYield(jsonStrings(obj["recipeYield"])),
+		Images:      jsonImageURLs(obj["image"]),
+	}
+	if len(r.Ingredients) == 0 {
+		// Older markup used "ingredients" before recipeIngredient existed
+		r.Ingredients = cleanAll(jsonStrings(obj["ingredients"]))
+	}
+
+	r.setTimes(
+		firstNonEmpty(jsonStrings(obj["prepTime"])),
+		firstNonEmpty(jsonStrings(obj["cookTime"])),
+		firstNonEmpty(jsonStrings(obj["totalTime"])),
+	)
+
+	if nutrition, ok := obj["nutrition"].(map[string]interface{}); ok {
+		for _, f := range nutritionFields {
+			if value := cleanText(firstNonEmpty(jsonStrings(nutrition[f.Property]))); value != "" {
+				r.Nutrition = append(r.Nutrition, NutritionFact{Label: f.Label, Value: value})
+			}
+		}
+	}
+
+	return r
+}
+
+// instructionSteps flattens the many shapes recipeInstructions takes in the wild:
+// a single text block, a list of strings, HowToStep objects, or HowToSection/ItemList groups.
+func instructionSteps(v interface{}) []string {
+	var steps []string
+	switch t := v.(type) {
+	case string:
+		for _, line := range strings.Split(htmlTagRe.ReplaceAllString(t, "\n"), "\n") {
+			if step := cleanText(line); step != "" {
+				steps = append(steps, step)
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			steps = append(steps, instructionSteps(item)...)
+		}
+	case map[string]interface{}:
+		if items, ok := t["itemListElement"]; ok {
+			return instructionSteps(items)
+		}
+		text := firstNonEmpty(jsonStrings(t["text"]))
+		if text == "" {
+			text = firstNonEmpty(jsonStrings(t["name"]))
+		}
+		if step := cleanText(text); step != "" {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// jsonStrings returns the string values of a JSON-LD property, which can be a
+// scalar, an array, or an object carrying the value in @value.
+func jsonStrings(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case float64:
+		return []string{strconv.FormatFloat(t, 'f', -1, 64)}
+	case []interface{}:
+		var out []string
+		for _, item := range t {
+			out = append(out, jsonStrings(item)...)
+		}
+		return out
+	case map[string]interface{}:
+		return jsonStrings(t["@value"])
+	}
+	return nil
+}
+
+// jsonImageURLs accepts a URL, an ImageObject, or a list of either.
+func jsonImageURLs(v interface{}) []string {
+	var urls []string
+	switch t := v.(type) {
+	case string:
+		urls = append(urls, t)
+	case []interface{}:
+		for _, item := range t {
+			urls = append(urls, jsonImageURLs(item)...)
+		}
+	case map[string]interface{}:
+		if u := firstNonEmpty(jsonStrings(t["url"])); u != "" {
+			urls = append(urls, u)
+		} else if u := firstNonEmpty(jsonStrings(t["contentUrl"])); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return dedupe(urls)
+}
+
+// --- Microdata ---
+
+func extractMicrodataRecipe(doc *goquery.Document) (*ExtractedRecipe, bool) {
+	scope := doc.Find("[itemscope][itemtype]").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		for _, t := range strings.Fields(s.AttrOr("itemtype", "")) {
+			if schemaTypeName(t) == "Recipe" {
+				return true
+			}
+		}
+		return false
+	}).First()
+	if scope.Length() == 0 {
+		return nil, false
+	}
+
+	r := &ExtractedRecipe{
+		Title:       cleanText(firstNonEmpty(itemValues(scope, "name"))),
+		Ingredients: cleanAll(itemValues(scope, "recipeIngredient")),
+		Servings:    pickYield(itemValues(scope, "recipeYield")),
+		Images:      dedupe(itemValues(scope, "image")),
+	}
+	if len(r.Ingredients) == 0 {
+		r.Ingredients = cleanAll(itemValues(scope, "ingredients"))
+	}
+
+	itemProps(scope, "recipeInstructions").Each(func(_ int, s *goquery.Selection) {
+		r.Steps = append(r.Steps, microdataSteps(s)...)
+	})
+
+	r.setTimes(
+		firstNonEmpty(itemValues(scope, "prepTime")),
+		firstNonEmpty(itemValues(scope, "cookTime")),
+		firstNonEmpty(itemValues(scope, "totalTime")),
+	)
+
+	nutrition := itemProps(scope, "nutrition").First()
+	if nutrition.Length() > 0 {
+		for _, f := range nutritionFields {
+			if value := cleanText(firstNonEmpty(itemValues(nutrition, f.Property))); value != "" {
+				r.Nutrition = append(r.Nutrition, NutritionFact{Label: f.Label, Value: value})
+			}
+		}
+	}
+
+	return r, r.isComplete()
+}
+
+// microdataSteps reads one recipeInstructions element, which can be a nested
+// HowToStep scope, a list, or a block of text.
+func microdataSteps(s *goquery.Selection) []string {
+	if _, ok := s.Attr("itemscope"); ok {
+		if text := itemValues(s, "text"); len(text) > 0 {
+			return cleanAll(text)
+		}
+	}
+
+	var steps []string
+	if items := s.Find("li"); items.Length() > 0 {
+		items.Each(func(_ int, li *goquery.Selection) {
+			if step := cleanText(li.Text()); step != "" {
+				steps = append(steps, step)
+			}
+		})
+		return steps
+	}
+
+	if paragraphs := s.Find("p"); paragraphs.Length() > 0 {
+		paragraphs.Each(func(_ int, p *goquery.Selection) {
+			if step := cleanText(p.Text()); step != "" {
+				steps = append(steps, step)
+			}
+		})
+		return steps
+	}
+
+	if step := cleanText(itemValue(s)); step != "" {
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// itemProps returns the elements carrying the given itemprop that belong directly to
+// scope, skipping properties of nested item scopes.
+func itemProps(scope *goquery.Selection, name string) *goquery.Selection {
+	owner := scope.Get(0)
+	return scope.Find("[itemprop]").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		found := false
+		for _, p := range strings.Fields(s.AttrOr("itemprop", "")) {
+			if p == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+		parentScope := s.Parent().Closest("[itemscope]")
+		return parentScope.Length() > 0 && parentScope.Get(0) == owner
+	})
+}
+
+func itemValues(scope *goquery.Selection, name string) []string {
+	var values []string
+	itemProps(scope, name).Each(func(_ int, s *goquery.Selection) {
+		if v := strings.TrimSpace(itemValue(s)); v != "" {
+			values = append(values, v)
+		}
+	})
+	return values
+}
+
+// itemValue follows the microdata rules for where an element keeps its value.
+func itemValue(s *goquery.Selection) string {
+	if content, ok := s.Attr("content"); ok {
+		return content
+	}
+	switch goquery.NodeName(s) {
+	case "img", "source", "video", "audio":
+		return s.AttrOr("src", "")
+	case "a", "link":
+		return s.AttrOr("href", "")
+	case "time":
+		if dt, ok := s.Attr("datetime"); ok {
+			return dt
+		}
+	case "data", "meter":
+		return s.AttrOr("value", "")
+	}
+	return s.Text()
+}
+
+// --- Shared helpers ---
+
+// setTimes fills the duration fields from ISO-8601 values. PrepTime keeps the
+// overall time, which is what the rest of the app treats as "prep time".
+func (r *ExtractedRecipe) setTimes(prep, cook, total string) {
+	r.PrepMinutes = isoMinutes(prep)
+	r.CookMinutes = isoMinutes(cook)
+	r.TotalMinutes = isoMinutes(total)
+	if r.TotalMinutes == 0 {
+		r.TotalMinutes = r.PrepMinutes + r.CookMinutes
+	}
+	if r.TotalMinutes > 0 {
+		r.PrepTime = formatMinutes(r.TotalMinutes)
+	}
+}
+
+// parseISODuration parses ISO-8601 durations such as "PT1H30M" or "P0DT45M".
+func parseISODuration(s string) (time.Duration, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	m := isoDurationRe.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, false
+	}
+
+	var d time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, false
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[4] != "" {
+		secs, err := strconv.ParseFloat(m[4], 64)
+		if err != nil {
+			return 0, false
+		}
+		d += time.Duration(secs * float64(time.Second))
+	}
+	return d, true
+}
+
+func isoMinutes(s string) int {
+	d, ok := parseISODuration(s)
+	if !ok {
+		return 0
+	}
+	return int(d.Round(time.Minute) / time.Minute)
+}
+
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%d mins", minutes)
+	}
+	h, m := minutes/60, minutes%60
+	if m == 0 {
+		return fmt.Sprintf("%d h", h)
+	}
+	return fmt.Sprintf("%d h %d mins", h, m)
+}
+
+// pickYield prefers a descriptive yield ("4 servings") over a bare number,
+// since many sites publish both.
+func pickYield(values []string) string {
+	values = cleanAll(values)
+	for _, v := range values {
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return v
+		}
+	}
+	return firstNonEmpty(values)
+}
+
+// cleanText unescapes entities, drops inline markup and collapses whitespace.
+func cleanText(s string) string {
+	s = html.UnescapeString(htmlTagRe.ReplaceAllString(s, " "))
+	s = strings.Join(strings.Fields(s), " ")
+	// Removing tags like </b> leaves a gap before the punctuation that followed them
+	return spaceBeforeRe.ReplaceAllString(s, "$1")
+}
+
+func cleanAll(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if c := cleanText(v); c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func firstNonEmpty(values []string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	var out []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,187 @@
+package clipper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-meal-planner/internal/llm/llmtest"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const jsonLDPage = `
+<html>
+	<head>
+		<script type="application/ld+json">
+		{
+			"@context": "https://schema.org",
+			"@graph": [
+				{"@type": "WebPage", "name": "Blog"},
+				{
+					"@type": ["Recipe", "NewsArticle"],
+					"name": "Feijão &amp; Arroz",
+					"image": [{"@type": "ImageObject", "url": "https://img.test/a.jpg"}, "https://img.test/a.jpg", "https://img.test/b.jpg"],
+					"recipeYield": ["4", "4 servings"],
+					"prepTime": "PT15M",
+					"cookTime": "PT1H",
+					"recipeIngredient": ["2 cups rice", " 1 cup  beans "],
+					"recipeInstructions": [
+						{"@type": "HowToSection", "name": "Beans", "itemListElement": [
+							{"@type": "HowToStep", "text": "Soak the beans."},
+							{"@type": "HowToStep", "text": "Cook the <b>beans</b>."}
+						]},
+						{"@type": "HowToStep", "text": "Cook the rice."}
+					],
+					"nutrition": {"@type": "NutritionInformation", "calories": "320 kcal", "proteinContent": "12 g"}
+				}
+			]
+		}
+		</script>
+	</head>
+	<body><p>Long story about beans.</p></body>
+</html>`
+
+const microdataPage = `
+<html><body>
+	<div itemscope itemtype="http://schema.org/Recipe">
+		<h1 itemprop="name">Pão de Queijo</h1>
+		<img itemprop="image" src="https://img.test/pao.jpg">
+		<meta itemprop="totalTime" content="PT40M">
+		<span itemprop="recipeYield">20 pieces</span>
+		<ul>
+			<li itemprop="recipeIngredient">500 g tapioca flour</li>
+			<li itemprop="recipeIngredient">2 eggs</li>
+		</ul>
+		<div itemprop="author" itemscope itemtype="http://schema.org/Person">
+			<span itemprop="name">Someone Else</span>
+		</div>
+		<ol itemprop="recipeInstructions">
+			<li>Mix everything.</li>
+			<li>Bake for 25 minutes.</li>
+		</ol>
+		<div itemprop="nutrition" itemscope itemtype="http://schema.org/NutritionInformation">
+			<span itemprop="calories">90 kcal</span>
+		</div>
+	</div>
+</body></html>`
+
+func parseDoc(t *testing.T, page string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("parse html: %v", err)
+	}
+	return doc
+}
+
+func TestExtractStructuredRecipe_JSONLD(t *testing.T) {
+	r, ok := extractStructuredRecipe(parseDoc(t, jsonLDPage))
+	if !ok {
+		t.Fatal("expected structured recipe to be found")
+	}
+
+	if r.Title != "Feijão & Arroz" {
+		t.Errorf("Title = %q", r.Title)
+	}
+	if strings.Join(r.Ingredients, "|") != "2 cups rice|1 cup beans" {
+		t.Errorf("Ingredients = %#v", r.Ingredients)
+	}
+	if strings.Join(r.Steps, "|") != "Soak the beans.|Cook the beans.|Cook the rice." {
+		t.Errorf("Steps = %#v", r.Steps)
+	}
+	if r.Servings != "4 servings" {
+		t.Errorf("Servings = %q", r.Servings)
+	}
+	if r.PrepMinutes != 15 || r.CookMinutes != 60 || r.TotalMinutes != 75 || r.PrepTime != "1 h 15 mins" {
+		t.Errorf("times = %d/%d/%d %q", r.PrepMinutes, r.CookMinutes, r.TotalMinutes, r.PrepTime)
+	}
+	if strings.Join(r.Images, "|") != "https://img.test/a.jpg|https://img.test/b.jpg" {
+		t.Errorf("Images = %#v", r.Images)
+	}
+	if len(r.Nutrition) != 2 || r.Nutrition[0] != (NutritionFact{Label: "Calories", Value: "320 kcal"}) {
+		t.Errorf("Nutrition = %#v", r.Nutrition)
+	}
+}
+
+func TestExtractStructuredRecipe_Microdata(t *testing.T) {
+	r, ok := extractStructuredRecipe(parseDoc(t, microdataPage))
+	if !ok {
+		t.Fatal("expected structured recipe to be found")
+	}
+
+	if r.Title != "Pão de Queijo" {
+		t.Errorf("Title = %q, nested author name must not leak in", r.Title)
+	}
+	if len(r.Ingredients) != 2 || len(r.Steps) != 2 {
+		t.Errorf("Ingredients = %#v, Steps = %#v", r.Ingredients, r.Steps)
+	}
+	if r.TotalMinutes != 40 || r.PrepTime != "40 mins" {
+		t.Errorf("TotalMinutes = %d, PrepTime = %q", r.TotalMinutes, r.PrepTime)
+	}
+	if r.Servings != "20 pieces" || len(r.Images) != 1 {
+		t.Errorf("Servings = %q, Images = %#v", r.Servings, r.Images)
+	}
+	if len(r.Nutrition) != 1 || r.Nutrition[0].Value != "90 kcal" {
+		t.Errorf("Nutrition = %#v", r.Nutrition)
+	}
+}
+
+func TestExtractStructuredRecipe_NoData(t *testing.T) {
+	if _, ok := extractStructuredRecipe(parseDoc(t, "<html><body><p>Just text</p></body></html>")); ok {
+		t.Error("expected no structured recipe")
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"PT30M", 30 * time.Minute, true},
+		{"PT1H30M", 90 * time.Minute, true},
+		{"P0DT2H", 2 * time.Hour, true},
+		{"P1D", 24 * time.Hour, true},
+		{"pt45s", 45 * time.Second, true},
+		{"PT", 0, false},
+		{"30 mins", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseISODuration(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseISODuration(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExtractURL_UsesStructuredDataWithoutLLM(t *testing.T) {
+	mockGhost := &MockGhostClient{}
+	c := NewClipper(mockGhost, &llmtest.MockTextGenerator{ShouldError: true}, nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(jsonLDPage))
+	}))
+	defer ts.Close()
+
+	extracted, err := c.ExtractURL(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatalf("ExtractURL failed: %v", err)
+	}
+	post, err := c.Publish(context.Background(), *extracted, ts.URL, nil, true)
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if post.Title != "Feijão & Arroz" {
+		t.Errorf("Title = %q", post.Title)
+	}
+	for _, sub := range []string{"<li>2 cups rice</li>", "<img src=\"https://img.test/a.jpg\"", "<strong>Calories:</strong> 320 kcal"} {
+		if !strings.Contains(mockGhost.CreatedPost.HTML, sub) {
+			t.Errorf("expected HTML to contain %q", sub)
+		}
+	}
+}
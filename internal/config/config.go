@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 )
 
@@ -12,6 +13,18 @@ const (
 	DefaultChefModel       = "openai/gpt-oss-20b"
 	DefaultNormalizerModel = DefaultChefModel
 	DefaultTaggerModel     = "qwen/qwen3.6-27b"
+	DefaultVisionModel     = "meta-llama/llama-4-scout-17b-16e-instruct"
+	DefaultIntentModel     = DefaultChefModel
+)
+
+// webhookSecretRe is what Telegram accepts as a webhook secret token.
+var webhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// How the Telegram bot receives updates: Telegram pushes them to the webhook, or the
+// bot long-polls for them, which needs no public URL.
+const (
+	TelegramModeWebhook = "webhook"
+	TelegramModePolling = "polling"
 )
 
 // Config holds the configuration for the application.
@@ -26,15 +39,28 @@ type Config struct {
 	ChefModel       string
 	NormalizerModel string
 	TaggerModel     string
+	VisionModel     string
+	IntentModel     string
 
 	// Telegram Config
 	TelegramBotToken       string
 	TelegramWebhookURL     string
 	TelegramAllowedUserIDs []int64
 	AdminTelegramID        int64
+	TelegramMode           string
+	// TelegramWebhookSecret is the token Telegram sends back in every webhook request;
+	// empty derives one from the bot token.
+	TelegramWebhookSecret string
+	// TelegramAPIEndpoint overrides the Bot API URL format, e.g. for a local fake server;
+	// empty uses api.telegram.org.
+	TelegramAPIEndpoint string
 
 	DatabasePath string
 
+	// DefaultTimezone is the IANA timezone for reminders of users who did not pick one;
+	// empty means the server's local time.
+	DefaultTimezone string
+
 	// Defaults for Planning
 	DefaultAdults           int
 	DefaultChildren         int
@@ -73,6 +99,14 @@ func NewFromEnv() (*Config, error) {
 	// Telegram Config (Optional for CLI, required for Bot)
 	telegramBotToken := os.Getenv("TELEGRAM_BOT_TOKEN")
 	telegramWebhookURL := os.Getenv("TELEGRAM_WEBHOOK_URL")
+	telegramMode := strings.ToLower(envOrDefault("TELEGRAM_MODE", TelegramModeWebhook))
+	if telegramMode != TelegramModeWebhook && telegramMode != TelegramModePolling {
+		return nil, fmt.Errorf("TELEGRAM_MODE must be %q or %q, got %q", TelegramModeWebhook, TelegramModePolling, telegramMode)
+	}
+	webhookSecret := strings.TrimSpace(os.Getenv("TELEGRAM_WEBHOOK_SECRET"))
+	if webhookSecret != "" && !webhookSecretRe.MatchString(webhookSecret) {
+		return nil, fmt.Errorf("TELEGRAM_WEBHOOK_SECRET must be 1-256 letters, digits, '_' or '-'")
+	}
 
 	var allowedIDs []int64
 	idsStr := os.Getenv("TELEGRAM_ALLOWED_USER_IDS")
@@ -147,11 +181,17 @@ func NewFromEnv() (*Config, error) {
 		ChefModel:               envOrDefault("GROQ_CHEF_MODEL", DefaultChefModel),
 		NormalizerModel:         envOrDefault("GROQ_NORMALIZER_MODEL", DefaultNormalizerModel),
 		TaggerModel:             envOrDefault("GROQ_TAGGER_MODEL", DefaultTaggerModel),
+		VisionModel:             envOrDefault("GROQ_VISION_MODEL", DefaultVisionModel),
+		IntentModel:             envOrDefault("GROQ_INTENT_MODEL", DefaultIntentModel),
 		TelegramBotToken:        telegramBotToken,
 		TelegramWebhookURL:      telegramWebhookURL,
 		TelegramAllowedUserIDs:  allowedIDs,
 		AdminTelegramID:         adminID,
+		TelegramMode:            telegramMode,
+		TelegramWebhookSecret:   webhookSecret,
+		TelegramAPIEndpoint:     strings.TrimSpace(os.Getenv("TELEGRAM_API_ENDPOINT")),
 		DatabasePath:            databasePath,
+		DefaultTimezone:         strings.TrimSpace(os.Getenv("DEFAULT_TIMEZONE")),
 		DefaultAdults:           defaultAdults,
 		DefaultChildren:         defaultChildren,
 		DefaultChildrenAges:     defaultAges,
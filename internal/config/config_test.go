@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -36,7 +37,7 @@ func TestNewFromEnv(t *testing.T) {
 		}
 		if cfg.AnalystModel != DefaultAnalystModel || cfg.ReviewerModel != DefaultReviewerModel ||
 			cfg.ChefModel != DefaultChefModel || cfg.NormalizerModel != DefaultNormalizerModel ||
-			cfg.TaggerModel != DefaultTaggerModel {
+			cfg.TaggerModel != DefaultTaggerModel || cfg.VisionModel != DefaultVisionModel {
 			t.Fatalf("model defaults were not applied: %#v", cfg)
 		}
 	})
@@ -97,4 +98,49 @@ func TestNewFromEnv(t *testing.T) {
 			t.Errorf("Expected error '%s', got '%s'", expectedError, err.Error())
 		}
 	})
+
+	t.Run("TelegramMode", func(t *testing.T) {
+		setEnv("GHOST_API_URL", "http://ghost.test")
+		setEnv("GHOST_CONTENT_API_KEY", "ghost_key")
+		setEnv("EMBEDDING_API_KEY", "embed_key")
+		setEnv("GROQ_API_KEY", "groq_key")
+
+		cfg, err := NewFromEnv()
+		if err != nil {
+			t.Fatalf("NewFromEnv() error = %v", err)
+		}
+		if cfg.TelegramMode != TelegramModeWebhook {
+			t.Errorf("TelegramMode = %q, want %q by default", cfg.TelegramMode, TelegramModeWebhook)
+		}
+
+		setEnv("TELEGRAM_MODE", "Polling")
+		if cfg, err := NewFromEnv(); err != nil || cfg.TelegramMode != TelegramModePolling {
+			t.Errorf("NewFromEnv() = %v, %v; want polling mode", cfg, err)
+		}
+
+		setEnv("TELEGRAM_MODE", "carrier-pigeon")
+		if _, err := NewFromEnv(); err == nil {
+			t.Error("NewFromEnv() accepted an unknown TELEGRAM_MODE")
+		}
+	})
+
+	t.Run("TelegramWebhookSecret", func(t *testing.T) {
+		setEnv("GHOST_API_URL", "http://ghost.test")
+		setEnv("GHOST_CONTENT_API_KEY", "ghost_key")
+		setEnv("EMBEDDING_API_KEY", "embed_key")
+		setEnv("GROQ_API_KEY", "groq_key")
+		setEnv("TELEGRAM_MODE", "")
+
+		setEnv("TELEGRAM_WEBHOOK_SECRET", "s3cret_token-1")
+		if cfg, err := NewFromEnv(); err != nil || cfg.TelegramWebhookSecret != "s3cret_token-1" {
+			t.Errorf("NewFromEnv() = %v, %v; want the secret kept", cfg, err)
+		}
+
+		for _, secret := range []string{"not valid", "tok:en", strings.Repeat("a", 257)} {
+			setEnv("TELEGRAM_WEBHOOK_SECRET", secret)
+			if _, err := NewFromEnv(); err == nil {
+				t.Errorf("NewFromEnv() accepted TELEGRAM_WEBHOOK_SECRET %q", secret)
+			}
+		}
+	})
 }
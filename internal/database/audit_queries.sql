@@ -5,3 +5,13 @@ INSERT INTO audit_logs (
 
 -- name: CleanupAuditLogs :exec
 DELETE FROM audit_logs WHERE created_at < ?;
+
+-- name: ListAuditLogsByPlan :many
+SELECT id, user_id, plan_id, action_type, original_request, user_feedback, previous_state, new_state, created_at FROM audit_logs
+WHERE plan_id = ?
+ORDER BY id DESC
+LIMIT ?;
+
+-- name: GetAuditLog :one
+SELECT id, user_id, plan_id, action_type, original_request, user_feedback, previous_state, new_state, created_at FROM audit_logs
+WHERE id = ?;
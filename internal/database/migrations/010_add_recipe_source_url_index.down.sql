@@ -0,0 +1,2 @@
+-- 010_add_recipe_source_url_index.down.sql
+DROP INDEX idx_recipes_source_url;
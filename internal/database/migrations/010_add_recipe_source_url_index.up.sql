@@ -0,0 +1,4 @@
+-- 010_add_recipe_source_url_index.up.sql
+-- Clipped recipes keep their source page in the JSON document; index it so
+-- re-clipping the same page can be detected without scanning every recipe.
+CREATE INDEX idx_recipes_source_url ON recipes(json_extract(data, '$.source_url'));
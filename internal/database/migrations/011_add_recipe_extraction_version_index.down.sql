@@ -0,0 +1,2 @@
+-- 011_add_recipe_extraction_version_index.down.sql
+DROP INDEX idx_recipes_extraction_version;
@@ -0,0 +1,5 @@
+-- 011_add_recipe_extraction_version_index.up.sql
+-- Recipes normalized before steps, minutes, yield, cuisine, equipment and images
+-- were extracted have no extraction_version. Index it so the backfill command
+-- (`ai-meal-planner backfill`) can find and re-extract them.
+CREATE INDEX idx_recipes_extraction_version ON recipes(COALESCE(json_extract(data, '$.extraction_version'), 0));
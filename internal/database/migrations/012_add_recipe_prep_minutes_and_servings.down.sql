@@ -0,0 +1,5 @@
+-- 012_add_recipe_prep_minutes_and_servings.down.sql
+DROP INDEX idx_recipes_servings;
+DROP INDEX idx_recipes_prep_minutes;
+ALTER TABLE recipes DROP COLUMN servings;
+ALTER TABLE recipes DROP COLUMN prep_minutes;
@@ -0,0 +1,8 @@
+-- 012_add_recipe_prep_minutes_and_servings.up.sql
+-- Expose the numeric prep time and servings from the JSON document as indexed
+-- columns so search can filter on them in SQL. Recipes extracted before these
+-- fields existed stay NULL until `ai-meal-planner backfill` re-extracts them.
+ALTER TABLE recipes ADD COLUMN prep_minutes INTEGER GENERATED ALWAYS AS (json_extract(data, '$.total_minutes')) VIRTUAL;
+ALTER TABLE recipes ADD COLUMN servings INTEGER GENERATED ALWAYS AS (json_extract(data, '$.yield')) VIRTUAL;
+CREATE INDEX idx_recipes_prep_minutes ON recipes(prep_minutes);
+CREATE INDEX idx_recipes_servings ON recipes(servings);
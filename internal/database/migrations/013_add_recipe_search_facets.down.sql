@@ -0,0 +1,7 @@
+-- 013_add_recipe_search_facets.down.sql
+DROP INDEX idx_recipes_main_protein;
+DROP INDEX idx_recipes_meal_type;
+DROP INDEX idx_recipes_cuisine;
+ALTER TABLE recipes DROP COLUMN main_protein;
+ALTER TABLE recipes DROP COLUMN meal_type;
+ALTER TABLE recipes DROP COLUMN cuisine;
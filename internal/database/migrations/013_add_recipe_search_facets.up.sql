@@ -0,0 +1,10 @@
+-- 013_add_recipe_search_facets.up.sql
+-- Expose cuisine, meal type and main protein as indexed columns so search can
+-- pre-filter on them in SQL. Recipes extracted before meal type and main protein
+-- existed stay NULL until `ai-meal-planner backfill` re-extracts them.
+ALTER TABLE recipes ADD COLUMN cuisine TEXT GENERATED ALWAYS AS (lower(json_extract(data, '$.cuisine'))) VIRTUAL;
+ALTER TABLE recipes ADD COLUMN meal_type TEXT GENERATED ALWAYS AS (lower(json_extract(data, '$.meal_type'))) VIRTUAL;
+ALTER TABLE recipes ADD COLUMN main_protein TEXT GENERATED ALWAYS AS (lower(json_extract(data, '$.main_protein'))) VIRTUAL;
+CREATE INDEX idx_recipes_cuisine ON recipes(cuisine);
+CREATE INDEX idx_recipes_meal_type ON recipes(meal_type);
+CREATE INDEX idx_recipes_main_protein ON recipes(main_protein);
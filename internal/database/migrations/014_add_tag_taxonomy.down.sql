@@ -0,0 +1,6 @@
+-- 014_add_tag_taxonomy.down.sql
+DROP TABLE IF EXISTS tag_suggestions;
+DROP INDEX IF EXISTS idx_tag_synonyms_tag;
+DROP TABLE IF EXISTS tag_synonyms;
+DROP INDEX IF EXISTS idx_tag_taxonomy_parent;
+DROP TABLE IF EXISTS tag_taxonomy;
@@ -0,0 +1,135 @@
+-- 014_add_tag_taxonomy.up.sql
+-- Managed tag vocabulary. Every canonical tag is English, has a Brazilian
+-- Portuguese label, a category and an optional parent tag, so searching for
+-- "poultry" also finds recipes tagged "chicken" or "frango".
+CREATE TABLE IF NOT EXISTS tag_taxonomy (
+    tag TEXT PRIMARY KEY NOT NULL,
+    label_pt TEXT NOT NULL,
+    category TEXT NOT NULL,
+    parent TEXT,
+    FOREIGN KEY (parent) REFERENCES tag_taxonomy(tag) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_tag_taxonomy_parent ON tag_taxonomy(parent);
+
+-- Every spelling that maps to a canonical tag, in both languages, including the tag itself.
+CREATE TABLE IF NOT EXISTS tag_synonyms (
+    synonym TEXT PRIMARY KEY NOT NULL,
+    tag TEXT NOT NULL,
+    FOREIGN KEY (tag) REFERENCES tag_taxonomy(tag) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_tag_synonyms_tag ON tag_synonyms(tag);
+
+-- Tags proposed by the tagger that are not in the taxonomy yet, waiting for review.
+CREATE TABLE IF NOT EXISTS tag_suggestions (
+    tag TEXT PRIMARY KEY NOT NULL,
+    label_pt TEXT NOT NULL,
+    recipe_id TEXT NOT NULL,
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Parents first so children can reference them
+INSERT INTO tag_taxonomy (tag, label_pt, category, parent) VALUES
+    ('poultry', 'aves', 'protein', NULL),
+    ('meat', 'carne', 'protein', NULL),
+    ('seafood', 'frutos do mar', 'protein', NULL),
+    ('legumes', 'leguminosas', 'protein', NULL),
+    ('eggs', 'ovos', 'protein', NULL),
+    ('tofu', 'tofu', 'protein', NULL),
+    ('dairy', 'laticínios', 'ingredient', NULL),
+    ('vegetables', 'vegetais', 'ingredient', NULL),
+    ('pasta', 'massa', 'ingredient', NULL),
+    ('rice', 'arroz', 'ingredient', NULL),
+    ('vegetarian', 'vegetariano', 'diet', NULL),
+    ('gluten free', 'sem glúten', 'diet', NULL),
+    ('lactose free', 'sem lactose', 'diet', NULL),
+    ('low carb', 'low carb', 'diet', NULL),
+    ('breakfast', 'café da manhã', 'meal-type', NULL),
+    ('lunch', 'almoço', 'meal-type', NULL),
+    ('dinner', 'jantar', 'meal-type', NULL),
+    ('dessert', 'sobremesa', 'meal-type', NULL),
+    ('snack', 'lanche', 'meal-type', NULL),
+    ('side dish', 'acompanhamento', 'meal-type', NULL),
+    ('soup', 'sopa', 'meal-type', NULL),
+    ('salad', 'salada', 'meal-type', NULL),
+    ('brazilian', 'brasileira', 'cuisine', NULL),
+    ('italian', 'italiana', 'cuisine', NULL),
+    ('japanese', 'japonesa', 'cuisine', NULL),
+    ('mexican', 'mexicana', 'cuisine', NULL),
+    ('indian', 'indiana', 'cuisine', NULL),
+    ('chinese', 'chinesa', 'cuisine', NULL),
+    ('french', 'francesa', 'cuisine', NULL),
+    ('arabic', 'árabe', 'cuisine', NULL),
+    ('portuguese', 'portuguesa', 'cuisine', NULL),
+    ('air fryer', 'fritadeira sem óleo', 'technique', NULL),
+    ('oven', 'forno', 'technique', NULL),
+    ('pressure cooker', 'panela de pressão', 'technique', NULL),
+    ('grilled', 'grelhado', 'technique', NULL),
+    ('quick', 'rápido', 'technique', NULL);
+
+INSERT INTO tag_taxonomy (tag, label_pt, category, parent) VALUES
+    ('chicken', 'frango', 'protein', 'poultry'),
+    ('turkey', 'peru', 'protein', 'poultry'),
+    ('beef', 'carne bovina', 'protein', 'meat'),
+    ('pork', 'carne suína', 'protein', 'meat'),
+    ('lamb', 'cordeiro', 'protein', 'meat'),
+    ('fish', 'peixe', 'protein', 'seafood'),
+    ('shellfish', 'mariscos', 'protein', 'seafood'),
+    ('beans', 'feijão', 'protein', 'legumes'),
+    ('lentils', 'lentilha', 'protein', 'legumes'),
+    ('chickpeas', 'grão-de-bico', 'protein', 'legumes'),
+    ('cheese', 'queijo', 'ingredient', 'dairy'),
+    ('broccoli', 'brócolis', 'ingredient', 'vegetables'),
+    ('potato', 'batata', 'ingredient', 'vegetables'),
+    ('vegan', 'vegano', 'diet', 'vegetarian');
+
+INSERT INTO tag_taxonomy (tag, label_pt, category, parent) VALUES
+    ('salmon', 'salmão', 'protein', 'fish'),
+    ('tuna', 'atum', 'protein', 'fish'),
+    ('cod', 'bacalhau', 'protein', 'fish'),
+    ('tilapia', 'tilápia', 'protein', 'fish'),
+    ('shrimp', 'camarão', 'protein', 'shellfish');
+
+INSERT OR IGNORE INTO tag_synonyms (synonym, tag) SELECT tag, tag FROM tag_taxonomy;
+INSERT OR IGNORE INTO tag_synonyms (synonym, tag) SELECT label_pt, tag FROM tag_taxonomy;
+INSERT OR IGNORE INTO tag_synonyms (synonym, tag) VALUES
+    ('ave', 'poultry'),
+    ('red meat', 'meat'),
+    ('carne vermelha', 'meat'),
+    ('chicken breast', 'chicken'),
+    ('peito de frango', 'chicken'),
+    ('chicken thigh', 'chicken'),
+    ('sobrecoxa', 'chicken'),
+    ('ground beef', 'beef'),
+    ('carne moída', 'beef'),
+    ('steak', 'beef'),
+    ('bife', 'beef'),
+    ('porco', 'pork'),
+    ('carne de porco', 'pork'),
+    ('bacon', 'pork'),
+    ('peixes', 'fish'),
+    ('prawn', 'shrimp'),
+    ('prawns', 'shrimp'),
+    ('camarões', 'shrimp'),
+    ('egg', 'eggs'),
+    ('ovo', 'eggs'),
+    ('bean', 'beans'),
+    ('lentil', 'lentils'),
+    ('chickpea', 'chickpeas'),
+    ('grão de bico', 'chickpeas'),
+    ('gluten-free', 'gluten free'),
+    ('sem gluten', 'gluten free'),
+    ('lactose-free', 'lactose free'),
+    ('low-carb', 'low carb'),
+    ('baixo carboidrato', 'low carb'),
+    ('cafe da manha', 'breakfast'),
+    ('side', 'side dish'),
+    ('guarnição', 'side dish'),
+    ('brasileiro', 'brazilian'),
+    ('italiano', 'italian'),
+    ('japonês', 'japanese'),
+    ('mexicano', 'mexican'),
+    ('airfryer', 'air fryer'),
+    ('grelhados', 'grilled'),
+    ('grill', 'grilled'),
+    ('rápida', 'quick');
@@ -0,0 +1,3 @@
+-- 015_add_recipe_ingredients.down.sql
+DROP INDEX IF EXISTS idx_recipe_ingredients_ingredient;
+DROP TABLE IF EXISTS recipe_ingredients;
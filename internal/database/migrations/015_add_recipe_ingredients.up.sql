@@ -0,0 +1,11 @@
+-- 015_add_recipe_ingredients.up.sql
+-- Index the normalized ingredient names of each recipe, in Portuguese and English, so
+-- searches can exclude ingredients that are not reflected in the tags. Recipes extracted
+-- before ingredient names existed have no rows until `ai-meal-planner backfill` re-extracts them.
+CREATE TABLE recipe_ingredients (
+    recipe_id TEXT NOT NULL,
+    ingredient TEXT NOT NULL,
+    PRIMARY KEY (recipe_id, ingredient),
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_recipe_ingredients_ingredient ON recipe_ingredients(ingredient);
@@ -0,0 +1,3 @@
+-- 016_add_recipe_ratings.down.sql
+DROP TABLE IF EXISTS rating_prompts;
+DROP TABLE IF EXISTS recipe_ratings;
@@ -0,0 +1,23 @@
+-- 016_add_recipe_ratings.up.sql
+-- Per-user ratings of cooked recipes, collected the day after a Cook slot, and the
+-- prompts already sent so each plan day is only asked about once.
+CREATE TABLE recipe_ratings (
+    user_id TEXT NOT NULL,
+    recipe_id TEXT NOT NULL,
+    stars INTEGER NOT NULL DEFAULT 0,
+    comment TEXT NOT NULL DEFAULT '',
+    never_suggest BOOLEAN NOT NULL DEFAULT 0,
+    meal_plan_id INTEGER NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, recipe_id),
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE rating_prompts (
+    meal_plan_id INTEGER NOT NULL,
+    day_index INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (meal_plan_id, day_index),
+    FOREIGN KEY (meal_plan_id) REFERENCES user_meal_plans(id) ON DELETE CASCADE
+);
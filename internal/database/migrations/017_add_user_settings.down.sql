@@ -0,0 +1,2 @@
+-- 017_add_user_settings.down.sql
+DROP TABLE IF EXISTS user_settings;
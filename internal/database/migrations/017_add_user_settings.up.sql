@@ -0,0 +1,8 @@
+-- 017_add_user_settings.up.sql
+-- Per-user planning preferences. Users without a row get the planner defaults.
+CREATE TABLE user_settings (
+    user_id TEXT PRIMARY KEY,
+    repeat_window_weeks INTEGER NOT NULL,
+    favorite_repeat_weeks INTEGER NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,3 @@
+-- 018_add_plan_versions.down.sql
+DROP TABLE IF EXISTS meal_plan_versions;
+ALTER TABLE user_meal_plans DROP COLUMN current_version;
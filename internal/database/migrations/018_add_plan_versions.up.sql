@@ -0,0 +1,22 @@
+-- 018_add_plan_versions.up.sql
+-- A user_meal_plans row is now the plan for one user and week, holding its current
+-- version. Every generation, revision and restore adds a numbered version instead
+-- of a new plan row. Existing rows become version 1 of themselves; weeks that already
+-- have several rows keep them, and the newest one is treated as the week's plan.
+-- Foreign keys are not enforced, so versions are deleted by the queries that delete
+-- their plan rather than by a cascade.
+ALTER TABLE user_meal_plans ADD COLUMN current_version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE meal_plan_versions (
+    meal_plan_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    parent_version INTEGER,
+    author TEXT NOT NULL,
+    feedback TEXT NOT NULL DEFAULT '',
+    plan_data TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (meal_plan_id, version)
+);
+
+INSERT INTO meal_plan_versions (meal_plan_id, version, author, plan_data, created_at)
+SELECT id, 1, '', plan_data, created_at FROM user_meal_plans;
@@ -0,0 +1,3 @@
+-- 019_add_reminders.down.sql
+DROP TABLE IF EXISTS reminder_deliveries;
+DROP TABLE IF EXISTS reminder_settings;
@@ -0,0 +1,17 @@
+-- 019_add_reminders.up.sql
+-- Per-user reminder preferences. Users without a row get reminders in the default timezone.
+CREATE TABLE reminder_settings (
+    user_id TEXT PRIMARY KEY,
+    timezone TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- One row per reminder sent, keyed by the user's local date so restarts never send twice.
+CREATE TABLE reminder_deliveries (
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    local_date TEXT NOT NULL,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, kind, local_date)
+);
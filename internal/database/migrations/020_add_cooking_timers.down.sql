@@ -0,0 +1,2 @@
+-- 020_add_cooking_timers.down.sql
+DROP TABLE IF EXISTS cooking_timers;
@@ -0,0 +1,9 @@
+-- 020_add_cooking_timers.up.sql
+-- Timers started from cooking mode, kept until they ring so a restart does not lose them.
+CREATE TABLE cooking_timers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id INTEGER NOT NULL,
+    message TEXT NOT NULL,
+    due_at DATETIME NOT NULL
+);
+CREATE INDEX idx_cooking_timers_due_at ON cooking_timers(due_at);
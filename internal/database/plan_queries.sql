@@ -4,29 +4,97 @@ VALUES (?, ?, ?, ?, ?)
 RETURNING id;
 
 -- name: ListRecentMealPlansByUserID :many
-SELECT id, user_id, plan_data, week_start_date, status, created_at FROM user_meal_plans
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
 WHERE user_id = ?
 ORDER BY week_start_date DESC
 LIMIT ?;
 
+-- name: DeleteOldPlanVersionsByUserID :exec
+-- Run before DeleteOldMealPlansByUserID: nothing cascades to meal_plan_versions.
+DELETE FROM meal_plan_versions
+WHERE meal_plan_id IN (
+    SELECT id FROM user_meal_plans WHERE user_id = ? AND week_start_date < ?
+);
+
 -- name: DeleteOldMealPlansByUserID :exec
 DELETE FROM user_meal_plans
 WHERE user_id = ? AND week_start_date < ?;
 
--- name: CheckPlanExists :one
-SELECT COUNT(*) FROM user_meal_plans
-WHERE user_id = ? AND week_start_date = ?;
-
 -- name: GetMealPlanByID :one
-SELECT id, user_id, plan_data, week_start_date, status, created_at FROM user_meal_plans
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
 WHERE id = ?;
 
+-- name: GetMealPlanByIDForUser :one
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE id = ? AND user_id = ?;
+
 -- name: GetDraftPlanByUserAndWeek :one
-SELECT id, user_id, plan_data, week_start_date, status, created_at FROM user_meal_plans
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
 WHERE user_id = ? AND week_start_date = ? AND status = 'DRAFT'
 LIMIT 1;
 
+-- name: GetCurrentPlanByUserAndWeek :one
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE user_id = ? AND week_start_date = ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetFinalPlanByUserAndWeek :one
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE user_id = ? AND week_start_date = ? AND status = 'FINAL'
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: InsertPlanVersion :exec
+INSERT INTO meal_plan_versions (meal_plan_id, version, parent_version, author, feedback, plan_data, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLatestPlanVersion :one
+SELECT CAST(COALESCE(MAX(version), 0) AS INTEGER) FROM meal_plan_versions
+WHERE meal_plan_id = ?;
+
+-- name: SetCurrentPlanVersion :exec
+UPDATE user_meal_plans
+SET plan_data = ?, status = ?, current_version = ?
+WHERE id = ?;
+
+-- name: ListPlanVersions :many
+SELECT meal_plan_id, version, parent_version, author, feedback, plan_data, created_at FROM meal_plan_versions
+WHERE meal_plan_id = ?
+ORDER BY version;
+
+-- name: GetPlanVersion :one
+SELECT meal_plan_id, version, parent_version, author, feedback, plan_data, created_at FROM meal_plan_versions
+WHERE meal_plan_id = ? AND version = ?;
+
 -- name: UpdatePlanStatus :exec
 UPDATE user_meal_plans
 SET status = ?
 WHERE id = ?;
+
+-- name: ConfirmDraftPlan :execrows
+UPDATE user_meal_plans
+SET status = 'FINAL'
+WHERE id = ? AND status = 'DRAFT';
+
+-- name: ReplaceOtherFinalPlans :exec
+UPDATE user_meal_plans
+SET status = 'REPLACED'
+WHERE user_id = ? AND week_start_date = ? AND status = 'FINAL' AND id != ?;
+
+-- name: ListFinalPlansSince :many
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE user_id = ? AND status = 'FINAL' AND week_start_date >= sqlc.arg(since)
+ORDER BY week_start_date DESC;
+
+-- name: GetUserSettings :one
+SELECT user_id, repeat_window_weeks, favorite_repeat_weeks, updated_at FROM user_settings
+WHERE user_id = ?;
+
+-- name: UpsertUserSettings :exec
+INSERT INTO user_settings (user_id, repeat_window_weeks, favorite_repeat_weeks, updated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    repeat_window_weeks = excluded.repeat_window_weeks,
+    favorite_repeat_weeks = excluded.favorite_repeat_weeks,
+    updated_at = excluded.updated_at;
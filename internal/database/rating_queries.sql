@@ -0,0 +1,37 @@
+-- name: UpsertRatingStars :exec
+INSERT INTO recipe_ratings (user_id, recipe_id, stars, meal_plan_id, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_id, recipe_id) DO UPDATE SET
+    stars = EXCLUDED.stars,
+    meal_plan_id = EXCLUDED.meal_plan_id,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: UpsertRatingComment :exec
+INSERT INTO recipe_ratings (user_id, recipe_id, comment, meal_plan_id, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_id, recipe_id) DO UPDATE SET
+    comment = EXCLUDED.comment,
+    meal_plan_id = EXCLUDED.meal_plan_id,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: UpsertRatingNeverSuggest :exec
+INSERT INTO recipe_ratings (user_id, recipe_id, never_suggest, meal_plan_id, updated_at)
+VALUES (?, ?, 1, ?, ?)
+ON CONFLICT (user_id, recipe_id) DO UPDATE SET
+    never_suggest = 1,
+    meal_plan_id = EXCLUDED.meal_plan_id,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: ListRatingsByUser :many
+SELECT user_id, recipe_id, stars, comment, never_suggest, meal_plan_id, updated_at FROM recipe_ratings
+WHERE user_id = ?
+ORDER BY updated_at DESC;
+
+-- name: InsertRatingPrompt :execrows
+INSERT INTO rating_prompts (meal_plan_id, day_index, user_id, sent_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (meal_plan_id, day_index) DO NOTHING;
+
+-- name: DeleteRatingPrompt :exec
+DELETE FROM rating_prompts
+WHERE meal_plan_id = ? AND day_index = ?;
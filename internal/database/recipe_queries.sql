@@ -10,6 +10,11 @@ WHERE EXCLUDED.updated_at > recipes.updated_at;
 SELECT id, data, updated_at FROM recipes
 WHERE id = ?;
 
+-- name: GetRecipeBySourceURL :one
+SELECT id, data, updated_at FROM recipes
+WHERE json_extract(data, '$.source_url') = CAST(sqlc.arg(source_url) AS TEXT)
+LIMIT 1;
+
 -- name: UpdateRecipeData :exec
 UPDATE recipes
 SET data = ?
@@ -24,6 +29,11 @@ SELECT id, data, updated_at FROM recipes
 WHERE id NOT IN (sqlc.slice('exclude_ids'))
 ORDER BY updated_at DESC;
 
+-- name: ListRecipeIDsBelowExtractionVersion :many
+SELECT id FROM recipes
+WHERE COALESCE(json_extract(data, '$.extraction_version'), 0) < CAST(sqlc.arg(version) AS INTEGER)
+ORDER BY updated_at DESC;
+
 -- name: ListAllRecipes :many
 SELECT id, data, updated_at FROM recipes
 ORDER BY updated_at DESC;
@@ -46,7 +56,124 @@ ON CONFLICT (recipe_id, tag) DO NOTHING;
 DELETE FROM recipe_tags
 WHERE recipe_id = ?;
 
+-- name: InsertRecipeIngredient :exec
+INSERT INTO recipe_ingredients (recipe_id, ingredient)
+VALUES (?, ?)
+ON CONFLICT (recipe_id, ingredient) DO NOTHING;
+
+-- name: DeleteRecipeIngredients :exec
+DELETE FROM recipe_ingredients
+WHERE recipe_id = ?;
+
+-- name: GetRecipeIDsByIngredients :many
+SELECT recipe_id
+FROM recipe_ingredients
+WHERE ingredient IN (sqlc.slice('ingredients'))
+UNION
+SELECT id FROM recipes
+WHERE NOT EXISTS (
+    SELECT 1 FROM recipe_ingredients
+    WHERE recipe_ingredients.recipe_id = recipes.id
+);
+
+-- name: GetRecipeIDsOutsideFilter :many
+SELECT id FROM recipes
+WHERE (CAST(sqlc.arg(max_prep_minutes) AS INTEGER) > 0 AND (prep_minutes IS NULL OR prep_minutes > CAST(sqlc.arg(max_prep_minutes) AS INTEGER)))
+   OR (CAST(sqlc.arg(min_servings) AS INTEGER) > 0 AND (servings IS NULL OR servings < CAST(sqlc.arg(min_servings) AS INTEGER)))
+   OR (CAST(sqlc.arg(meal_type) AS TEXT) != '' AND (meal_type IS NULL OR meal_type != CAST(sqlc.arg(meal_type) AS TEXT)))
+   OR (CAST(sqlc.arg(cuisine) AS TEXT) != '' AND (cuisine IS NULL OR cuisine != CAST(sqlc.arg(cuisine) AS TEXT)))
+   OR (CAST(sqlc.arg(main_protein) AS TEXT) != '' AND (main_protein IS NULL OR main_protein != CAST(sqlc.arg(main_protein) AS TEXT)));
+
+-- name: GetRecipeIDsWithoutTags :many
+SELECT id FROM recipes
+WHERE NOT EXISTS (
+    SELECT 1 FROM recipe_tags
+    WHERE recipe_tags.recipe_id = recipes.id AND tag IN (sqlc.slice('tags'))
+);
+
+-- name: ListTagCounts :many
+SELECT tag, COUNT(*) AS recipe_count
+FROM recipe_tags
+GROUP BY tag
+ORDER BY recipe_count DESC, tag;
+
+-- name: ListRecipeTags :many
+SELECT recipe_id, tag FROM recipe_tags
+ORDER BY recipe_id, tag;
+
 -- name: GetRecipeIDsByTags :many
 SELECT DISTINCT recipe_id
 FROM recipe_tags
 WHERE tag IN (sqlc.slice('tags'));
+
+-- name: ExpandTags :many
+WITH RECURSIVE wanted(tag) AS (
+    SELECT tag FROM tag_synonyms WHERE synonym IN (sqlc.slice('tags'))
+    UNION
+    SELECT tag_taxonomy.tag FROM tag_taxonomy JOIN wanted ON tag_taxonomy.parent = wanted.tag
+)
+SELECT synonym FROM tag_synonyms
+WHERE tag IN (SELECT tag FROM wanted);
+
+-- name: GetTagsBySynonyms :many
+SELECT tag_synonyms.synonym, tag_taxonomy.tag, tag_taxonomy.label_pt, tag_taxonomy.category, tag_taxonomy.parent
+FROM tag_synonyms
+JOIN tag_taxonomy ON tag_taxonomy.tag = tag_synonyms.tag
+WHERE tag_synonyms.synonym IN (sqlc.slice('synonyms'));
+
+-- name: ListTaxonomyTags :many
+SELECT tag, label_pt, category, parent FROM tag_taxonomy
+ORDER BY category, tag;
+
+-- name: UpsertTaxonomyTag :exec
+INSERT INTO tag_taxonomy (tag, label_pt, category, parent)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (tag) DO UPDATE SET
+    label_pt = EXCLUDED.label_pt,
+    category = EXCLUDED.category,
+    parent = EXCLUDED.parent;
+
+-- name: GetTaxonomyTag :one
+SELECT tag, label_pt, category, parent FROM tag_taxonomy
+WHERE tag = ?;
+
+-- name: DeleteTaxonomyTag :exec
+DELETE FROM tag_taxonomy WHERE tag = ?;
+
+-- name: RepointTagSynonyms :exec
+UPDATE tag_synonyms
+SET tag = sqlc.arg(to_tag)
+WHERE tag = sqlc.arg(from_tag);
+
+-- name: RepointTagChildren :exec
+UPDATE tag_taxonomy
+SET parent = sqlc.arg(to_tag)
+WHERE parent = sqlc.arg(from_tag);
+
+-- name: ListTagSynonyms :many
+SELECT synonym, tag FROM tag_synonyms
+ORDER BY tag, synonym;
+
+-- name: InsertTagSynonym :exec
+INSERT INTO tag_synonyms (synonym, tag)
+VALUES (?, ?)
+ON CONFLICT (synonym) DO UPDATE SET tag = EXCLUDED.tag;
+
+-- name: UpsertTagSuggestion :exec
+INSERT INTO tag_suggestions (tag, label_pt, recipe_id, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (tag) DO UPDATE SET occurrences = tag_suggestions.occurrences + 1;
+
+-- name: GetTagSuggestion :one
+SELECT tag, label_pt, recipe_id, occurrences, created_at FROM tag_suggestions
+WHERE tag = ?;
+
+-- name: ListTagSuggestions :many
+SELECT tag, label_pt, recipe_id, occurrences, created_at FROM tag_suggestions
+ORDER BY occurrences DESC, tag;
+
+-- name: DeleteTagSuggestion :exec
+DELETE FROM tag_suggestions WHERE tag = ?;
+
+-- name: CountRecipes :one
+SELECT COUNT(*) FROM recipes;
@@ -0,0 +1,20 @@
+-- name: GetReminderSettings :one
+SELECT user_id, timezone, enabled, updated_at FROM reminder_settings
+WHERE user_id = ?;
+
+-- name: UpsertReminderSettings :exec
+INSERT INTO reminder_settings (user_id, timezone, enabled, updated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    timezone = excluded.timezone,
+    enabled = excluded.enabled,
+    updated_at = excluded.updated_at;
+
+-- name: InsertReminderDelivery :execrows
+INSERT INTO reminder_deliveries (user_id, kind, local_date, sent_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id, kind, local_date) DO NOTHING;
+
+-- name: DeleteReminderDelivery :exec
+DELETE FROM reminder_deliveries
+WHERE user_id = ? AND kind = ? AND local_date = ?;
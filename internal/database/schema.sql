@@ -18,10 +18,22 @@ CREATE INDEX IF NOT EXISTS idx_execution_metrics_agent_model ON execution_metric
 CREATE TABLE IF NOT EXISTS recipes (
     id TEXT PRIMARY KEY NOT NULL,
     data TEXT NOT NULL,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    prep_minutes INTEGER GENERATED ALWAYS AS (json_extract(data, '$.total_minutes')) VIRTUAL,
+    servings INTEGER GENERATED ALWAYS AS (json_extract(data, '$.yield')) VIRTUAL,
+    cuisine TEXT GENERATED ALWAYS AS (lower(json_extract(data, '$.cuisine'))) VIRTUAL,
+    meal_type TEXT GENERATED ALWAYS AS (lower(json_extract(data, '$.meal_type'))) VIRTUAL,
+    main_protein TEXT GENERATED ALWAYS AS (lower(json_extract(data, '$.main_protein'))) VIRTUAL
 );
 CREATE INDEX IF NOT EXISTS idx_recipes_updated_at ON recipes(updated_at);
 CREATE INDEX IF NOT EXISTS idx_recipes_id_updated_at ON recipes(id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_recipes_source_url ON recipes(json_extract(data, '$.source_url'));
+CREATE INDEX IF NOT EXISTS idx_recipes_extraction_version ON recipes(COALESCE(json_extract(data, '$.extraction_version'), 0));
+CREATE INDEX IF NOT EXISTS idx_recipes_prep_minutes ON recipes(prep_minutes);
+CREATE INDEX IF NOT EXISTS idx_recipes_servings ON recipes(servings);
+CREATE INDEX IF NOT EXISTS idx_recipes_cuisine ON recipes(cuisine);
+CREATE INDEX IF NOT EXISTS idx_recipes_meal_type ON recipes(meal_type);
+CREATE INDEX IF NOT EXISTS idx_recipes_main_protein ON recipes(main_protein);
 
 -- recipe_embeddings table
 CREATE TABLE IF NOT EXISTS recipe_embeddings (
@@ -41,13 +53,26 @@ CREATE TABLE IF NOT EXISTS user_meal_plans (
     plan_data TEXT NOT NULL,
     week_start_date DATETIME NOT NULL,
     status TEXT NOT NULL DEFAULT 'FINAL',
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    current_version INTEGER NOT NULL DEFAULT 1
 );
 CREATE INDEX IF NOT EXISTS idx_user_meal_plans_user_id ON user_meal_plans(user_id);
 CREATE INDEX IF NOT EXISTS idx_user_meal_plans_week_start ON user_meal_plans(week_start_date);
 CREATE INDEX IF NOT EXISTS idx_user_meal_plans_user_id_week ON user_meal_plans(user_id, week_start_date DESC);
 CREATE INDEX IF NOT EXISTS idx_user_meal_plans_status ON user_meal_plans(status);
 
+-- meal_plan_versions table (every generated, revised or restored state of a plan)
+CREATE TABLE IF NOT EXISTS meal_plan_versions (
+    meal_plan_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    parent_version INTEGER,
+    author TEXT NOT NULL,
+    feedback TEXT NOT NULL DEFAULT '',
+    plan_data TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (meal_plan_id, version)
+);
+
 -- shopping_lists table
 CREATE TABLE IF NOT EXISTS shopping_lists (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -108,3 +133,96 @@ CREATE TABLE IF NOT EXISTS recipe_tags (
     FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
 );
 CREATE INDEX IF NOT EXISTS idx_recipe_tags_tag ON recipe_tags(tag);
+
+-- recipe_ingredients table (normalized ingredient names in both languages)
+CREATE TABLE IF NOT EXISTS recipe_ingredients (
+    recipe_id TEXT NOT NULL,
+    ingredient TEXT NOT NULL,
+    PRIMARY KEY (recipe_id, ingredient),
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_ingredient ON recipe_ingredients(ingredient);
+
+-- recipe_ratings table (per-user verdicts on cooked recipes)
+CREATE TABLE IF NOT EXISTS recipe_ratings (
+    user_id TEXT NOT NULL,
+    recipe_id TEXT NOT NULL,
+    stars INTEGER NOT NULL DEFAULT 0,
+    comment TEXT NOT NULL DEFAULT '',
+    never_suggest BOOLEAN NOT NULL DEFAULT 0,
+    meal_plan_id INTEGER NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, recipe_id),
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+-- rating_prompts table (plan days the user was already asked to rate)
+CREATE TABLE IF NOT EXISTS rating_prompts (
+    meal_plan_id INTEGER NOT NULL,
+    day_index INTEGER NOT NULL,
+    user_id TEXT NOT NULL,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (meal_plan_id, day_index),
+    FOREIGN KEY (meal_plan_id) REFERENCES user_meal_plans(id) ON DELETE CASCADE
+);
+
+-- tag_taxonomy table (canonical English tags with a pt-BR label and optional parent)
+CREATE TABLE IF NOT EXISTS tag_taxonomy (
+    tag TEXT PRIMARY KEY NOT NULL,
+    label_pt TEXT NOT NULL,
+    category TEXT NOT NULL,
+    parent TEXT,
+    FOREIGN KEY (parent) REFERENCES tag_taxonomy(tag) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_tag_taxonomy_parent ON tag_taxonomy(parent);
+
+-- tag_synonyms table (every spelling of a canonical tag, in both languages)
+CREATE TABLE IF NOT EXISTS tag_synonyms (
+    synonym TEXT PRIMARY KEY NOT NULL,
+    tag TEXT NOT NULL,
+    FOREIGN KEY (tag) REFERENCES tag_taxonomy(tag) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_tag_synonyms_tag ON tag_synonyms(tag);
+
+-- tag_suggestions table (review queue for tags outside the taxonomy)
+CREATE TABLE IF NOT EXISTS tag_suggestions (
+    tag TEXT PRIMARY KEY NOT NULL,
+    label_pt TEXT NOT NULL,
+    recipe_id TEXT NOT NULL,
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- user_settings table (per-user planning preferences; missing rows use the defaults)
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id TEXT PRIMARY KEY,
+    repeat_window_weeks INTEGER NOT NULL,
+    favorite_repeat_weeks INTEGER NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- reminder_settings table (per-user timezone and opt-out for daily reminders)
+CREATE TABLE IF NOT EXISTS reminder_settings (
+    user_id TEXT PRIMARY KEY,
+    timezone TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- reminder_deliveries table (reminders already sent, by the user's local date)
+CREATE TABLE IF NOT EXISTS reminder_deliveries (
+    user_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    local_date TEXT NOT NULL,
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, kind, local_date)
+);
+
+-- cooking_timers table (timers started in cooking mode that have not rung yet)
+CREATE TABLE IF NOT EXISTS cooking_timers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id INTEGER NOT NULL,
+    message TEXT NOT NULL,
+    due_at DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_cooking_timers_due_at ON cooking_timers(due_at);
@@ -6,17 +6,43 @@ RETURNING id;
 -- name: GetActiveSession :one
 SELECT id, user_id, session_type, state, context_data, expires_at, created_at
 FROM user_sessions
-WHERE user_id = ? AND expires_at > ?
+WHERE user_id = ? AND expires_at > ? AND session_type IN (sqlc.slice('session_types'))
 ORDER BY created_at DESC
 LIMIT 1;
 
+-- name: GetSession :one
+SELECT id, user_id, session_type, state, context_data, expires_at, created_at
+FROM user_sessions
+WHERE id = ? AND user_id = ? AND expires_at > ?;
+
 -- name: UpdateSession :exec
 UPDATE user_sessions
 SET state = ?, context_data = ?
 WHERE id = ?;
 
+-- name: ExtendSession :exec
+UPDATE user_sessions
+SET expires_at = ?
+WHERE id = ?;
+
 -- name: DeleteSession :exec
 DELETE FROM user_sessions WHERE id = ?;
 
+-- name: DeleteUserSessionsByType :exec
+DELETE FROM user_sessions WHERE user_id = ? AND session_type = ?;
+
 -- name: CleanupExpiredSessions :exec
 DELETE FROM user_sessions WHERE expires_at <= ?;
+
+-- name: CreateCookingTimer :exec
+INSERT INTO cooking_timers (chat_id, message, due_at)
+VALUES (?, ?, ?);
+
+-- name: ListDueCookingTimers :many
+SELECT id, chat_id, message, due_at
+FROM cooking_timers
+WHERE due_at <= ?
+ORDER BY due_at;
+
+-- name: DeleteCookingTimer :exec
+DELETE FROM cooking_timers WHERE id = ?;
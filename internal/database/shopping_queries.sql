@@ -13,8 +13,14 @@ SELECT sl.id, sl.user_id, sl.meal_plan_id, sl.items, sl.created_at
 FROM shopping_lists sl
 INNER JOIN user_meal_plans ump ON sl.meal_plan_id = ump.id
 WHERE sl.user_id = ? AND ump.week_start_date = ?
+ORDER BY sl.id DESC
 LIMIT 1;
 
 -- name: DeleteShoppingListByMealPlanID :exec
 DELETE FROM shopping_lists
 WHERE meal_plan_id = ?;
+
+-- name: UpdateShoppingListItems :exec
+UPDATE shopping_lists
+SET items = ?
+WHERE id = ?;
@@ -1,8 +1,10 @@
 package ghost
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -17,11 +19,42 @@ import (
 type Post struct {
 	ID        string `json:"id"`
 	Title     string `json:"title"`
+	Slug      string `json:"slug,omitempty"`
+	Status    string `json:"status,omitempty"`
+	URL       string `json:"url,omitempty"`
 	HTML      string `json:"html"`
 	UpdatedAt string `json:"updated_at"`
 	Tags      []Tag  `json:"tags,omitempty"`
+
+	FeatureImage string `json:"feature_image,omitempty"`
 }
 
+// Post statuses accepted by the Admin API.
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+)
+
+// PostUpdate describes the fields to change on an existing post.
+// Nil fields are left untouched; a non-nil Tags slice replaces all tags.
+type PostUpdate struct {
+	Title  *string
+	Status *string
+	Tags   []string
+}
+
+// ErrUpdateCollision is returned when a post keeps changing between
+// reading its updated_at and writing the update.
+var ErrUpdateCollision = errors.New("post was modified concurrently")
+
+// adminPostQuery makes the Admin API return rendered HTML and tags,
+// which is what ingestion needs after a post is edited.
+const adminPostQuery = "?include=tags&formats=html"
+
+// maxUpdateAttempts bounds how many times UpdatePost re-reads updated_at
+// after Ghost rejects a write with a collision.
+const maxUpdateAttempts = 3
+
 // Tag represents a tag in Ghost.
 type Tag struct {
 	ID   string `json:"id,omitempty"`
@@ -55,6 +88,10 @@ type Client interface {
 	FetchRecipes() ([]Post, error)
 	FetchRecipeByID(id string) (*Post, error)
 	CreatePost(title, html string, tags []string, publish bool) (*Post, error)
+	GetPostBySlug(slug string) (*Post, error)
+	UpdatePost(id string, update PostUpdate) (*Post, error)
+	DeletePost(id string) error
+	AddTags(id string, tags []string) (*Post, error)
 }
 
 // ghostClient is the concrete implementation of the Ghost API client.
@@ -149,19 +186,9 @@ func (c *ghostClient) FetchRecipeByID(id string) (*Post, error) {
 
 // CreatePost creates a new post using the Ghost Admin API.
 func (c *ghostClient) CreatePost(title, html string, tags []string, publish bool) (*Post, error) {
-	token, err := c.createAdminToken()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create admin token: %w", err)
-	}
-
-	status := "draft"
+	status := StatusDraft
 	if publish {
-		status = "published"
-	}
-
-	ghostTags := make([]Tag, len(tags))
-	for i, t := range tags {
-		ghostTags[i] = Tag{Name: t}
+		status = StatusPublished
 	}
 
 	newPost := map[string]interface{}{
@@ -170,34 +197,184 @@ func (c *ghostClient) CreatePost(title, html string, tags []string, publish bool
 				"title":  title,
 				"html":   html,
 				"status": status,
-				"tags":   ghostTags,
+				"tags":   toGhostTags(tags),
 			},
 		},
 	}
 
-	body, _ := json.Marshal(newPost)
-	url := fmt.Sprintf("%s/ghost/api/v3/admin/posts/?source=html", c.config.GhostURL)
+	resp, err := c.doAdmin("POST", "/posts/?source=html", newPost)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, adminError(resp)
+	}
+
+	return decodeSinglePost(resp)
+}
+
+// GetPostBySlug fetches a post of any status by its slug using the Admin API.
+func (c *ghostClient) GetPostBySlug(slug string) (*Post, error) {
+	return c.getAdminPost("/posts/slug/" + slug + "/" + adminPostQuery)
+}
+
+// UpdatePost applies the given changes to an existing post.
+// Ghost rejects writes whose updated_at does not match the stored value, so the
+// current updated_at is read right before each write and the update is retried
+// when another editor saved the post in between.
+func (c *ghostClient) UpdatePost(id string, update PostUpdate) (*Post, error) {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		current, err := c.getAdminPost("/posts/" + id + "/" + adminPostQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		fields := map[string]interface{}{
+			"updated_at": current.UpdatedAt,
+		}
+		if update.Title != nil {
+			fields["title"] = *update.Title
+		}
+		if update.Status != nil {
+			fields["status"] = *update.Status
+		}
+		if update.Tags != nil {
+			fields["tags"] = toGhostTags(update.Tags)
+		}
+
+		payload := map[string]interface{}{
+			"posts": []map[string]interface{}{fields},
+		}
+
+		resp, err := c.doAdmin("PUT", "/posts/"+id+"/"+adminPostQuery, payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusConflict {
+			resp.Body.Close()
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := adminError(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		post, err := decodeSinglePost(resp)
+		resp.Body.Close()
+		return post, err
+	}
+
+	return nil, fmt.Errorf("failed to update post %s after %d attempts: %w", id, maxUpdateAttempts, ErrUpdateCollision)
+}
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(body)))
+// DeletePost permanently removes a post using the Admin API.
+func (c *ghostClient) DeletePost(id string) error {
+	resp, err := c.doAdmin("DELETE", "/posts/"+id+"/", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("post with ID %s not found", id)
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return adminError(resp)
+	}
+	return nil
+}
+
+// AddTags appends tags to a post, keeping the ones it already has.
+// Tag names are compared case-insensitively.
+func (c *ghostClient) AddTags(id string, tags []string) (*Post, error) {
+	current, err := c.getAdminPost("/posts/" + id + "/" + adminPostQuery)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Ghost "+token)
-	req.Header.Set("Content-Type", "application/json")
+	seen := make(map[string]struct{}, len(current.Tags)+len(tags))
+	merged := make([]string, 0, len(current.Tags)+len(tags))
+	for _, t := range current.Tags {
+		seen[strings.ToLower(t.Name)] = struct{}{}
+		merged = append(merged, t.Name)
+	}
+	for _, t := range tags {
+		name := strings.TrimSpace(t)
+		if name == "" {
+			continue
+		}
+		if _, exists := seen[strings.ToLower(name)]; exists {
+			continue
+		}
+		seen[strings.ToLower(name)] = struct{}{}
+		merged = append(merged, name)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	return c.UpdatePost(id, PostUpdate{Tags: merged})
+}
+
+// getAdminPost fetches a single post from an Admin API path.
+func (c *ghostClient) getAdminPost(path string) (*Post, error) {
+	resp, err := c.doAdmin("GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		var errResp interface{}
-		json.NewDecoder(resp.Body).Decode(&errResp)
-		return nil, fmt.Errorf("admin api error: status %d, body: %v", resp.StatusCode, errResp)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("post not found: %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, adminError(resp)
+	}
+
+	return decodeSinglePost(resp)
+}
+
+// doAdmin sends an authenticated request to the Admin API. The caller must close the response body.
+func (c *ghostClient) doAdmin(method, path string, payload interface{}) (*http.Response, error) {
+	token, err := c.createAdminToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin token: %w", err)
 	}
 
+	var body *bytes.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal admin payload: %w", err)
+		}
+		body = bytes.NewReader(raw)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	url := fmt.Sprintf("%s/ghost/api/v3/admin%s", c.config.GhostURL, path)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Ghost "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func adminError(resp *http.Response) error {
+	var errResp interface{}
+	json.NewDecoder(resp.Body).Decode(&errResp)
+	return fmt.Errorf("admin api error: status %d, body: %v", resp.StatusCode, errResp)
+}
+
+func decodeSinglePost(resp *http.Response) (*Post, error) {
 	var response PostsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, err
@@ -210,6 +387,14 @@ func (c *ghostClient) CreatePost(title, html string, tags []string, publish bool
 	return &response.Posts[0], nil
 }
 
+func toGhostTags(tags []string) []Tag {
+	ghostTags := make([]Tag, len(tags))
+	for i, t := range tags {
+		ghostTags[i] = Tag{Name: t}
+	}
+	return ghostTags
+}
+
 // createAdminToken generates a short-lived JWT for the Admin API.
 func (c *ghostClient) createAdminToken() (string, error) {
 	keyParts := strings.Split(c.config.GhostAdminKey, ":")
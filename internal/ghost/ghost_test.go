@@ -2,9 +2,12 @@ package ghost
 
 import (
 	"ai-meal-planner/internal/config"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -72,3 +75,132 @@ func TestFetchRecipes(t *testing.T) {
 		}
 	})
 }
+
+func newAdminTestClient(serverURL string) Client {
+	return NewClient(&config.Config{
+		GhostURL:      serverURL,
+		GhostAdminKey: "adminid:0123456789abcdef",
+	})
+}
+
+func TestUpdatePost(t *testing.T) {
+	t.Run("RetriesOnCollision", func(t *testing.T) {
+		var reads, writes int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Ghost ") {
+				t.Errorf("missing admin authorization header")
+			}
+			switch r.Method {
+			case http.MethodGet:
+				reads++
+				fmt.Fprintf(w, `{"posts":[{"id":"p1","title":"Old","updated_at":"2024-01-0%dT00:00:00.000Z"}]}`, reads)
+			case http.MethodPut:
+				writes++
+				var body struct {
+					Posts []map[string]interface{} `json:"posts"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode body: %v", err)
+				}
+				if writes == 1 {
+					w.WriteHeader(http.StatusConflict)
+					fmt.Fprintln(w, `{"errors":[{"type":"UpdateCollisionError"}]}`)
+					return
+				}
+				if got := body.Posts[0]["updated_at"]; got != "2024-01-02T00:00:00.000Z" {
+					t.Errorf("updated_at = %v, want the re-read value", got)
+				}
+				fmt.Fprintf(w, `{"posts":[{"id":"p1","title":%q,"updated_at":"2024-01-03T00:00:00.000Z"}]}`, body.Posts[0]["title"])
+			}
+		}))
+		defer server.Close()
+
+		title := "New"
+		post, err := newAdminTestClient(server.URL).UpdatePost("p1", PostUpdate{Title: &title})
+		if err != nil {
+			t.Fatalf("UpdatePost() error = %v", err)
+		}
+		if post.Title != "New" {
+			t.Errorf("title = %q, want %q", post.Title, "New")
+		}
+		if reads != 2 || writes != 2 {
+			t.Errorf("reads = %d, writes = %d, want 2 and 2", reads, writes)
+		}
+	})
+
+	t.Run("GivesUpAfterRepeatedCollisions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				fmt.Fprintln(w, `{"posts":[{"id":"p1","updated_at":"2024-01-01T00:00:00.000Z"}]}`)
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+		}))
+		defer server.Close()
+
+		status := StatusDraft
+		_, err := newAdminTestClient(server.URL).UpdatePost("p1", PostUpdate{Status: &status})
+		if !errors.Is(err, ErrUpdateCollision) {
+			t.Fatalf("error = %v, want ErrUpdateCollision", err)
+		}
+	})
+}
+
+func TestAddTags(t *testing.T) {
+	var sent []Tag
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprintln(w, `{"posts":[{"id":"p1","updated_at":"2024-01-01T00:00:00.000Z","tags":[{"name":"Dinner"}]}]}`)
+			return
+		}
+		var body struct {
+			Posts []struct {
+				Tags []Tag `json:"tags"`
+			} `json:"posts"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent = body.Posts[0].Tags
+		fmt.Fprintln(w, `{"posts":[{"id":"p1"}]}`)
+	}))
+	defer server.Close()
+
+	if _, err := newAdminTestClient(server.URL).AddTags("p1", []string{"dinner", "Quick", " "}); err != nil {
+		t.Fatalf("AddTags() error = %v", err)
+	}
+	if len(sent) != 2 || sent[0].Name != "Dinner" || sent[1].Name != "Quick" {
+		t.Errorf("sent tags = %+v, want [Dinner Quick]", sent)
+	}
+}
+
+func TestDeletePost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/ghost/api/v3/admin/posts/p1/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := newAdminTestClient(server.URL).DeletePost("p1"); err != nil {
+		t.Fatalf("DeletePost() error = %v", err)
+	}
+}
+
+func TestGetPostBySlug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ghost/api/v3/admin/posts/slug/pao-de-queijo/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `{"posts":[{"id":"p1","slug":"pao-de-queijo","status":"draft"}]}`)
+	}))
+	defer server.Close()
+
+	post, err := newAdminTestClient(server.URL).GetPostBySlug("pao-de-queijo")
+	if err != nil {
+		t.Fatalf("GetPostBySlug() error = %v", err)
+	}
+	if post.ID != "p1" || post.Status != StatusDraft {
+		t.Errorf("post = %+v", post)
+	}
+}
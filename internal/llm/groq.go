@@ -27,6 +27,7 @@ const (
 	ModelChef       = config.DefaultChefModel
 	ModelNormalizer = config.DefaultNormalizerModel
 	ModelTagger     = config.DefaultTaggerModel
+	ModelVision     = config.DefaultVisionModel
 )
 
 // GroqClient is a client for the Groq API.
@@ -54,6 +55,32 @@ type groqMessage struct {
 	Content    string         `json:"content"`
 	ToolCalls  []groqToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string         `json:"tool_call_id,omitempty"`
+
+	// ContentParts replaces Content in requests that carry images.
+	ContentParts []groqContentPart `json:"-"`
+}
+
+type groqContentPart struct {
+	Type     string        `json:"type"`
+	Text     string        `json:"text,omitempty"`
+	ImageURL *groqImageURL `json:"image_url,omitempty"`
+}
+
+type groqImageURL struct {
+	URL string `json:"url"`
+}
+
+// MarshalJSON sends content as a list of parts when the message has images,
+// which is how the API expects vision input.
+func (m groqMessage) MarshalJSON() ([]byte, error) {
+	type plainMessage groqMessage
+	if len(m.ContentParts) == 0 {
+		return json.Marshal(plainMessage(m))
+	}
+	return json.Marshal(struct {
+		plainMessage
+		Content []groqContentPart `json:"content"`
+	}{plainMessage(m), m.ContentParts})
 }
 
 type groqResponseFormat struct {
@@ -308,12 +335,23 @@ func mapToGroqMessages(conversation []Message) ([]groqMessage, error) {
 			return nil, err
 		}
 
-		result = append(result, groqMessage{
+		msg := groqMessage{
 			Role:       m.Role,
 			Content:    m.Content,
 			ToolCalls:  calls,
 			ToolCallID: m.ToolCallID,
-		})
+		}
+		if len(m.Images) > 0 {
+			msg.ContentParts = append(msg.ContentParts, groqContentPart{Type: "text", Text: m.Content})
+			for _, img := range m.Images {
+				msg.ContentParts = append(msg.ContentParts, groqContentPart{
+					Type:     "image_url",
+					ImageURL: &groqImageURL{URL: img.DataURL()},
+				})
+			}
+		}
+
+		result = append(result, msg)
 	}
 	return result, nil
 }
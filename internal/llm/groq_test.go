@@ -78,6 +78,35 @@ func TestMapToGroqMessages(t *testing.T) {
 	}
 }
 
+func TestMapToGroqMessagesWithImages(t *testing.T) {
+	groqMsgs, err := mapToGroqMessages([]Message{{
+		Role:    "user",
+		Content: "Read this recipe",
+		Images:  []Image{{MIMEType: "image/png", Data: []byte("png")}},
+	}})
+	if err != nil {
+		t.Fatalf("mapToGroqMessages failed: %v", err)
+	}
+
+	bytes, err := json.Marshal(groqMsgs[0])
+	if err != nil {
+		t.Fatalf("failed to marshal groq message: %v", err)
+	}
+
+	var raw struct {
+		Content []groqContentPart `json:"content"`
+	}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		t.Fatalf("expected content to be a list of parts: %v (%s)", err, bytes)
+	}
+	if len(raw.Content) != 2 || raw.Content[0].Text != "Read this recipe" {
+		t.Fatalf("unexpected content parts: %s", bytes)
+	}
+	if raw.Content[1].ImageURL == nil || raw.Content[1].ImageURL.URL != "data:image/png;base64,cG5n" {
+		t.Errorf("unexpected image part: %s", bytes)
+	}
+}
+
 func TestMapToTToolCall(t *testing.T) {
 	client := &GroqClient{}
 	rawCall := groqToolCall{
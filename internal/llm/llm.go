@@ -3,6 +3,7 @@ package llm
 import (
 	"ai-meal-planner/internal/shared"
 	"context"
+	"encoding/base64"
 	"regexp"
 	"strings"
 )
@@ -77,6 +78,18 @@ type Message struct {
 	Content    string
 	ToolCalls  []ToolCall
 	ToolCallID string
+	Images     []Image // Only honoured by vision-capable models
+}
+
+// Image is a picture attached to a user message.
+type Image struct {
+	MIMEType string
+	Data     []byte
+}
+
+// DataURL encodes the image inline, so private files never need a public URL.
+func (i Image) DataURL() string {
+	return "data:" + i.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(i.Data)
 }
 
 func (m *Message) IsAToolCall() bool {
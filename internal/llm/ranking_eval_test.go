@@ -0,0 +1,83 @@
+package llm_test
+
+import (
+	"testing"
+	"time"
+
+	"ai-meal-planner/internal/rating"
+	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/value"
+)
+
+const (
+	minimumRankedHitAt1 = 0.85
+	minimumRankedMRR    = 0.90
+)
+
+// rankingScenario is a search whose candidates arrive in retrieval order, with the
+// recipes a person picked as the best next meal given the household's history.
+type rankingScenario struct {
+	Name        string         `json:"name"`
+	Now         time.Time      `json:"now"`
+	Candidates  []value.Recipe `json:"candidates"`
+	History     []cookedEntry  `json:"history"`
+	Favorites   []string       `json:"favorites"`
+	RelevantIDs []string       `json:"relevant_ids"`
+}
+
+type cookedEntry struct {
+	RecipeID    string    `json:"recipe_id"`
+	CookedAt    time.Time `json:"cooked_at"`
+	Cuisine     string    `json:"cuisine"`
+	MainProtein string    `json:"main_protein"`
+}
+
+// TestDefaultRankerOfflineEval replays curated history scenarios through the default ranker
+// and compares the metrics against the raw retrieval order. It needs no API keys.
+func TestDefaultRankerOfflineEval(t *testing.T) {
+	scenarios := loadFixture[[]rankingScenario](t, "ranking_scenarios.json")
+	ranker := recipe.NewDefaultRanker()
+
+	var baseline, ranked []rankedResult
+	for _, sc := range scenarios {
+		rc := recipe.RankingContext{
+			Now:         sc.Now,
+			Preferences: rating.Preferences{Favorites: make(map[string]struct{})},
+		}
+		for _, h := range sc.History {
+			rc.History = append(rc.History, recipe.CookedRecipe(h))
+		}
+		for _, id := range sc.Favorites {
+			rc.Preferences.Favorites[id] = struct{}{}
+		}
+
+		query := goldenQuery{Name: sc.Name, RelevantIDs: sc.RelevantIDs}
+		baseline = append(baseline, rankedResult{Query: query, RetrievedIDs: recipeIDs(sc.Candidates)})
+		result := ranker.Rank(sc.Candidates, rc)
+		ranked = append(ranked, rankedResult{Query: query, RetrievedIDs: recipeIDs(result)})
+		t.Logf("%s: %v", sc.Name, recipeIDs(result))
+	}
+
+	before := calculateRetrievalMetrics(baseline, retrievalTopK)
+	after := calculateRetrievalMetrics(ranked, retrievalTopK)
+	t.Logf("retrieval order: Hit@1=%.3f MRR@3=%.3f", before.HitAt1, before.MRRAtK)
+	t.Logf("default ranker:  Hit@1=%.3f MRR@3=%.3f", after.HitAt1, after.MRRAtK)
+
+	if after.HitAt1 < minimumRankedHitAt1 {
+		t.Errorf("ranked Hit@1 %.3f is below minimum %.2f", after.HitAt1, minimumRankedHitAt1)
+	}
+	if after.MRRAtK < minimumRankedMRR {
+		t.Errorf("ranked MRR@3 %.3f is below minimum %.2f", after.MRRAtK, minimumRankedMRR)
+	}
+	if after.MRRAtK <= before.MRRAtK {
+		t.Errorf("ranking did not improve MRR@3: %.3f <= %.3f", after.MRRAtK, before.MRRAtK)
+	}
+}
+
+func recipeIDs(recipes []value.Recipe) []string {
+	ids := make([]string, 0, len(recipes))
+	for _, rec := range recipes {
+		ids = append(ids, rec.ID)
+	}
+	return ids
+}
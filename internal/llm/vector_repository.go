@@ -92,10 +92,32 @@ func (r *VectorRepository) Get(ctx context.Context, recipeID string) (*Embedding
 	}, nil
 }
 
+// ScoredRecipe is a recipe ID with its cosine similarity to a query embedding.
+type ScoredRecipe struct {
+	RecipeID string
+	Score    float64
+}
+
 // FindSimilar searches for recipes with embeddings similar to the query.
 // It retrieves all embeddings, calculates cosine similarity, and fetches the corresponding
 // recipe data for the top N similar recipes.
 func (r *VectorRepository) FindSimilar(ctx context.Context, queryEmbedding []float32, limit int, excludeIDs []string) ([]string, error) {
+	scoredRecipes, err := r.FindSimilarScored(ctx, queryEmbedding, limit, excludeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, s := range scoredRecipes {
+		result = append(result, s.RecipeID)
+	}
+
+	return result, nil
+}
+
+// FindSimilarScored works like FindSimilar but keeps the similarity scores,
+// for callers that need to apply a threshold rather than just rank.
+func (r *VectorRepository) FindSimilarScored(ctx context.Context, queryEmbedding []float32, limit int, excludeIDs []string) ([]ScoredRecipe, error) {
 	allEmbeddings, err := r.queries.ListAllEmbeddings(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list all embeddings: %w", err)
@@ -107,12 +129,7 @@ func (r *VectorRepository) FindSimilar(ctx context.Context, queryEmbedding []flo
 		excludeMap[id] = struct{}{}
 	}
 
-	type scoredRecipe struct {
-		RecipeID string
-		Score    float64
-	}
-
-	scoredRecipes := []scoredRecipe{}
+	scoredRecipes := []ScoredRecipe{}
 
 	for _, dbEmbed := range allEmbeddings {
 		// Skip if ID is in the exclusion list
@@ -126,18 +143,14 @@ func (r *VectorRepository) FindSimilar(ctx context.Context, queryEmbedding []flo
 			continue
 		}
 
-		score := cosineSimilarity(queryEmbedding, embed)
-		scoredRecipes = append(scoredRecipes, struct {
-			RecipeID string
-			Score    float64
-		}{
+		scoredRecipes = append(scoredRecipes, ScoredRecipe{
 			RecipeID: dbEmbed.RecipeID,
-			Score:    score,
+			Score:    cosineSimilarity(queryEmbedding, embed),
 		})
 	}
 
 	// Sort by score descending (highest similarity first)
-	slices.SortFunc(scoredRecipes, func(i, j scoredRecipe) int {
+	slices.SortFunc(scoredRecipes, func(i, j ScoredRecipe) int {
 		if i.Score > j.Score {
 			return -1
 		}
@@ -147,17 +160,12 @@ func (r *VectorRepository) FindSimilar(ctx context.Context, queryEmbedding []flo
 		return 0
 	})
 
-	// Take top K and fetch full recipe data
+	// Take top K
 	if limit > len(scoredRecipes) {
 		limit = len(scoredRecipes)
 	}
 
-	var result []string
-	for i := 0; i < limit; i++ {
-		result = append(result, scoredRecipes[i].RecipeID)
-	}
-
-	return result, nil
+	return scoredRecipes[:limit], nil
 }
 
 // float32SliceToByteSlice converts a slice of float32 to a byte slice.
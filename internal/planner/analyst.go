@@ -95,8 +95,9 @@ type MealProposal struct {
 }
 
 type AnalystResult struct {
-	Proposal *MealProposal
-	Meta     shared.AgentMeta
+	Proposal   *MealProposal
+	Exclusions PlanExclusions // What the searches ruled out, to keep with the plan
+	Meta       shared.AgentMeta
 }
 
 type rawLlmResult struct {
@@ -150,13 +151,18 @@ func (a *Analyst) Run(
 
 	raw := &rawLlmResult{}
 
+	baseFilter := shared.RecipeFilter{ExcludeIDs: recipesRecentlyUsed, UserID: planingCtx.UserID}
+	var exclusions PlanExclusions
+
 	// 2. Setup Tool Handlers
 	handlers := map[string]ToolHandler[[]value.Recipe]{
 		searchRecipesSemanticTool.Name: func(ctx context.Context, toolCall llm.ToolCall) (llm.Message, []value.Recipe, error) {
-			return HandleRecipeSemanticSearch(ctx, a.searcher, toolCall, recipesRecentlyUsed)
+			exclusions.addSearch(toolCall.Args)
+			return HandleRecipeSemanticSearch(ctx, a.searcher, toolCall, baseFilter)
 		},
 		searchRecipesRandomTool.Name: func(ctx context.Context, toolCall llm.ToolCall) (llm.Message, []value.Recipe, error) {
-			return HandleRecipeRandomSearch(ctx, a.searcher, toolCall, recipesRecentlyUsed)
+			exclusions.addSearch(toolCall.Args)
+			return HandleRecipeRandomSearch(ctx, a.searcher, toolCall, baseFilter)
 		},
 		submitMealProposalTool.Name: func(ctx context.Context, toolCall llm.ToolCall) (llm.Message, []value.Recipe, error) {
 			b, err := json.Marshal(toolCall.Args)
@@ -220,7 +226,8 @@ func (a *Analyst) Run(
 	proposal := buildMealProposal(*raw, recipeLookup, planingCtx)
 
 	return AnalystResult{
-		Proposal: proposal,
+		Proposal:   proposal,
+		Exclusions: exclusions,
 		Meta: shared.AgentMeta{
 			AgentName: "Analyst",
 			Usage:     resp.Usage,
@@ -50,7 +50,7 @@ func TestPlanReviewer_BugReproduction_LiveEval(t *testing.T) {
 
 	// 4. Run the Agent
 	t.Log("Executing PlanReviewer Agent...")
-	result, err := reviewer.Run(ctx, currentPlan, userRequest, feedback, pCtx, recipesRecentlyUsed)
+	result, err := reviewer.Run(ctx, currentPlan, userRequest, feedback, nil, pCtx, recipesRecentlyUsed)
 	if err != nil {
 		t.Fatalf("PlanReviewer failed to respond: %v", err)
 	}
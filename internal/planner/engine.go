@@ -139,6 +139,44 @@ var searchRecipesSemanticTool = llm.Tool{
 					Type: llm.PropertyTypeString,
 				},
 			},
+			"exclude_ingredients": {
+				Type:        llm.PropertyTypeArray,
+				Description: "A list of ingredients (in English) that no returned recipe may contain, checked against each recipe's ingredient list (e.g., ['shrimp', 'peanut']). Use it for allergies and dislikes, together with exclude_tags. MUST be an array. Omit when there is nothing to exclude.",
+				Items: &llm.Property{
+					Type: llm.PropertyTypeString,
+				},
+			},
+			"max_prep_minutes": {
+				Type:        llm.PropertyTypeNumber,
+				Description: "Only return recipes that take at most this many minutes in total (e.g., 30 for quick weeknight meals). Recipes with an unknown time are left out. Omit when there is no time limit.",
+			},
+			"min_servings": {
+				Type:        llm.PropertyTypeNumber,
+				Description: "Only return recipes that serve at least this many people. Recipes with an unknown yield are left out. Omit when any size works.",
+			},
+			"include_tags": {
+				Type:        llm.PropertyTypeArray,
+				Description: "A list of tags (in English) that every returned recipe MUST have (e.g., ['vegetarian', 'kid-friendly']). MUST be an array. Omit when no tag is required.",
+				Items: &llm.Property{
+					Type: llm.PropertyTypeString,
+				},
+			},
+			"meal_type": {
+				Type:        llm.PropertyTypeString,
+				Description: "Only return recipes of this meal type: 'breakfast', 'main', 'side', 'dessert', 'snack' or 'drink'. Omit to search all types.",
+			},
+			"cuisine": {
+				Type:        llm.PropertyTypeString,
+				Description: "Only return recipes of this cuisine, in English and lowercase (e.g., 'brazilian', 'italian'). Omit to search all cuisines.",
+			},
+			"main_protein": {
+				Type:        llm.PropertyTypeString,
+				Description: "Only return recipes whose main protein is this one: 'chicken', 'beef', 'pork', 'fish', 'seafood', 'eggs', 'legumes', 'tofu', 'cheese' or 'none'. Omit when any protein works.",
+			},
+			"not_cooked_weeks": {
+				Type:        llm.PropertyTypeNumber,
+				Description: "Skip recipes the user has had in a confirmed plan during the last N weeks (e.g., 4). Omit to allow recent recipes.",
+			},
 			"reasoning": {
 				Type:        llm.PropertyTypeString,
 				Description: "A brief explanation of why you are running this search and what you hope to find based on previous results.",
@@ -153,37 +191,72 @@ func simplifyForTool(recipes []value.Recipe) []value.Recipe {
 	content := []value.Recipe{} // Initialize to avoid nil marshaling to "null" if preferred, though "[]" is better
 	for _, r := range recipes {
 		content = append(content, value.Recipe{
-			ID:       r.ID,
-			Title:    r.Title,
-			PrepTime: r.PrepTime,
-			Tags:     r.Tags,
-			Servings: r.Servings,
+			ID:           r.ID,
+			Title:        r.Title,
+			PrepTime:     r.PrepTime,
+			TotalMinutes: r.TotalMinutes,
+			Tags:         r.Tags,
+			Servings:     r.Servings,
+			Yield:        r.Yield,
 		})
 	}
 	return content
 }
 
+// recipeFilterFromArgs reads the filter parameters shared by the search tools on top of base,
+// which carries the recently used recipes and the user the search runs for.
+func recipeFilterFromArgs(args map[string]any, base shared.RecipeFilter) shared.RecipeFilter {
+	filter := base
+	filter.ExcludeTags = append(filter.ExcludeTags, stringsFromArg(args["exclude_tags"])...)
+	filter.IncludeTags = append(filter.IncludeTags, stringsFromArg(args["include_tags"])...)
+	filter.ExcludeIngredients = append(filter.ExcludeIngredients, stringsFromArg(args["exclude_ingredients"])...)
+
+	if val, ok := args["max_prep_minutes"].(float64); ok {
+		filter.MaxPrepMinutes = int(val)
+	}
+	if val, ok := args["min_servings"].(float64); ok {
+		filter.MinServings = int(val)
+	}
+	if val, ok := args["not_cooked_weeks"].(float64); ok {
+		filter.NotCookedWeeks = int(val)
+	}
+	if val, ok := args["meal_type"].(string); ok {
+		filter.MealType = val
+	}
+	if val, ok := args["cuisine"].(string); ok {
+		filter.Cuisine = val
+	}
+	if val, ok := args["main_protein"].(string); ok {
+		filter.MainProtein = val
+	}
+
+	return filter
+}
+
+// stringsFromArg reads a tool argument that should be an array of strings, skipping anything else.
+func stringsFromArg(arg any) []string {
+	var result []string
+	if values, ok := arg.([]interface{}); ok {
+		for _, v := range values {
+			if str, ok := v.(string); ok {
+				result = append(result, str)
+			}
+		}
+	}
+	return result
+}
+
 // HandleRecipeSemanticSearch executes the search_recipes tool and formats the result as an LLM message.
 func HandleRecipeSemanticSearch(
 	ctx context.Context,
 	searcher shared.RecipeSearcher,
 	toolCall llm.ToolCall,
-	recipesRecentlyUsed []string,
+	base shared.RecipeFilter,
 ) (llm.Message, []value.Recipe, error) {
-	var excludeTags []string
-	if tags, ok := toolCall.Args["exclude_tags"].([]interface{}); ok {
-		for _, tag := range tags {
-			if strTag, ok := tag.(string); ok {
-				excludeTags = append(excludeTags, strTag)
-			}
-		}
-	}
-
 	recipes, err := searcher.RecipeSemanticSearch(
 		ctx,
 		toolCall.Args["query"].(string),
-		recipesRecentlyUsed,
-		excludeTags, // Passed down!
+		recipeFilterFromArgs(toolCall.Args, base),
 	)
 	if err != nil {
 		return llm.Message{}, nil, err
@@ -220,6 +293,44 @@ var searchRecipesRandomTool = llm.Tool{
 					Type: llm.PropertyTypeString,
 				},
 			},
+			"exclude_ingredients": {
+				Type:        llm.PropertyTypeArray,
+				Description: "A list of ingredients (in English) that no returned recipe may contain, checked against each recipe's ingredient list (e.g., ['shrimp', 'peanut']). Use it for allergies and dislikes, together with exclude_tags. MUST be an array. Omit when there is nothing to exclude.",
+				Items: &llm.Property{
+					Type: llm.PropertyTypeString,
+				},
+			},
+			"max_prep_minutes": {
+				Type:        llm.PropertyTypeNumber,
+				Description: "Only return recipes that take at most this many minutes in total (e.g., 30 for quick weeknight meals). Recipes with an unknown time are left out. Omit when there is no time limit.",
+			},
+			"min_servings": {
+				Type:        llm.PropertyTypeNumber,
+				Description: "Only return recipes that serve at least this many people. Recipes with an unknown yield are left out. Omit when any size works.",
+			},
+			"include_tags": {
+				Type:        llm.PropertyTypeArray,
+				Description: "A list of tags (in English) that every returned recipe MUST have (e.g., ['vegetarian', 'kid-friendly']). MUST be an array. Omit when no tag is required.",
+				Items: &llm.Property{
+					Type: llm.PropertyTypeString,
+				},
+			},
+			"meal_type": {
+				Type:        llm.PropertyTypeString,
+				Description: "Only return recipes of this meal type: 'breakfast', 'main', 'side', 'dessert', 'snack' or 'drink'. Omit to search all types.",
+			},
+			"cuisine": {
+				Type:        llm.PropertyTypeString,
+				Description: "Only return recipes of this cuisine, in English and lowercase (e.g., 'brazilian', 'italian'). Omit to search all cuisines.",
+			},
+			"main_protein": {
+				Type:        llm.PropertyTypeString,
+				Description: "Only return recipes whose main protein is this one: 'chicken', 'beef', 'pork', 'fish', 'seafood', 'eggs', 'legumes', 'tofu', 'cheese' or 'none'. Omit when any protein works.",
+			},
+			"not_cooked_weeks": {
+				Type:        llm.PropertyTypeNumber,
+				Description: "Skip recipes the user has had in a confirmed plan during the last N weeks (e.g., 4). Omit to allow recent recipes.",
+			},
 			"reasoning": {
 				Type:        llm.PropertyTypeString,
 				Description: "A brief explanation of why you are running this search and what you hope to find based on previous results.",
@@ -233,27 +344,17 @@ func HandleRecipeRandomSearch(
 	ctx context.Context,
 	searcher shared.RecipeSearcher,
 	toolCall llm.ToolCall,
-	recipesRecentlyUsed []string,
+	base shared.RecipeFilter,
 ) (llm.Message, []value.Recipe, error) {
 	limit := int64(10)
 	if val, ok := toolCall.Args["limit"].(float64); ok {
 		limit = int64(val)
 	}
 
-	var excludeTags []string
-	if tags, ok := toolCall.Args["exclude_tags"].([]interface{}); ok {
-		for _, tag := range tags {
-			if strTag, ok := tag.(string); ok {
-				excludeTags = append(excludeTags, strTag)
-			}
-		}
-	}
-
 	recipes, err := searcher.RandomRecipes(
 		ctx,
 		limit,
-		recipesRecentlyUsed,
-		excludeTags, // Passed down!
+		recipeFilterFromArgs(toolCall.Args, base),
 	)
 	if err != nil {
 		return llm.Message{}, nil, err
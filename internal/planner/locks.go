@@ -0,0 +1,38 @@
+package planner
+
+import "fmt"
+
+// VersionAuthorLock marks plan versions that only lock or unlock days.
+const VersionAuthorLock = "Lock"
+
+// LockedDays lists the names of the days the reviewer must leave as they are.
+func (p *MealPlan) LockedDays() []string {
+	var days []string
+	for _, day := range p.Plan {
+		if day.Locked {
+			days = append(days, day.Day)
+		}
+	}
+	return days
+}
+
+// ToggleLock locks the Cook day at dayIndex together with the Reuse days that eat its
+// leftovers, or unlocks them all when the Cook day is already locked. It reports
+// whether the days are now locked.
+func ToggleLock(plan *MealPlan, dayIndex int) (bool, error) {
+	if dayIndex < 0 || dayIndex >= len(plan.Plan) {
+		return false, fmt.Errorf("day %d is outside the plan", dayIndex)
+	}
+	cook := plan.Plan[dayIndex]
+	if cook.RecipeID == "" || cook.IsReuse() {
+		return false, fmt.Errorf("%s is not a Cook day", cook.Day)
+	}
+
+	locked := !cook.Locked
+	for i, day := range plan.Plan {
+		if i == dayIndex || (day.RecipeID == cook.RecipeID && day.IsReuse()) {
+			plan.Plan[i].Locked = locked
+		}
+	}
+	return locked, nil
+}
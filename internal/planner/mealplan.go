@@ -1,6 +1,13 @@
 package planner
 
-import "time"
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"ai-meal-planner/internal/shared"
+)
 
 // PlanStatus represents the lifecycle state of a meal plan.
 type PlanStatus string
@@ -9,6 +16,8 @@ const (
 	StatusDraft     PlanStatus = "DRAFT"
 	StatusFinal     PlanStatus = "FINAL"
 	StatusAdjusting PlanStatus = "ADJUSTING"
+	// StatusReplaced marks a confirmed plan superseded by a newer plan confirmed for its week
+	StatusReplaced PlanStatus = "REPLACED"
 )
 
 // DayPlan represents the plan for a single day.
@@ -19,14 +28,85 @@ type DayPlan struct {
 	SideDishes  []string `json:"side_dishes,omitempty"`
 	PrepTime    string   `json:"prep_time"`
 	Note        string   `json:"note"`
+	Locked      bool     `json:"locked,omitempty"` // Kept as is when the plan is revised
+}
+
+// IsReuse reports whether the day eats leftovers from an earlier Cook day.
+// The Chef labels those titles "Leftovers: <recipe>".
+func (d DayPlan) IsReuse() bool {
+	title := strings.ToLower(d.RecipeTitle)
+	return strings.Contains(title, "leftover") || strings.Contains(title, "reuse")
+}
+
+// weekdayOffsets maps the day names the Chef writes to their offset from the plan's Monday.
+var weekdayOffsets = map[string]int{
+	"monday":    0,
+	"tuesday":   1,
+	"wednesday": 2,
+	"thursday":  3,
+	"friday":    4,
+	"saturday":  5,
+	"sunday":    6,
 }
 
 // MealPlan represents a full weekly meal plan.
 type MealPlan struct {
-	ID              int64      `json:"id,omitempty"` // Database ID for referencing
+	ID              int64      `json:"id,omitempty"`      // Database ID for referencing
+	Version         int        `json:"version,omitempty"` // Current version number of the plan
 	WeekStart       time.Time  `json:"week_start"`
 	Status          PlanStatus `json:"status"`
 	Plan            []DayPlan  `json:"plan"`
 	ShoppingList    []string   `json:"shopping_list,omitempty"` // Optional, only populated for FINAL plans
 	OriginalRequest string     `json:"original_request,omitempty"`
+	// Exclusions keep the request's constraints for searches made after the plan was drafted
+	Exclusions PlanExclusions `json:"exclusions,omitzero"`
+}
+
+// PlanExclusions are the tags and ingredients the searches behind a plan ruled out, so
+// later changes to the plan, like swapping a day, rule them out too.
+type PlanExclusions struct {
+	Tags        []string `json:"tags,omitempty"`
+	Ingredients []string `json:"ingredients,omitempty"`
+}
+
+// addSearch records the exclude_tags and exclude_ingredients of a search tool call.
+func (e *PlanExclusions) addSearch(args map[string]any) {
+	for _, tag := range stringsFromArg(args["exclude_tags"]) {
+		if !slices.ContainsFunc(e.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			e.Tags = append(e.Tags, tag)
+		}
+	}
+	for _, ingredient := range stringsFromArg(args["exclude_ingredients"]) {
+		if !slices.ContainsFunc(e.Ingredients, func(i string) bool { return strings.EqualFold(i, ingredient) }) {
+			e.Ingredients = append(e.Ingredients, ingredient)
+		}
+	}
+}
+
+// apply returns filter with the exclusions added.
+func (e PlanExclusions) apply(filter shared.RecipeFilter) shared.RecipeFilter {
+	filter.ExcludeTags = append(slices.Clone(filter.ExcludeTags), e.Tags...)
+	filter.ExcludeIngredients = append(slices.Clone(filter.ExcludeIngredients), e.Ingredients...)
+	return filter
+}
+
+// DayDate returns the date of the i-th plan day, matched by the weekday name the slot
+// starts with ("Saturday (Lunch)" is a Saturday) and falling back to the day's position,
+// capped at Sunday, when the name is not recognized.
+func (p *MealPlan) DayDate(i int) time.Time {
+	offset, ok := weekdayOffset(p.Plan[i].Day)
+	if !ok {
+		offset = min(i, 6)
+	}
+	return p.WeekStart.AddDate(0, 0, offset)
+}
+
+// weekdayOffset reads the weekday a slot name starts with.
+func weekdayOffset(day string) (int, bool) {
+	words := strings.FieldsFunc(strings.ToLower(day), func(r rune) bool { return !unicode.IsLetter(r) })
+	if len(words) == 0 {
+		return 0, false
+	}
+	offset, ok := weekdayOffsets[words[0]]
+	return offset, ok
 }
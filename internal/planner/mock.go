@@ -1,27 +1,32 @@
 package planner
 
 import (
+	"ai-meal-planner/internal/shared"
 	"ai-meal-planner/internal/value"
 	"context"
 )
 
 type mockSearcher struct {
 	recipes []value.Recipe
+	// filters records the filter of every search, oldest first
+	filters []shared.RecipeFilter
 }
 
 func (m *mockSearcher) GetByIds(ctx context.Context, recipeIds []string) ([]value.Recipe, error) {
 	return m.recipes, nil
 }
 
-func (m *mockSearcher) RandomRecipes(ctx context.Context, limit int64, excludeIDs []string, excludeTags []string) ([]value.Recipe, error) {
-	return m.recipes, nil
+func (m *mockSearcher) RandomRecipes(ctx context.Context, limit int64, filter shared.RecipeFilter) ([]value.Recipe, error) {
+	return m.RecipeSemanticSearch(ctx, "", filter)
 }
 
-func (m *mockSearcher) RecipeSemanticSearch(ctx context.Context, query string, excludeIDs []string, excludeTags []string) ([]value.Recipe, error) {
+func (m *mockSearcher) RecipeSemanticSearch(ctx context.Context, query string, filter shared.RecipeFilter) ([]value.Recipe, error) {
+	m.filters = append(m.filters, filter)
+
 	// Filter out excluded IDs manually to simulate real DB behavior
 	var filtered []value.Recipe
 	excludedMap := make(map[string]bool)
-	for _, id := range excludeIDs {
+	for _, id := range filter.ExcludeIDs {
 		excludedMap[id] = true
 	}
 
@@ -7,24 +7,22 @@ package plandb
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
-const checkPlanExists = `-- name: CheckPlanExists :one
-SELECT COUNT(*) FROM user_meal_plans
-WHERE user_id = ? AND week_start_date = ?
+const confirmDraftPlan = `-- name: ConfirmDraftPlan :execrows
+UPDATE user_meal_plans
+SET status = 'FINAL'
+WHERE id = ? AND status = 'DRAFT'
 `
 
-type CheckPlanExistsParams struct {
-	UserID        string
-	WeekStartDate time.Time
-}
-
-func (q *Queries) CheckPlanExists(ctx context.Context, arg CheckPlanExistsParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, checkPlanExists, arg.UserID, arg.WeekStartDate)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+func (q *Queries) ConfirmDraftPlan(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, confirmDraftPlan, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteOldMealPlansByUserID = `-- name: DeleteOldMealPlansByUserID :exec
@@ -42,8 +40,53 @@ func (q *Queries) DeleteOldMealPlansByUserID(ctx context.Context, arg DeleteOldM
 	return err
 }
 
+const deleteOldPlanVersionsByUserID = `-- name: DeleteOldPlanVersionsByUserID :exec
+DELETE FROM meal_plan_versions
+WHERE meal_plan_id IN (
+    SELECT id FROM user_meal_plans WHERE user_id = ? AND week_start_date < ?
+)
+`
+
+type DeleteOldPlanVersionsByUserIDParams struct {
+	UserID        string
+	WeekStartDate time.Time
+}
+
+// Run before DeleteOldMealPlansByUserID: nothing cascades to meal_plan_versions.
+func (q *Queries) DeleteOldPlanVersionsByUserID(ctx context.Context, arg DeleteOldPlanVersionsByUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOldPlanVersionsByUserID, arg.UserID, arg.WeekStartDate)
+	return err
+}
+
+const getCurrentPlanByUserAndWeek = `-- name: GetCurrentPlanByUserAndWeek :one
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE user_id = ? AND week_start_date = ?
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetCurrentPlanByUserAndWeekParams struct {
+	UserID        string
+	WeekStartDate time.Time
+}
+
+func (q *Queries) GetCurrentPlanByUserAndWeek(ctx context.Context, arg GetCurrentPlanByUserAndWeekParams) (UserMealPlan, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentPlanByUserAndWeek, arg.UserID, arg.WeekStartDate)
+	var i UserMealPlan
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PlanData,
+		&i.WeekStartDate,
+		&i.Status,
+		&i.CreatedAt,
+		&i.CurrentVersion,
+	)
+	return i, err
+}
+
 const getDraftPlanByUserAndWeek = `-- name: GetDraftPlanByUserAndWeek :one
-SELECT id, user_id, plan_data, week_start_date, status, created_at FROM user_meal_plans
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
 WHERE user_id = ? AND week_start_date = ? AND status = 'DRAFT'
 LIMIT 1
 `
@@ -63,12 +106,52 @@ func (q *Queries) GetDraftPlanByUserAndWeek(ctx context.Context, arg GetDraftPla
 		&i.WeekStartDate,
 		&i.Status,
 		&i.CreatedAt,
+		&i.CurrentVersion,
+	)
+	return i, err
+}
+
+const getFinalPlanByUserAndWeek = `-- name: GetFinalPlanByUserAndWeek :one
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE user_id = ? AND week_start_date = ? AND status = 'FINAL'
+ORDER BY id DESC
+LIMIT 1
+`
+
+type GetFinalPlanByUserAndWeekParams struct {
+	UserID        string
+	WeekStartDate time.Time
+}
+
+func (q *Queries) GetFinalPlanByUserAndWeek(ctx context.Context, arg GetFinalPlanByUserAndWeekParams) (UserMealPlan, error) {
+	row := q.db.QueryRowContext(ctx, getFinalPlanByUserAndWeek, arg.UserID, arg.WeekStartDate)
+	var i UserMealPlan
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PlanData,
+		&i.WeekStartDate,
+		&i.Status,
+		&i.CreatedAt,
+		&i.CurrentVersion,
 	)
 	return i, err
 }
 
+const getLatestPlanVersion = `-- name: GetLatestPlanVersion :one
+SELECT CAST(COALESCE(MAX(version), 0) AS INTEGER) FROM meal_plan_versions
+WHERE meal_plan_id = ?
+`
+
+func (q *Queries) GetLatestPlanVersion(ctx context.Context, mealPlanID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLatestPlanVersion, mealPlanID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const getMealPlanByID = `-- name: GetMealPlanByID :one
-SELECT id, user_id, plan_data, week_start_date, status, created_at FROM user_meal_plans
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
 WHERE id = ?
 `
 
@@ -82,6 +165,74 @@ func (q *Queries) GetMealPlanByID(ctx context.Context, id int64) (UserMealPlan,
 		&i.WeekStartDate,
 		&i.Status,
 		&i.CreatedAt,
+		&i.CurrentVersion,
+	)
+	return i, err
+}
+
+const getMealPlanByIDForUser = `-- name: GetMealPlanByIDForUser :one
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE id = ? AND user_id = ?
+`
+
+type GetMealPlanByIDForUserParams struct {
+	ID     int64
+	UserID string
+}
+
+func (q *Queries) GetMealPlanByIDForUser(ctx context.Context, arg GetMealPlanByIDForUserParams) (UserMealPlan, error) {
+	row := q.db.QueryRowContext(ctx, getMealPlanByIDForUser, arg.ID, arg.UserID)
+	var i UserMealPlan
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PlanData,
+		&i.WeekStartDate,
+		&i.Status,
+		&i.CreatedAt,
+		&i.CurrentVersion,
+	)
+	return i, err
+}
+
+const getPlanVersion = `-- name: GetPlanVersion :one
+SELECT meal_plan_id, version, parent_version, author, feedback, plan_data, created_at FROM meal_plan_versions
+WHERE meal_plan_id = ? AND version = ?
+`
+
+type GetPlanVersionParams struct {
+	MealPlanID int64
+	Version    int64
+}
+
+func (q *Queries) GetPlanVersion(ctx context.Context, arg GetPlanVersionParams) (MealPlanVersion, error) {
+	row := q.db.QueryRowContext(ctx, getPlanVersion, arg.MealPlanID, arg.Version)
+	var i MealPlanVersion
+	err := row.Scan(
+		&i.MealPlanID,
+		&i.Version,
+		&i.ParentVersion,
+		&i.Author,
+		&i.Feedback,
+		&i.PlanData,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserSettings = `-- name: GetUserSettings :one
+SELECT user_id, repeat_window_weeks, favorite_repeat_weeks, updated_at FROM user_settings
+WHERE user_id = ?
+`
+
+func (q *Queries) GetUserSettings(ctx context.Context, userID string) (UserSetting, error) {
+	row := q.db.QueryRowContext(ctx, getUserSettings, userID)
+	var i UserSetting
+	err := row.Scan(
+		&i.UserID,
+		&i.RepeatWindowWeeks,
+		&i.FavoriteRepeatWeeks,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -113,8 +264,115 @@ func (q *Queries) InsertMealPlan(ctx context.Context, arg InsertMealPlanParams)
 	return id, err
 }
 
+const insertPlanVersion = `-- name: InsertPlanVersion :exec
+INSERT INTO meal_plan_versions (meal_plan_id, version, parent_version, author, feedback, plan_data, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertPlanVersionParams struct {
+	MealPlanID    int64
+	Version       int64
+	ParentVersion sql.NullInt64
+	Author        string
+	Feedback      string
+	PlanData      string
+	CreatedAt     time.Time
+}
+
+func (q *Queries) InsertPlanVersion(ctx context.Context, arg InsertPlanVersionParams) error {
+	_, err := q.db.ExecContext(ctx, insertPlanVersion,
+		arg.MealPlanID,
+		arg.Version,
+		arg.ParentVersion,
+		arg.Author,
+		arg.Feedback,
+		arg.PlanData,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const listFinalPlansSince = `-- name: ListFinalPlansSince :many
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
+WHERE user_id = ? AND status = 'FINAL' AND week_start_date >= ?
+ORDER BY week_start_date DESC
+`
+
+type ListFinalPlansSinceParams struct {
+	UserID string
+	Since  time.Time
+}
+
+func (q *Queries) ListFinalPlansSince(ctx context.Context, arg ListFinalPlansSinceParams) ([]UserMealPlan, error) {
+	rows, err := q.db.QueryContext(ctx, listFinalPlansSince, arg.UserID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserMealPlan
+	for rows.Next() {
+		var i UserMealPlan
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PlanData,
+			&i.WeekStartDate,
+			&i.Status,
+			&i.CreatedAt,
+			&i.CurrentVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlanVersions = `-- name: ListPlanVersions :many
+SELECT meal_plan_id, version, parent_version, author, feedback, plan_data, created_at FROM meal_plan_versions
+WHERE meal_plan_id = ?
+ORDER BY version
+`
+
+func (q *Queries) ListPlanVersions(ctx context.Context, mealPlanID int64) ([]MealPlanVersion, error) {
+	rows, err := q.db.QueryContext(ctx, listPlanVersions, mealPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MealPlanVersion
+	for rows.Next() {
+		var i MealPlanVersion
+		if err := rows.Scan(
+			&i.MealPlanID,
+			&i.Version,
+			&i.ParentVersion,
+			&i.Author,
+			&i.Feedback,
+			&i.PlanData,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRecentMealPlansByUserID = `-- name: ListRecentMealPlansByUserID :many
-SELECT id, user_id, plan_data, week_start_date, status, created_at FROM user_meal_plans
+SELECT id, user_id, plan_data, week_start_date, status, created_at, current_version FROM user_meal_plans
 WHERE user_id = ?
 ORDER BY week_start_date DESC
 LIMIT ?
@@ -141,6 +399,7 @@ func (q *Queries) ListRecentMealPlansByUserID(ctx context.Context, arg ListRecen
 			&i.WeekStartDate,
 			&i.Status,
 			&i.CreatedAt,
+			&i.CurrentVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -155,6 +414,46 @@ func (q *Queries) ListRecentMealPlansByUserID(ctx context.Context, arg ListRecen
 	return items, nil
 }
 
+const replaceOtherFinalPlans = `-- name: ReplaceOtherFinalPlans :exec
+UPDATE user_meal_plans
+SET status = 'REPLACED'
+WHERE user_id = ? AND week_start_date = ? AND status = 'FINAL' AND id != ?
+`
+
+type ReplaceOtherFinalPlansParams struct {
+	UserID        string
+	WeekStartDate time.Time
+	ID            int64
+}
+
+func (q *Queries) ReplaceOtherFinalPlans(ctx context.Context, arg ReplaceOtherFinalPlansParams) error {
+	_, err := q.db.ExecContext(ctx, replaceOtherFinalPlans, arg.UserID, arg.WeekStartDate, arg.ID)
+	return err
+}
+
+const setCurrentPlanVersion = `-- name: SetCurrentPlanVersion :exec
+UPDATE user_meal_plans
+SET plan_data = ?, status = ?, current_version = ?
+WHERE id = ?
+`
+
+type SetCurrentPlanVersionParams struct {
+	PlanData       string
+	Status         string
+	CurrentVersion int64
+	ID             int64
+}
+
+func (q *Queries) SetCurrentPlanVersion(ctx context.Context, arg SetCurrentPlanVersionParams) error {
+	_, err := q.db.ExecContext(ctx, setCurrentPlanVersion,
+		arg.PlanData,
+		arg.Status,
+		arg.CurrentVersion,
+		arg.ID,
+	)
+	return err
+}
+
 const updatePlanStatus = `-- name: UpdatePlanStatus :exec
 UPDATE user_meal_plans
 SET status = ?
@@ -170,3 +469,29 @@ func (q *Queries) UpdatePlanStatus(ctx context.Context, arg UpdatePlanStatusPara
 	_, err := q.db.ExecContext(ctx, updatePlanStatus, arg.Status, arg.ID)
 	return err
 }
+
+const upsertUserSettings = `-- name: UpsertUserSettings :exec
+INSERT INTO user_settings (user_id, repeat_window_weeks, favorite_repeat_weeks, updated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    repeat_window_weeks = excluded.repeat_window_weeks,
+    favorite_repeat_weeks = excluded.favorite_repeat_weeks,
+    updated_at = excluded.updated_at
+`
+
+type UpsertUserSettingsParams struct {
+	UserID              string
+	RepeatWindowWeeks   int64
+	FavoriteRepeatWeeks int64
+	UpdatedAt           time.Time
+}
+
+func (q *Queries) UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserSettings,
+		arg.UserID,
+		arg.RepeatWindowWeeks,
+		arg.FavoriteRepeatWeeks,
+		arg.UpdatedAt,
+	)
+	return err
+}
@@ -2,10 +2,12 @@ package planner
 
 import (
 	db "ai-meal-planner/internal/planner/plan_db"
+	"ai-meal-planner/internal/shared"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -23,32 +25,61 @@ func NewPlanRepository(d *sql.DB) *PlanRepository {
 	}
 }
 
-// Save inserts a new meal plan into the database and returns its ID.
+// Save inserts a new meal plan into the database, with planData as its first version,
+// and returns its ID. Use SaveVersion to revise the plan of a week that already has one.
 func (r *PlanRepository) Save(ctx context.Context, userID string, planData *MealPlan) (int64, error) {
-	planJSON, err := json.Marshal(planData)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to marshal meal plan to JSON for saving: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
+
+	id, err := insertPlan(ctx, r.queries.WithTx(tx), userID, planData, "", "")
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit meal plan: %w", err)
+	}
+	return id, nil
+}
 
+// insertPlan creates the plan row for planData's week together with version 1.
+func insertPlan(ctx context.Context, queries *db.Queries, userID string, planData *MealPlan, author, feedback string) (int64, error) {
 	// Default to DRAFT status if not set
-	status := string(planData.Status)
-	if status == "" {
-		status = string(StatusDraft)
+	if planData.Status == "" {
+		planData.Status = StatusDraft
 	}
+	planData.Version = 1
 
-	params := db.InsertMealPlanParams{
+	planJSON, err := json.Marshal(planData)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal meal plan to JSON for saving: %w", err)
+	}
+
+	now := time.Now().UTC()
+	id, err := queries.InsertMealPlan(ctx, db.InsertMealPlanParams{
 		UserID:        userID,
 		PlanData:      string(planJSON),
 		WeekStartDate: planData.WeekStart,
-		Status:        status,
-		CreatedAt:     time.Now().UTC(),
-	}
-
-	id, err := r.queries.InsertMealPlan(ctx, params)
+		Status:        string(planData.Status),
+		CreatedAt:     now,
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert meal plan: %w", err)
 	}
 
+	if err := queries.InsertPlanVersion(ctx, db.InsertPlanVersionParams{
+		MealPlanID: id,
+		Version:    1,
+		Author:     author,
+		Feedback:   feedback,
+		PlanData:   string(planJSON),
+		CreatedAt:  now,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to insert first version of meal plan %d: %w", id, err)
+	}
+
 	planData.ID = id
 	return id, nil
 }
@@ -70,6 +101,7 @@ func (r *PlanRepository) ListRecentByUserID(ctx context.Context, userID string,
 			plan.ID = dbPlan.ID
 			plan.WeekStart = dbPlan.WeekStartDate
 			plan.Status = PlanStatus(dbPlan.Status)
+			plan.Version = int(dbPlan.CurrentVersion)
 			mealPlans = append(mealPlans, plan)
 		}
 	}
@@ -85,7 +117,26 @@ func (r *PlanRepository) GetByID(ctx context.Context, id int64) (*MealPlan, erro
 		}
 		return nil, fmt.Errorf("failed to get meal plan by ID: %w", err)
 	}
+	return planFromRow(dbPlan)
+}
+
+// GetByIDForUser retrieves a user's meal plan by its ID, returning nil if it does not
+// exist or belongs to another user. Use it for IDs that come from chat buttons.
+func (r *PlanRepository) GetByIDForUser(ctx context.Context, id int64, userID string) (*MealPlan, error) {
+	dbPlan, err := r.queries.GetMealPlanByIDForUser(ctx, db.GetMealPlanByIDForUserParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get meal plan by ID: %w", err)
+	}
+	return planFromRow(dbPlan)
+}
 
+func planFromRow(dbPlan db.UserMealPlan) (*MealPlan, error) {
 	plan := &MealPlan{}
 	if err := json.Unmarshal([]byte(dbPlan.PlanData), plan); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal meal plan: %w", err)
@@ -94,6 +145,7 @@ func (r *PlanRepository) GetByID(ctx context.Context, id int64) (*MealPlan, erro
 	plan.ID = dbPlan.ID
 	plan.WeekStart = dbPlan.WeekStartDate
 	plan.Status = PlanStatus(dbPlan.Status)
+	plan.Version = int(dbPlan.CurrentVersion)
 
 	return plan, nil
 }
@@ -110,17 +162,7 @@ func (r *PlanRepository) GetDraftByUserAndWeek(ctx context.Context, userID strin
 		}
 		return nil, fmt.Errorf("failed to get draft plan: %w", err)
 	}
-
-	plan := &MealPlan{}
-	if err := json.Unmarshal([]byte(dbPlan.PlanData), plan); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal meal plan: %w", err)
-	}
-
-	plan.ID = dbPlan.ID
-	plan.WeekStart = dbPlan.WeekStartDate
-	plan.Status = PlanStatus(dbPlan.Status)
-
-	return plan, nil
+	return planFromRow(dbPlan)
 }
 
 // UpdateStatus updates the status of a meal plan.
@@ -133,12 +175,173 @@ func (r *PlanRepository) UpdateStatus(ctx context.Context, id int64, status Plan
 
 // ExistsForWeek checks if a plan already exists for a user on a given week.
 func (r *PlanRepository) ExistsForWeek(ctx context.Context, userID string, weekStart time.Time) (bool, error) {
-	count, err := r.queries.CheckPlanExists(ctx, db.CheckPlanExistsParams{
+	plan, err := r.GetCurrentByUserAndWeek(ctx, userID, weekStart)
+	if err != nil {
+		return false, err
+	}
+	return plan != nil, nil
+}
+
+// GetCurrentByUserAndWeek retrieves the current version of the user's plan for a week,
+// whatever its status. It returns nil when the week has no plan.
+func (r *PlanRepository) GetCurrentByUserAndWeek(ctx context.Context, userID string, weekStart time.Time) (*MealPlan, error) {
+	dbPlan, err := r.queries.GetCurrentPlanByUserAndWeek(ctx, db.GetCurrentPlanByUserAndWeekParams{
 		UserID:        userID,
 		WeekStartDate: weekStart,
 	})
 	if err != nil {
-		return false, err
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get plan for week: %w", err)
+	}
+	return planFromRow(dbPlan)
+}
+
+// GetFinalByUserAndWeek retrieves the user's confirmed plan for a week. A week keeps its
+// confirmed plan while a regenerated draft waits for confirmation. It returns nil when
+// the week has no confirmed plan.
+func (r *PlanRepository) GetFinalByUserAndWeek(ctx context.Context, userID string, weekStart time.Time) (*MealPlan, error) {
+	dbPlan, err := r.queries.GetFinalPlanByUserAndWeek(ctx, db.GetFinalPlanByUserAndWeekParams{
+		UserID:        userID,
+		WeekStartDate: weekStart,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get confirmed plan for week: %w", err)
+	}
+	return planFromRow(dbPlan)
+}
+
+// Confirm marks the draft FINAL and retires the confirmed plan it replaces for the same week.
+// It returns ErrPlanNotEditable when the plan is not a draft.
+func (r *PlanRepository) Confirm(ctx context.Context, userID string, planID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	queries := r.queries.WithTx(tx)
+
+	plan, err := queries.GetMealPlanByIDForUser(ctx, db.GetMealPlanByIDForUserParams{ID: planID, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("failed to get meal plan %d to confirm: %w", planID, err)
+	}
+	confirmed, err := queries.ConfirmDraftPlan(ctx, planID)
+	if err != nil {
+		return fmt.Errorf("failed to confirm meal plan %d: %w", planID, err)
+	}
+	if confirmed == 0 {
+		return fmt.Errorf("meal plan %d is %s, not a draft: %w", planID, plan.Status, ErrPlanNotEditable)
+	}
+	if err := queries.ReplaceOtherFinalPlans(ctx, db.ReplaceOtherFinalPlansParams{
+		UserID:        userID,
+		WeekStartDate: plan.WeekStartDate,
+		ID:            planID,
+	}); err != nil {
+		return fmt.Errorf("failed to retire plans replaced by meal plan %d: %w", planID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit confirmation of meal plan %d: %w", planID, err)
+	}
+	return nil
+}
+
+// GetRepetitionSettings returns the user's repetition rules, or the defaults when
+// the user never set them.
+func (r *PlanRepository) GetRepetitionSettings(ctx context.Context, userID string) (RepetitionSettings, error) {
+	row, err := r.queries.GetUserSettings(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultRepetitionSettings(), nil
+		}
+		return RepetitionSettings{}, fmt.Errorf("failed to get settings for user %s: %w", userID, err)
+	}
+	return RepetitionSettings{
+		RepeatWindowWeeks:   int(row.RepeatWindowWeeks),
+		FavoriteRepeatWeeks: int(row.FavoriteRepeatWeeks),
+	}, nil
+}
+
+// SaveRepetitionSettings validates and stores the user's repetition rules.
+func (r *PlanRepository) SaveRepetitionSettings(ctx context.Context, userID string, s RepetitionSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	err := r.queries.UpsertUserSettings(ctx, db.UpsertUserSettingsParams{
+		UserID:              userID,
+		RepeatWindowWeeks:   int64(s.RepeatWindowWeeks),
+		FavoriteRepeatWeeks: int64(s.FavoriteRepeatWeeks),
+		UpdatedAt:           time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save settings for user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// ListRecipeUsage returns every recipe in the user's confirmed plans whose week
+// starts between since and until, newest week first. Drafts and plans being
+// adjusted are not counted. Favorite is left for the caller to fill in.
+func (r *PlanRepository) ListRecipeUsage(ctx context.Context, userID string, since, until time.Time) ([]RecipeUsage, error) {
+	rows, err := r.queries.ListFinalPlansSince(ctx, db.ListFinalPlansSinceParams{
+		UserID: userID,
+		Since:  since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipe usage for user %s: %w", userID, err)
 	}
-	return count > 0, nil
+
+	var usage []RecipeUsage
+	for _, row := range rows {
+		if row.WeekStartDate.After(until) {
+			continue
+		}
+		plan, err := planFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range plan.Plan {
+			if day.RecipeID != "" {
+				usage = append(usage, RecipeUsage{RecipeID: day.RecipeID, WeekStart: plan.WeekStart})
+			}
+		}
+	}
+	return usage, nil
+}
+
+// CookedSince returns the Cook days of the user's confirmed plans dated on or after since,
+// most recent first. Leftover days are left out, since that meal was cooked on an earlier day.
+func (r *PlanRepository) CookedSince(ctx context.Context, userID string, since time.Time) ([]shared.CookedMeal, error) {
+	// A week that started up to six days earlier still has days on or after since
+	rows, err := r.queries.ListFinalPlansSince(ctx, db.ListFinalPlansSinceParams{
+		UserID: userID,
+		Since:  since.AddDate(0, 0, -6),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list confirmed plans for user %s: %w", userID, err)
+	}
+
+	var meals []shared.CookedMeal
+	for _, row := range rows {
+		plan, err := planFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		for i, day := range plan.Plan {
+			if day.RecipeID == "" || day.IsReuse() {
+				continue
+			}
+			if cookedAt := plan.DayDate(i); !cookedAt.Before(since) {
+				meals = append(meals, shared.CookedMeal{RecipeID: day.RecipeID, CookedAt: cookedAt})
+			}
+		}
+	}
+	slices.SortStableFunc(meals, func(a, b shared.CookedMeal) int {
+		return b.CookedAt.Compare(a.CookedAt)
+	})
+	return meals, nil
 }
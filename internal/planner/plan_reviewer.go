@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"slices"
 	"time"
 )
 
@@ -56,6 +57,8 @@ type planReviewerUserContextData struct {
 	Children           int
 	ChildrenAges       []int
 	AdjustmentFeedback string
+	EarlierFeedback    []string
+	LockedDays         []string
 }
 
 type PlanReviewerResult struct {
@@ -77,12 +80,14 @@ func NewPlanReviewer(llm llm.TextGenerator, searcher shared.RecipeSearcher) *Pla
 	}
 }
 
-// Run revises a meal plan based on user feedback.
+// Run revises a meal plan based on user feedback. earlierFeedback holds the requests
+// already applied in the same adjustment conversation, oldest first.
 func (r *PlanReviewer) Run(
 	ctx context.Context,
 	currentPlan *MealPlan,
 	userRequest string,
 	adjustmentFeedback string,
+	earlierFeedback []string,
 	planningCtx PlanningContext,
 	recipesRecentlyUsed []string,
 ) (PlanReviewerResult, error) {
@@ -95,6 +100,8 @@ func (r *PlanReviewer) Run(
 		Children:           planningCtx.Children,
 		ChildrenAges:       planningCtx.ChildrenAges,
 		AdjustmentFeedback: adjustmentFeedback,
+		EarlierFeedback:    earlierFeedback,
+		LockedDays:         currentPlan.LockedDays(),
 	})
 	if err != nil {
 		return PlanReviewerResult{}, err
@@ -123,13 +130,21 @@ func (r *PlanReviewer) Run(
 		Plan []DayPlan `json:"plan"`
 	}{}
 
+	// The original request's exclusions still hold, and the feedback's join them
+	baseFilter := currentPlan.Exclusions.apply(shared.RecipeFilter{ExcludeIDs: recipesRecentlyUsed, UserID: planningCtx.UserID})
+	exclusions := currentPlan.Exclusions
+	exclusions.Tags = slices.Clone(exclusions.Tags)
+	exclusions.Ingredients = slices.Clone(exclusions.Ingredients)
+
 	// 2. Setup Tool Handlers
 	handlers := map[string]ToolHandler[[]value.Recipe]{
 		searchRecipesSemanticTool.Name: func(ctx context.Context, toolCall llm.ToolCall) (llm.Message, []value.Recipe, error) {
-			return HandleRecipeSemanticSearch(ctx, r.searcher, toolCall, recipesRecentlyUsed)
+			exclusions.addSearch(toolCall.Args)
+			return HandleRecipeSemanticSearch(ctx, r.searcher, toolCall, baseFilter)
 		},
 		searchRecipesRandomTool.Name: func(ctx context.Context, toolCall llm.ToolCall) (llm.Message, []value.Recipe, error) {
-			return HandleRecipeRandomSearch(ctx, r.searcher, toolCall, recipesRecentlyUsed)
+			exclusions.addSearch(toolCall.Args)
+			return HandleRecipeRandomSearch(ctx, r.searcher, toolCall, baseFilter)
 		},
 		submitRevisedPlanTool.Name: func(ctx context.Context, toolCall llm.ToolCall) (llm.Message, []value.Recipe, error) {
 			b, err := json.Marshal(toolCall.Args)
@@ -189,38 +204,18 @@ func (r *PlanReviewer) Run(
 		}
 	}
 
-	// 5. Build final result and map IDs
+	// 5. Build final result and map IDs, keeping locked days as they were
 	result := &MealPlan{}
-	revisedByDay := make(map[string]DayPlan, len(rawResponse.Plan))
-	for _, day := range rawResponse.Plan {
-		revisedByDay[day.Day] = day
-	}
-	for _, original := range currentPlan.Plan {
-		day, ok := revisedByDay[original.Day]
-		if !ok {
-			return PlanReviewerResult{}, fmt.Errorf("revised plan is missing %s", original.Day)
-		}
-		if recipe, ok := recipeLookup[day.RecipeTitle]; ok {
-			day.RecipeID = recipe.ID
-			if day.PrepTime == "" {
-				day.PrepTime = recipe.PrepTime
-			}
-			if len(day.SideDishes) == 0 {
-				day.SideDishes = recipe.SideDishes
-			}
-		} else {
-			return PlanReviewerResult{}, fmt.Errorf(
-				"revised plan references unknown recipe %q for %s",
-				day.RecipeTitle,
-				original.Day,
-			)
-		}
-		result.Plan = append(result.Plan, day)
+	result.Plan, err = buildRevisedPlan(currentPlan.Plan, rawResponse.Plan, recipeLookup)
+	if err != nil {
+		return PlanReviewerResult{}, err
 	}
 
+	result.ID = currentPlan.ID
 	result.WeekStart = currentPlan.WeekStart
 	result.Status = currentPlan.Status
 	result.OriginalRequest = userRequest
+	result.Exclusions = exclusions
 
 	return PlanReviewerResult{
 		RevisedPlan: result,
@@ -233,6 +228,50 @@ func (r *PlanReviewer) Run(
 	}, nil
 }
 
+// buildRevisedPlan maps the reviewer's days back onto the current plan, in its order,
+// resolving recipe IDs by title. Locked days keep their current meal whatever the
+// reviewer returned for them.
+func buildRevisedPlan(
+	current []DayPlan,
+	revised []DayPlan,
+	recipeLookup map[string]value.Recipe,
+) ([]DayPlan, error) {
+	revisedByDay := make(map[string]DayPlan, len(revised))
+	for _, day := range revised {
+		revisedByDay[day.Day] = day
+	}
+
+	plan := make([]DayPlan, 0, len(current))
+	for _, original := range current {
+		if original.Locked {
+			plan = append(plan, original)
+			continue
+		}
+		day, ok := revisedByDay[original.Day]
+		if !ok {
+			return nil, fmt.Errorf("revised plan is missing %s", original.Day)
+		}
+		recipe, ok := recipeLookup[day.RecipeTitle]
+		if !ok {
+			return nil, fmt.Errorf(
+				"revised plan references unknown recipe %q for %s",
+				day.RecipeTitle,
+				original.Day,
+			)
+		}
+		day.RecipeID = recipe.ID
+		if day.PrepTime == "" {
+			day.PrepTime = recipe.PrepTime
+		}
+		if len(day.SideDishes) == 0 {
+			day.SideDishes = recipe.SideDishes
+		}
+		day.Locked = false
+		plan = append(plan, day)
+	}
+	return plan, nil
+}
+
 func buildPlanReviewerUserContext(
 	data planReviewerUserContextData,
 ) (string, error) {
@@ -0,0 +1,262 @@
+package planner
+
+import (
+	db "ai-meal-planner/internal/planner/plan_db"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Authors of plan versions: the agent that produced the plan, or the action that restored it.
+const (
+	VersionAuthorAnalyst      = "Analyst"
+	VersionAuthorPlanReviewer = "PlanReviewer"
+	VersionAuthorRestore      = "Restore"
+)
+
+// ErrPlanNotEditable is returned for changes to a plan that was confirmed or replaced.
+var ErrPlanNotEditable = errors.New("meal plan can no longer be changed")
+
+// PlanVersion is one saved state of a user's plan for a week.
+type PlanVersion struct {
+	PlanID        int64
+	Version       int
+	ParentVersion int    // Version this one was derived from; 0 for the first version
+	Author        string // VersionAuthor* constant; empty for plans saved before versioning
+	Feedback      string // User request or feedback that produced the version
+	Plan          *MealPlan
+	CreatedAt     time.Time
+}
+
+// DayChange is a day whose meal differs between two versions of a plan.
+// Before or After is the zero DayPlan when the day exists on one side only.
+type DayChange struct {
+	Day    string
+	Before DayPlan
+	After  DayPlan
+}
+
+// SaveVersion records plan as the newest version of the user's plan for its week and
+// makes it the current one. plan.ID selects the plan; when it is zero the week's plan
+// is looked up, and created if the week has none yet or only a confirmed one. A
+// confirmed plan keeps its status: saving it as anything but FINAL returns ErrPlanNotEditable.
+func (r *PlanRepository) SaveVersion(ctx context.Context, userID string, plan *MealPlan, author, feedback string) (int64, error) {
+	return r.saveVersion(ctx, userID, plan, author, feedback, 0)
+}
+
+// saveVersion adds plan as a new version derived from parent, or from the current
+// version when parent is 0.
+func (r *PlanRepository) saveVersion(ctx context.Context, userID string, plan *MealPlan, author, feedback string, parent int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	queries := r.queries.WithTx(tx)
+
+	var current db.UserMealPlan
+	if plan.ID != 0 {
+		current, err = queries.GetMealPlanByID(ctx, plan.ID)
+	} else {
+		current, err = queries.GetCurrentPlanByUserAndWeek(ctx, db.GetCurrentPlanByUserAndWeekParams{
+			UserID:        userID,
+			WeekStartDate: plan.WeekStart,
+		})
+		// A new draft for a confirmed week starts a plan of its own, so the confirmed
+		// plan stays in use until the draft is confirmed
+		if err == sql.ErrNoRows || (err == nil && PlanStatus(current.Status) == StatusFinal && plan.Status != StatusFinal) {
+			id, err := insertPlan(ctx, queries, userID, plan, author, feedback)
+			if err != nil {
+				return 0, err
+			}
+			if err := tx.Commit(); err != nil {
+				return 0, fmt.Errorf("failed to commit meal plan: %w", err)
+			}
+			return id, nil
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get meal plan to version: %w", err)
+	}
+	if current.UserID != userID {
+		return 0, fmt.Errorf("meal plan %d does not belong to user %s", current.ID, userID)
+	}
+	// A confirmed plan only takes versions that keep it confirmed, and a replaced one none
+	if stored := PlanStatus(current.Status); stored == StatusReplaced || (stored == StatusFinal && plan.Status != StatusFinal) {
+		return 0, fmt.Errorf("meal plan %d is %s: %w", current.ID, stored, ErrPlanNotEditable)
+	}
+
+	latest, err := queries.GetLatestPlanVersion(ctx, current.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest version of meal plan %d: %w", current.ID, err)
+	}
+	if parent == 0 {
+		parent = int(current.CurrentVersion)
+	}
+
+	if plan.Status == "" {
+		plan.Status = StatusDraft
+	}
+	plan.ID = current.ID
+	plan.WeekStart = current.WeekStartDate
+	plan.Version = int(latest) + 1
+
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal meal plan to JSON for saving: %w", err)
+	}
+
+	if err := queries.InsertPlanVersion(ctx, db.InsertPlanVersionParams{
+		MealPlanID:    current.ID,
+		Version:       int64(plan.Version),
+		ParentVersion: sql.NullInt64{Int64: int64(parent), Valid: true},
+		Author:        author,
+		Feedback:      feedback,
+		PlanData:      string(planJSON),
+		CreatedAt:     time.Now().UTC(),
+	}); err != nil {
+		return 0, fmt.Errorf("failed to insert version %d of meal plan %d: %w", plan.Version, current.ID, err)
+	}
+	if err := queries.SetCurrentPlanVersion(ctx, db.SetCurrentPlanVersionParams{
+		PlanData:       string(planJSON),
+		Status:         string(plan.Status),
+		CurrentVersion: int64(plan.Version),
+		ID:             current.ID,
+	}); err != nil {
+		return 0, fmt.Errorf("failed to set current version of meal plan %d: %w", current.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit meal plan version: %w", err)
+	}
+	return current.ID, nil
+}
+
+// ListVersions returns every version of a plan, oldest first.
+func (r *PlanRepository) ListVersions(ctx context.Context, planID int64) ([]PlanVersion, error) {
+	rows, err := r.queries.ListPlanVersions(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of meal plan %d: %w", planID, err)
+	}
+
+	versions := make([]PlanVersion, 0, len(rows))
+	for _, row := range rows {
+		v, err := planVersionFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// GetVersion retrieves one version of a plan, or nil if it does not exist.
+func (r *PlanRepository) GetVersion(ctx context.Context, planID int64, version int) (*PlanVersion, error) {
+	row, err := r.queries.GetPlanVersion(ctx, db.GetPlanVersionParams{
+		MealPlanID: planID,
+		Version:    int64(version),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get version %d of meal plan %d: %w", version, planID, err)
+	}
+
+	v, err := planVersionFromRow(row)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// DiffVersions lists the days that changed from one version of a plan to another.
+func (r *PlanRepository) DiffVersions(ctx context.Context, planID int64, from, to int) ([]DayChange, error) {
+	before, err := r.GetVersion(ctx, planID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := r.GetVersion(ctx, planID, to)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("meal plan %d has no version %d or %d", planID, from, to)
+	}
+	return DiffPlans(before.Plan, after.Plan), nil
+}
+
+// RestoreVersion makes a copy of an earlier version the current draft of the plan.
+// The copy is a new version whose parent is the restored one, so history is never lost.
+func (r *PlanRepository) RestoreVersion(ctx context.Context, userID string, planID int64, version int) (*MealPlan, error) {
+	v, err := r.GetVersion(ctx, planID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("meal plan %d has no version %d", planID, version)
+	}
+
+	plan := v.Plan
+	plan.Status = StatusDraft
+	plan.ShoppingList = nil
+	feedback := fmt.Sprintf("Restored version %d", version)
+	if _, err := r.saveVersion(ctx, userID, plan, VersionAuthorRestore, feedback, version); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func planVersionFromRow(row db.MealPlanVersion) (PlanVersion, error) {
+	plan := &MealPlan{}
+	if err := json.Unmarshal([]byte(row.PlanData), plan); err != nil {
+		return PlanVersion{}, fmt.Errorf("failed to unmarshal version %d of meal plan %d: %w", row.Version, row.MealPlanID, err)
+	}
+	plan.ID = row.MealPlanID
+	plan.Version = int(row.Version)
+
+	return PlanVersion{
+		PlanID:        row.MealPlanID,
+		Version:       int(row.Version),
+		ParentVersion: int(row.ParentVersion.Int64),
+		Author:        row.Author,
+		Feedback:      row.Feedback,
+		Plan:          plan,
+		CreatedAt:     row.CreatedAt,
+	}, nil
+}
+
+// DiffPlans compares two plans day by day, matching days by name, and returns the
+// days whose recipe, title or side dishes differ, in the order they appear.
+func DiffPlans(before, after *MealPlan) []DayChange {
+	beforeByDay := make(map[string]DayPlan, len(before.Plan))
+	for _, day := range before.Plan {
+		beforeByDay[strings.ToLower(day.Day)] = day
+	}
+
+	var changes []DayChange
+	seen := make(map[string]bool, len(after.Plan))
+	for _, day := range after.Plan {
+		key := strings.ToLower(day.Day)
+		seen[key] = true
+		old, ok := beforeByDay[key]
+		if !ok || !sameMeal(old, day) {
+			changes = append(changes, DayChange{Day: day.Day, Before: old, After: day})
+		}
+	}
+	for _, day := range before.Plan {
+		if !seen[strings.ToLower(day.Day)] {
+			changes = append(changes, DayChange{Day: day.Day, Before: day})
+		}
+	}
+	return changes
+}
+
+func sameMeal(a, b DayPlan) bool {
+	return a.RecipeID == b.RecipeID && a.RecipeTitle == b.RecipeTitle && slices.Equal(a.SideDishes, b.SideDishes)
+}
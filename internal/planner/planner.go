@@ -3,7 +3,8 @@ package planner
 import (
 	"context"
 	"fmt"
-	"strings"
+	"log"
+	"slices"
 	"time"
 
 	"ai-meal-planner/internal/llm"
@@ -13,6 +14,7 @@ import (
 // Planner handles the orchestration of meal plan generation.
 type Planner struct {
 	RecipeSearcher    shared.RecipeSearcher
+	library           RecipeLibrary
 	planRepo          *PlanRepository
 	analystGenerator  llm.TextGenerator // High-reasoning model (e.g., 70B)
 	chefGenerator     llm.TextGenerator // High-throughput model (e.g., 8B)
@@ -22,6 +24,7 @@ type Planner struct {
 // NewPlanner creates a new Planner instance.
 func NewPlanner(
 	RecipeSearcher shared.RecipeSearcher,
+	library RecipeLibrary,
 	planRepo *PlanRepository,
 	analystGen llm.TextGenerator,
 	chefGen llm.TextGenerator,
@@ -29,6 +32,7 @@ func NewPlanner(
 ) *Planner {
 	return &Planner{
 		RecipeSearcher:    RecipeSearcher,
+		library:           library,
 		planRepo:          planRepo,
 		analystGenerator:  analystGen,
 		chefGenerator:     chefGen,
@@ -52,32 +56,59 @@ type PlanningContext struct {
 	Adults           int
 	Children         int
 	ChildrenAges     []int
-	CookingFrequency int // Times per week they want to cook
+	CookingFrequency int    // Times per week they want to cook
+	UserID           string // Scopes cooking-history filters in recipe searches
 }
 
+// receiptIDsRecentlyUsed returns the recipes the user's repetition settings keep out
+// of the plan for targetWeek. Only confirmed plans count. When the exclusions would
+// leave too few recipes for the week, the ones used longest ago are allowed back.
+// Failures are logged and cost only the exclusions that depend on them.
 func (p *Planner) receiptIDsRecentlyUsed(
 	ctx context.Context,
 	userID string,
 	targetWeek time.Time,
+	cookingFrequency int,
 ) []string {
-	result := []string{}
-	// Check the last 3 plans
-	recentPlans, err := p.planRepo.ListRecentByUserID(ctx, userID, 3)
-	if err == nil {
-		for _, plan := range recentPlans {
-			// Skip the plan we are currently redoing/replacing
-			if plan.WeekStart.Equal(targetWeek) {
-				continue
-			}
-			for _, day := range plan.Plan {
-				if day.RecipeID != "" {
-					result = append(result, day.RecipeID)
-				}
-			}
+	settings, err := p.planRepo.GetRepetitionSettings(ctx, userID)
+	if err != nil {
+		log.Printf("Warning: using default repetition settings for user %s: %v", userID, err)
+		settings = DefaultRepetitionSettings()
+	}
+	if settings.RepeatWindowWeeks == 0 {
+		return nil
+	}
+
+	span := 7 * settings.RepeatWindowWeeks
+	usage, err := p.planRepo.ListRecipeUsage(ctx, userID, targetWeek.AddDate(0, 0, -span), targetWeek.AddDate(0, 0, span))
+	if err != nil {
+		log.Printf("Warning: recently used recipes are not excluded for user %s: %v", userID, err)
+		return nil
+	}
+	if p.library != nil {
+		favorites, err := p.library.FavoriteRecipeIDs(ctx, userID)
+		if err != nil {
+			log.Printf("Warning: favorites get the regular repeat window for user %s: %v", userID, err)
+		}
+		for i := range usage {
+			usage[i].Favorite = slices.Contains(favorites, usage[i].RecipeID)
+		}
+	}
+	excluded := repeatExclusions(usage, settings, targetWeek)
+
+	if cookingFrequency <= 0 {
+		cookingFrequency = defaultMealsPerWeek
+	}
+	if p.library != nil {
+		total, err := p.library.CountRecipes(ctx)
+		if err != nil {
+			log.Printf("Warning: recently used recipes are excluded without checking the library size: %v", err)
+		} else {
+			excluded = relaxExclusions(excluded, total, cookingFrequency*freshCandidatesPerMeal)
 		}
 	}
 
-	return result
+	return excluded
 }
 
 // GeneratePlan creates a meal plan based on a user request.
@@ -85,7 +116,8 @@ func (p *Planner) GeneratePlan(ctx context.Context, userID string, userRequest s
 	var metas []shared.AgentMeta
 
 	// 0. Fetch recent history to avoid repetition
-	excludeIDs := p.receiptIDsRecentlyUsed(ctx, userID, targetWeek)
+	excludeIDs := p.receiptIDsRecentlyUsed(ctx, userID, targetWeek, pCtx.CookingFrequency)
+	pCtx.UserID = userID
 
 	// 1. Call Analyst agent to create a meal schedule
 	analyst := NewAnalyst(p.analystGenerator, p.RecipeSearcher)
@@ -108,6 +140,7 @@ func (p *Planner) GeneratePlan(ctx context.Context, userID string, userRequest s
 		return nil, metas, fmt.Errorf("failed to generate meal plan: %w", err)
 	}
 	chefResult.Plan.OriginalRequest = userRequest
+	chefResult.Plan.Exclusions = analystResult.Exclusions
 	metas = append(metas, chefResult.Meta)
 
 	return chefResult.Plan, metas, nil
@@ -126,8 +159,7 @@ func (p *Planner) GenerateShoppingList(ctx context.Context, plan *MealPlan, pCtx
 
 			// Determine action based on day title
 			action := MealActionCook
-			if strings.Contains(strings.ToLower(day.RecipeTitle), "leftover") ||
-				strings.Contains(strings.ToLower(day.RecipeTitle), "reuse") {
+			if day.IsReuse() {
 				action = MealActionLeftOvers
 			}
 
@@ -171,17 +203,19 @@ func (p *Planner) GenerateShoppingList(ctx context.Context, plan *MealPlan, pCtx
 	return chefResult.Plan.ShoppingList, nil
 }
 
-// RevisePlan revises an existing meal plan based on user feedback.
+// RevisePlan revises an existing meal plan based on user feedback. earlierFeedback is the
+// feedback already applied in the same adjustment conversation, oldest first.
 func (p *Planner) RevisePlan(
 	ctx context.Context,
 	userID string,
 	currentPlan *MealPlan,
 	originalRequest string,
 	feedback string,
+	earlierFeedback []string,
 	pCtx PlanningContext,
 ) (PlanReviewerResult, error) {
 	// Fetch recent history to avoid repetition from previous weeks
-	recentlyUsed := p.receiptIDsRecentlyUsed(ctx, userID, currentPlan.WeekStart)
+	recentlyUsed := p.receiptIDsRecentlyUsed(ctx, userID, currentPlan.WeekStart, pCtx.CookingFrequency)
 
 	// Extract recently used IDs from the current plan to inform the search
 	for _, meal := range currentPlan.Plan {
@@ -191,6 +225,7 @@ func (p *Planner) RevisePlan(
 	}
 
 	// Run the reviewer agent
+	pCtx.UserID = userID
 	reviewer := NewPlanReviewer(p.reviewerGenerator, p.RecipeSearcher)
-	return reviewer.Run(ctx, currentPlan, originalRequest, feedback, pCtx, recentlyUsed)
+	return reviewer.Run(ctx, currentPlan, originalRequest, feedback, earlierFeedback, pCtx, recentlyUsed)
 }
@@ -2,14 +2,20 @@ package planner
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"ai-meal-planner/internal/database"
 	"ai-meal-planner/internal/llm"
 	"ai-meal-planner/internal/llm/llmtest"
+	"ai-meal-planner/internal/rating"
 	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/shared"
 	"ai-meal-planner/internal/value"
 
 	_ "modernc.org/sqlite"
@@ -74,8 +80,8 @@ func TestGeneratePlan(t *testing.T) {
 		},
 	}
 	mockEmbedGen := &llmtest.MockEmbeddingGenerator{Values: []float32{1.0, 0.0}}
-	recipeService := recipe.NewSearchService(recipeRepo, vectorRepo, mockEmbedGen)
-	p := NewPlanner(recipeService, planRepo, mockGen, mockGen, mockGen)
+	recipeService := recipe.NewSearchService(recipeRepo, vectorRepo, mockEmbedGen, rating.NewRepository(db.SQL), planRepo)
+	p := NewPlanner(recipeService, recipeService, planRepo, mockGen, mockGen, mockGen)
 
 	// 4. Run GeneratePlan
 	plan, metas, err := p.GeneratePlan(ctx, "test_user", "I want pasta", PlanningContext{}, time.Now())
@@ -196,7 +202,7 @@ func TestPlanReviewer_TerminalTool(t *testing.T) {
 	}
 
 	reviewer := NewPlanReviewer(mockGen, mockSearcher)
-	result, err := reviewer.Run(ctx, currentPlan, "I want pasta", "Please update", PlanningContext{}, nil)
+	result, err := reviewer.Run(ctx, currentPlan, "I want pasta", "Please update", nil, PlanningContext{}, nil)
 	if err != nil {
 		t.Fatalf("PlanReviewer.Run failed: %v", err)
 	}
@@ -232,3 +238,497 @@ func TestChefRepairsInvalidJSON(t *testing.T) {
 		t.Fatalf("repaired plan = %#v", result.Plan.Plan)
 	}
 }
+
+func TestRecipeFilterFromArgs(t *testing.T) {
+	base := shared.RecipeFilter{ExcludeIDs: []string{"recent"}, UserID: "user1"}
+	args := map[string]any{
+		"exclude_tags":        []interface{}{"beef", 3},
+		"include_tags":        []interface{}{"vegetarian"},
+		"exclude_ingredients": []interface{}{"shrimp"},
+		"max_prep_minutes":    float64(30),
+		"min_servings":        float64(4),
+		"meal_type":           "main",
+		"cuisine":             "italian",
+		"main_protein":        "legumes",
+		"not_cooked_weeks":    float64(6),
+	}
+
+	got := recipeFilterFromArgs(args, base)
+	want := shared.RecipeFilter{
+		ExcludeIDs:         []string{"recent"},
+		ExcludeTags:        []string{"beef"},
+		IncludeTags:        []string{"vegetarian"},
+		ExcludeIngredients: []string{"shrimp"},
+		MaxPrepMinutes:     30,
+		MinServings:        4,
+		MealType:           "main",
+		Cuisine:            "italian",
+		MainProtein:        "legumes",
+		NotCookedWeeks:     6,
+		UserID:             "user1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recipeFilterFromArgs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRecipesRecentlyUsedFollowsRepetitionSettings(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	recipeRepo := recipe.NewRepository(db.SQL)
+	for _, id := range []string{"r1", "r2", "r3", "r4", "r5", "r6", "r7", "r8", "r9", "r10"} {
+		if err := recipeRepo.Save(ctx, value.Recipe{ID: id, Title: id, UpdatedAt: "2023-01-01T00:00:00Z"}); err != nil {
+			t.Fatalf("Save(%s) error = %v", id, err)
+		}
+	}
+
+	planRepo := NewPlanRepository(db.SQL)
+	target := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	savePlan := func(weeksAgo int, status PlanStatus, ids ...string) int64 {
+		plan := &MealPlan{WeekStart: target.AddDate(0, 0, -7*weeksAgo), Status: status}
+		for _, id := range ids {
+			plan.Plan = append(plan.Plan, DayPlan{Day: "Monday", RecipeID: id, RecipeTitle: "Cook: " + id})
+		}
+		id, err := planRepo.Save(ctx, "user1", plan)
+		if err != nil {
+			t.Fatalf("Save plan error = %v", err)
+		}
+		return id
+	}
+	lastWeek := savePlan(1, StatusFinal, "r1", "r2")
+	savePlan(1, StatusDraft, "r3")
+	savePlan(3, StatusFinal, "r4")
+	savePlan(5, StatusFinal, "r5")
+	savePlan(0, StatusFinal, "r6")
+	if err := rating.NewRepository(db.SQL).Rate(ctx, "user1", "r1", lastWeek, 5); err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+
+	p := NewPlanner(nil, recipe.NewSearchService(recipeRepo, nil, nil, rating.NewRepository(db.SQL), planRepo), planRepo, nil, nil, nil)
+	tests := []struct {
+		name      string
+		settings  *RepetitionSettings
+		frequency int
+		want      []string
+	}{
+		{name: "defaults skip drafts and the redone week", frequency: 1, want: []string{"r1", "r2", "r4"}},
+		{name: "favorites allowed right away", settings: &RepetitionSettings{RepeatWindowWeeks: 3, FavoriteRepeatWeeks: 0}, frequency: 1, want: []string{"r2", "r4"}},
+		{name: "longer window", settings: &RepetitionSettings{RepeatWindowWeeks: 6, FavoriteRepeatWeeks: 0}, frequency: 1, want: []string{"r2", "r4", "r5"}},
+		{name: "too few candidates keeps the closest weeks", settings: &RepetitionSettings{RepeatWindowWeeks: 6, FavoriteRepeatWeeks: 0}, frequency: 3, want: []string{"r2"}},
+		{name: "window disabled", settings: &RepetitionSettings{}, frequency: 1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.settings != nil {
+				if err := planRepo.SaveRepetitionSettings(ctx, "user1", *tt.settings); err != nil {
+					t.Fatalf("SaveRepetitionSettings() error = %v", err)
+				}
+			}
+			got := p.receiptIDsRecentlyUsed(ctx, "user1", target, tt.frequency)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("receiptIDsRecentlyUsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := planRepo.SaveRepetitionSettings(ctx, "user1", RepetitionSettings{RepeatWindowWeeks: 2, FavoriteRepeatWeeks: 4}); err == nil {
+		t.Error("SaveRepetitionSettings() accepted a favorite window longer than the repeat window")
+	}
+}
+
+func TestCookedSinceCountsCookDaysOfConfirmedPlans(t *testing.T) {
+	ctx := context.Background()
+	planRepo := NewPlanRepository(openTestDB(t).SQL)
+	monday := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	week := &MealPlan{WeekStart: monday, Status: StatusFinal, Plan: []DayPlan{
+		{Day: "Monday", RecipeID: "feijoada", RecipeTitle: "Cook: Feijoada"},
+		{Day: "Tuesday", RecipeID: "feijoada", RecipeTitle: "Leftovers: Feijoada"},
+		{Day: "Wednesday", RecipeID: "curry", RecipeTitle: "Cook: Curry"},
+		{Day: "Thursday", RecipeID: "curry", RecipeTitle: "Leftovers: Curry"},
+		{Day: "Friday", RecipeID: "lasagna", RecipeTitle: "Cook: Lasagna"},
+		{Day: "Saturday (Lunch)", RecipeID: "lasagna", RecipeTitle: "Leftovers: Lasagna"},
+		{Day: "Saturday (Dinner)", RecipeID: "moqueca", RecipeTitle: "Cook: Moqueca"},
+		{Day: "Sunday (Lunch)", RecipeID: "moqueca", RecipeTitle: "Leftovers: Moqueca"},
+		{Day: "Sunday (Dinner)", RecipeID: "salad", RecipeTitle: "Cook: Salad"},
+	}}
+	for _, plan := range []*MealPlan{
+		week,
+		{WeekStart: monday.AddDate(0, 0, 7), Status: StatusDraft, Plan: []DayPlan{{Day: "Monday", RecipeID: "draft", RecipeTitle: "Cook: Draft"}}},
+		{WeekStart: monday.AddDate(0, 0, -7), Status: StatusFinal, Plan: []DayPlan{{Day: "Monday", RecipeID: "old", RecipeTitle: "Cook: Old"}}},
+	} {
+		if _, err := planRepo.Save(ctx, "user1", plan); err != nil {
+			t.Fatalf("Save plan error = %v", err)
+		}
+	}
+
+	meals, err := planRepo.CookedSince(ctx, "user1", monday.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("CookedSince() error = %v", err)
+	}
+	var got []string
+	for _, meal := range meals {
+		got = append(got, meal.RecipeID+" "+meal.CookedAt.Format("Mon"))
+	}
+	want := []string{"salad Sun", "moqueca Sat", "lasagna Fri", "curry Wed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CookedSince() = %v, want %v", got, want)
+	}
+}
+
+func TestPlanVersions(t *testing.T) {
+	ctx := context.Background()
+	planRepo := NewPlanRepository(openTestDB(t).SQL)
+	week := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	first := &MealPlan{WeekStart: week, Plan: []DayPlan{
+		{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada"},
+		{Day: "Tuesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"},
+	}}
+	planID, err := planRepo.SaveVersion(ctx, "user1", first, VersionAuthorAnalyst, "something Brazilian")
+	if err != nil {
+		t.Fatalf("SaveVersion() error = %v", err)
+	}
+
+	revised := &MealPlan{WeekStart: week, Plan: []DayPlan{
+		{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada"},
+		{Day: "Tuesday", RecipeID: "r3", RecipeTitle: "Cook: Strogonoff"},
+	}}
+	revisedID, err := planRepo.SaveVersion(ctx, "user1", revised, VersionAuthorPlanReviewer, "no fish")
+	if err != nil {
+		t.Fatalf("SaveVersion() error = %v", err)
+	}
+	if revisedID != planID || revised.Version != 2 {
+		t.Fatalf("revision saved as plan %d v%d, want plan %d v2", revisedID, revised.Version, planID)
+	}
+	if _, err := planRepo.SaveVersion(ctx, "user2", &MealPlan{ID: planID, WeekStart: week}, VersionAuthorAnalyst, ""); err == nil {
+		t.Error("SaveVersion() let another user version the plan")
+	}
+	if plan, err := planRepo.GetByIDForUser(ctx, planID, "user2"); err != nil || plan != nil {
+		t.Errorf("GetByIDForUser() returned another user's plan: %+v, %v", plan, err)
+	}
+	if plan, err := planRepo.GetByIDForUser(ctx, planID, "user1"); err != nil || plan == nil || plan.Version != 2 {
+		t.Errorf("GetByIDForUser() = %+v, %v; want the owner's plan at v2", plan, err)
+	}
+
+	changes, err := planRepo.DiffVersions(ctx, planID, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffVersions() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Day != "Tuesday" || changes[0].Before.RecipeID != "r2" || changes[0].After.RecipeID != "r3" {
+		t.Errorf("DiffVersions() = %+v, want Tuesday r2 -> r3", changes)
+	}
+
+	if _, err := planRepo.RestoreVersion(ctx, "user1", planID, 1); err != nil {
+		t.Fatalf("RestoreVersion() error = %v", err)
+	}
+	current, err := planRepo.GetCurrentByUserAndWeek(ctx, "user1", week)
+	if err != nil || current == nil {
+		t.Fatalf("GetCurrentByUserAndWeek() = %v, %v", current, err)
+	}
+	if current.ID != planID || current.Version != 3 || current.Plan[1].RecipeID != "r2" || current.Status != StatusDraft {
+		t.Errorf("current plan after restore = %+v", current)
+	}
+
+	versions, err := planRepo.ListVersions(ctx, planID)
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	type summary struct {
+		Version, Parent  int
+		Author, Feedback string
+	}
+	var got []summary
+	for _, v := range versions {
+		got = append(got, summary{v.Version, v.ParentVersion, v.Author, v.Feedback})
+	}
+	want := []summary{
+		{1, 0, VersionAuthorAnalyst, "something Brazilian"},
+		{2, 1, VersionAuthorPlanReviewer, "no fish"},
+		{3, 1, VersionAuthorRestore, "Restored version 1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListVersions() = %+v, want %+v", got, want)
+	}
+
+	exists, err := planRepo.ExistsForWeek(ctx, "user1", week)
+	if err != nil || !exists {
+		t.Errorf("ExistsForWeek() = %v, %v; want true", exists, err)
+	}
+}
+
+func TestSaveVersionKeepsConfirmedPlans(t *testing.T) {
+	ctx := context.Background()
+	planRepo := NewPlanRepository(openTestDB(t).SQL)
+	week := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	confirmed := &MealPlan{WeekStart: week, Plan: []DayPlan{{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada"}}}
+	planID, err := planRepo.SaveVersion(ctx, "user1", confirmed, VersionAuthorAnalyst, "")
+	if err != nil {
+		t.Fatalf("SaveVersion() error = %v", err)
+	}
+	if err := planRepo.Confirm(ctx, "user1", planID); err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+	if err := planRepo.Confirm(ctx, "user1", planID); !errors.Is(err, ErrPlanNotEditable) {
+		t.Errorf("Confirm(confirmed plan) error = %v, want ErrPlanNotEditable", err)
+	}
+
+	draft := &MealPlan{ID: planID, Plan: []DayPlan{{Day: "Monday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"}}}
+	if _, err := planRepo.SaveVersion(ctx, "user1", draft, VersionAuthorAnalyst, ""); !errors.Is(err, ErrPlanNotEditable) {
+		t.Errorf("SaveVersion(draft of a confirmed plan) error = %v, want ErrPlanNotEditable", err)
+	}
+	if _, err := planRepo.RestoreVersion(ctx, "user1", planID, 1); !errors.Is(err, ErrPlanNotEditable) {
+		t.Errorf("RestoreVersion(confirmed plan) error = %v, want ErrPlanNotEditable", err)
+	}
+	kept := &MealPlan{ID: planID, Status: StatusFinal, Plan: []DayPlan{{Day: "Monday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"}}}
+	if _, err := planRepo.SaveVersion(ctx, "user1", kept, VersionAuthorAnalyst, ""); err != nil {
+		t.Errorf("SaveVersion(confirmed plan kept FINAL) error = %v", err)
+	}
+
+	// Confirming a newer plan for the week retires this one for good
+	newer := &MealPlan{WeekStart: week, Plan: []DayPlan{{Day: "Monday", RecipeID: "r3", RecipeTitle: "Cook: Strogonoff"}}}
+	newerID, err := planRepo.SaveVersion(ctx, "user1", newer, VersionAuthorAnalyst, "")
+	if err != nil || newerID == planID {
+		t.Fatalf("SaveVersion(new draft) = %d, %v; want a plan of its own", newerID, err)
+	}
+	if err := planRepo.Confirm(ctx, "user1", newerID); err != nil {
+		t.Fatalf("Confirm(newer) error = %v", err)
+	}
+	kept.Status = StatusFinal
+	if _, err := planRepo.SaveVersion(ctx, "user1", kept, VersionAuthorAnalyst, ""); !errors.Is(err, ErrPlanNotEditable) {
+		t.Errorf("SaveVersion(replaced plan) error = %v, want ErrPlanNotEditable", err)
+	}
+	if err := planRepo.Confirm(ctx, "user1", planID); !errors.Is(err, ErrPlanNotEditable) {
+		t.Errorf("Confirm(replaced plan) error = %v, want ErrPlanNotEditable", err)
+	}
+
+	stored, err := planRepo.GetByIDForUser(ctx, planID, "user1")
+	if err != nil || stored.Status != StatusReplaced || stored.Plan[0].RecipeID != "r2" {
+		t.Errorf("replaced plan = %+v, %v; want REPLACED with r2", stored, err)
+	}
+}
+
+// openTestDB returns a migrated database in a temporary file that is removed with the test.
+func openTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "planner_test.db")
+
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("Failed to migrate test DB: %v", err)
+	}
+	return db
+}
+
+func TestMealPlanDayDate(t *testing.T) {
+	monday := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	plan := &MealPlan{WeekStart: monday}
+	for _, day := range []string{
+		"Monday", "Tuesday", "Wednesday", "Thursday", "Friday",
+		"Saturday (Lunch)", "Saturday (Dinner)", "Sunday (Lunch)", "Sunday (Dinner)",
+	} {
+		plan.Plan = append(plan.Plan, DayPlan{Day: day})
+	}
+
+	wantOffsets := []int{0, 1, 2, 3, 4, 5, 5, 6, 6}
+	for i, offset := range wantOffsets {
+		if got, want := plan.DayDate(i), monday.AddDate(0, 0, offset); !got.Equal(want) {
+			t.Errorf("DayDate(%d) for %q = %s, want %s", i, plan.Plan[i].Day, got.Format("Mon 2006-01-02"), want.Format("Mon 2006-01-02"))
+		}
+	}
+
+	// Unknown names fall back to their position, never past Sunday
+	plan.Plan[8].Day = "Day 9"
+	if got, want := plan.DayDate(8), monday.AddDate(0, 0, 6); !got.Equal(want) {
+		t.Errorf("DayDate(8) for an unknown name = %s, want %s", got, want)
+	}
+}
+
+func TestSwapDayReplacesLeftovers(t *testing.T) {
+	plan := &MealPlan{
+		Plan: []DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada", PrepTime: "3 hours"},
+			{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Feijoada"},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"},
+		},
+		ShoppingList: []string{"black beans"},
+	}
+	rec := value.Recipe{ID: "r3", Title: "Strogonoff", PrepTime: "30 mins", SideDishes: []string{"Rice"}}
+
+	if err := SwapDay(plan, 1, rec); err == nil {
+		t.Error("SwapDay() accepted a leftovers day")
+	}
+	if err := SwapDay(plan, 0, rec); err != nil {
+		t.Fatalf("SwapDay() error = %v", err)
+	}
+
+	want := []DayPlan{
+		{Day: "Monday", RecipeID: "r3", RecipeTitle: "Cook: Strogonoff", PrepTime: "30 mins", SideDishes: []string{"Rice"}},
+		{Day: "Tuesday", RecipeID: "r3", RecipeTitle: "Leftovers: Strogonoff", SideDishes: []string{"Rice"}},
+		{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"},
+	}
+	if !reflect.DeepEqual(plan.Plan, want) {
+		t.Errorf("SwapDay() plan = %+v, want %+v", plan.Plan, want)
+	}
+	if plan.ShoppingList != nil {
+		t.Errorf("SwapDay() kept the shopping list %v", plan.ShoppingList)
+	}
+}
+
+func TestSwapAlternatives(t *testing.T) {
+	ctx := context.Background()
+	searcher := &mockSearcher{recipes: []value.Recipe{
+		{ID: "r1", Title: "Feijoada", Cuisine: "brazilian", MainProtein: "pork"},
+		{ID: "r2", Title: "Moqueca", Cuisine: "brazilian", MainProtein: "fish"},
+		{ID: "r3", Title: "Bobó de camarão", Cuisine: "brazilian", MainProtein: "shrimp"},
+		{ID: "r4", Title: "Carbonara", Cuisine: "italian", MainProtein: "pork"},
+		{ID: "r5", Title: "Pad thai", Cuisine: "thai", MainProtein: "chicken"},
+	}}
+	p := NewPlanner(searcher, nil, NewPlanRepository(openTestDB(t).SQL), nil, nil, nil)
+	plan := &MealPlan{
+		WeekStart: time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC),
+		Plan: []DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada"},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"},
+		},
+	}
+
+	ids := func(recipes []value.Recipe) []string {
+		var out []string
+		for _, r := range recipes {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	similar, err := p.SwapAlternatives(ctx, "user1", plan, 0, SwapSimilar, 3, PlanningContext{})
+	if err != nil {
+		t.Fatalf("SwapAlternatives(similar) error = %v", err)
+	}
+	if got := ids(similar); !reflect.DeepEqual(got, []string{"r3", "r4", "r5"}) {
+		t.Errorf("SwapAlternatives(similar) = %v, want every recipe outside the plan", got)
+	}
+
+	different, err := p.SwapAlternatives(ctx, "user1", plan, 0, SwapDifferent, 3, PlanningContext{})
+	if err != nil {
+		t.Fatalf("SwapAlternatives(different) error = %v", err)
+	}
+	if got := ids(different); !reflect.DeepEqual(got, []string{"r5"}) {
+		t.Errorf("SwapAlternatives(different) = %v, want only recipes with another protein and cuisine", got)
+	}
+
+	plan.Exclusions = PlanExclusions{Tags: []string{"spicy"}, Ingredients: []string{"peanut"}}
+	if _, err := p.SwapAlternatives(ctx, "user1", plan, 0, SwapSimilar, 3, PlanningContext{}); err != nil {
+		t.Fatalf("SwapAlternatives(exclusions) error = %v", err)
+	}
+	filter := searcher.filters[len(searcher.filters)-1]
+	if !reflect.DeepEqual(filter.ExcludeTags, []string{"spicy"}) || !reflect.DeepEqual(filter.ExcludeIngredients, []string{"peanut"}) {
+		t.Errorf("SwapAlternatives filter excludes tags %v and ingredients %v, want the plan's exclusions", filter.ExcludeTags, filter.ExcludeIngredients)
+	}
+}
+
+func TestPlanExclusionsAddSearch(t *testing.T) {
+	var exclusions PlanExclusions
+	exclusions.addSearch(map[string]any{"query": "dinner", "exclude_tags": []any{"spicy", "pork"}})
+	exclusions.addSearch(map[string]any{"exclude_tags": []any{"Spicy"}, "exclude_ingredients": []any{"peanut"}})
+
+	want := PlanExclusions{Tags: []string{"spicy", "pork"}, Ingredients: []string{"peanut"}}
+	if !reflect.DeepEqual(exclusions, want) {
+		t.Errorf("addSearch() = %+v, want %+v", exclusions, want)
+	}
+}
+
+func TestPlanReviewerKeepsLockedDays(t *testing.T) {
+	ctx := context.Background()
+
+	var prompt string
+	mockGen := &llmtest.MockTextGenerator{
+		GenerateFn: func(conversation llm.Conversation) llm.ContentResponse {
+			prompt = conversation[1].Content
+			return llm.ContentResponse{Message: llm.Message{
+				Role: "assistant",
+				ToolCalls: []llm.ToolCall{{
+					ID:   "call_1",
+					Name: "submit_revised_plan",
+					Args: map[string]any{"plan": []any{
+						map[string]any{"day": "Monday", "recipe_title": "Unknown recipe", "note": "Changed anyway"},
+						map[string]any{"day": "Wednesday", "recipe_title": "Cook: Moqueca", "note": "Revised"},
+					}},
+				}},
+			}}
+		},
+	}
+
+	currentPlan := &MealPlan{
+		WeekStart: time.Now(),
+		Plan: []DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada", Locked: true},
+			{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Feijoada", Locked: true},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca", PrepTime: "40 mins"},
+		},
+	}
+
+	reviewer := NewPlanReviewer(mockGen, &mockSearcher{})
+	result, err := reviewer.Run(ctx, currentPlan, "", "Less beans", nil, PlanningContext{}, nil)
+	if err != nil {
+		t.Fatalf("PlanReviewer.Run failed: %v", err)
+	}
+
+	want := []DayPlan{
+		{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada", Locked: true},
+		{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Feijoada", Locked: true},
+		{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca", PrepTime: "40 mins", Note: "Revised"},
+	}
+	if !reflect.DeepEqual(result.RevisedPlan.Plan, want) {
+		t.Errorf("revised plan = %+v, want %+v", result.RevisedPlan.Plan, want)
+	}
+	if !strings.Contains(prompt, "Return them exactly as they are: Monday, Tuesday") {
+		t.Errorf("reviewer prompt does not list the locked days:\n%s", prompt)
+	}
+}
+
+func TestToggleLockCoversLeftovers(t *testing.T) {
+	plan := &MealPlan{Plan: []DayPlan{
+		{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada"},
+		{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Feijoada"},
+		{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"},
+	}}
+
+	if _, err := ToggleLock(plan, 1); err == nil {
+		t.Error("ToggleLock() accepted a leftovers day")
+	}
+	if locked, err := ToggleLock(plan, 0); err != nil || !locked {
+		t.Fatalf("ToggleLock() = %v, %v, want locked", locked, err)
+	}
+	if got := plan.LockedDays(); !reflect.DeepEqual(got, []string{"Monday", "Tuesday"}) {
+		t.Errorf("LockedDays() = %v, want Monday and its leftovers", got)
+	}
+	if err := SwapDay(plan, 0, value.Recipe{ID: "r3", Title: "Lasagna"}); err == nil {
+		t.Error("SwapDay() changed a locked day")
+	}
+	if locked, _ := ToggleLock(plan, 0); locked || len(plan.LockedDays()) != 0 {
+		t.Errorf("second ToggleLock() left %v locked", plan.LockedDays())
+	}
+}
+
+func TestPlanReviewerPromptListsEarlierFeedback(t *testing.T) {
+	prompt, err := buildPlanReviewerUserContext(planReviewerUserContextData{
+		CurrentPlan:        []DayPlan{{Day: "Monday", RecipeTitle: "Cook: Feijoada"}},
+		EarlierFeedback:    []string{"No pork", "Faster on Wednesday"},
+		AdjustmentFeedback: "Something Italian on Friday",
+	})
+	if err != nil {
+		t.Fatalf("buildPlanReviewerUserContext() error = %v", err)
+	}
+	for _, want := range []string{"### Earlier Feedback", "- No pork\n- Faster on Wednesday\n", "Something Italian on Friday"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q:\n%s", want, prompt)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package planner
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// MaxRepeatWindowWeeks caps both repetition windows at roughly half a year.
+	MaxRepeatWindowWeeks = 26
+	// freshCandidatesPerMeal is how many searchable recipes the planner wants per
+	// meal it cooks before it starts dropping exclusions.
+	freshCandidatesPerMeal = 3
+	// defaultMealsPerWeek stands in for an unknown cooking frequency.
+	defaultMealsPerWeek = 7
+)
+
+// RepetitionSettings controls how soon a recipe may come back in a new plan.
+type RepetitionSettings struct {
+	RepeatWindowWeeks   int // No recipe repeats within this many weeks of a confirmed plan
+	FavoriteRepeatWeeks int // Favorites may come back after this many weeks instead
+}
+
+// DefaultRepetitionSettings applies to users who never changed their settings.
+func DefaultRepetitionSettings() RepetitionSettings {
+	return RepetitionSettings{RepeatWindowWeeks: 3, FavoriteRepeatWeeks: 2}
+}
+
+// Validate checks that both windows are in range and favorites are not held back
+// longer than other recipes.
+func (s RepetitionSettings) Validate() error {
+	if s.RepeatWindowWeeks < 0 || s.RepeatWindowWeeks > MaxRepeatWindowWeeks {
+		return fmt.Errorf("repeat window must be between 0 and %d weeks, got %d", MaxRepeatWindowWeeks, s.RepeatWindowWeeks)
+	}
+	if s.FavoriteRepeatWeeks < 0 || s.FavoriteRepeatWeeks > s.RepeatWindowWeeks {
+		return fmt.Errorf("favorite window must be between 0 and %d weeks, got %d", s.RepeatWindowWeeks, s.FavoriteRepeatWeeks)
+	}
+	return nil
+}
+
+// RecipeLibrary is what the repetition rules need to know about the recipes themselves:
+// how many there are and which ones the user rated as favorites. The planner does not read
+// recipes or ratings; recipe.SearchService implements it.
+type RecipeLibrary interface {
+	CountRecipes(ctx context.Context) (int, error)
+	FavoriteRecipeIDs(ctx context.Context, userID string) ([]string, error)
+}
+
+// RecipeUsage is one appearance of a recipe in a confirmed plan.
+type RecipeUsage struct {
+	RecipeID  string
+	WeekStart time.Time
+	Favorite  bool
+}
+
+// repeatExclusions returns the recipes that may not appear in the plan for targetWeek,
+// the ones used closest to it first. Usage from targetWeek itself is ignored because
+// that is the plan being redone.
+func repeatExclusions(usage []RecipeUsage, s RepetitionSettings, targetWeek time.Time) []string {
+	usage = slices.Clone(usage)
+	slices.SortStableFunc(usage, func(a, b RecipeUsage) int {
+		return cmp.Compare(weeksApart(a.WeekStart, targetWeek), weeksApart(b.WeekStart, targetWeek))
+	})
+
+	seen := make(map[string]bool)
+	var excluded []string
+	for _, u := range usage {
+		if seen[u.RecipeID] || u.WeekStart.Equal(targetWeek) {
+			continue
+		}
+		window := s.RepeatWindowWeeks
+		if u.Favorite {
+			window = s.FavoriteRepeatWeeks
+		}
+		if weeksApart(u.WeekStart, targetWeek) > window {
+			continue
+		}
+		seen[u.RecipeID] = true
+		excluded = append(excluded, u.RecipeID)
+	}
+	return excluded
+}
+
+// weeksApart rounds the distance between two week starts, so a DST change in
+// between does not shift the count.
+func weeksApart(a, b time.Time) int {
+	return int(math.Round(math.Abs(b.Sub(a).Hours()) / (7 * 24)))
+}
+
+// relaxExclusions drops the exclusions used furthest from the target week when they
+// would leave fewer than minCandidates of the total recipes to choose from.
+func relaxExclusions(excluded []string, totalRecipes, minCandidates int) []string {
+	keep := max(totalRecipes-minCandidates, 0)
+	if len(excluded) <= keep {
+		return excluded
+	}
+	return excluded[:keep]
+}
@@ -0,0 +1,133 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ai-meal-planner/internal/shared"
+	"ai-meal-planner/internal/value"
+)
+
+// SwapMode chooses whether swap alternatives resemble the current recipe or move away from it.
+type SwapMode string
+
+const (
+	SwapSimilar   SwapMode = "similar"
+	SwapDifferent SwapMode = "different"
+)
+
+// VersionAuthorSwap marks plan versions produced by swapping a single day.
+const VersionAuthorSwap = "Swap"
+
+// swapCandidatePool is how many random recipes are drawn before dropping the ones
+// that share the current recipe's protein or cuisine.
+const swapCandidatePool = 30
+
+// SwapAlternatives suggests up to limit recipes to replace the Cook day at dayIndex,
+// without running any agent. It honors the user's repetition settings and the tags and
+// ingredients the plan's request excluded, and never offers a recipe already in the plan.
+func (p *Planner) SwapAlternatives(
+	ctx context.Context,
+	userID string,
+	plan *MealPlan,
+	dayIndex int,
+	mode SwapMode,
+	limit int,
+	pCtx PlanningContext,
+) ([]value.Recipe, error) {
+	if dayIndex < 0 || dayIndex >= len(plan.Plan) || plan.Plan[dayIndex].RecipeID == "" {
+		return nil, fmt.Errorf("day %d of the plan has no recipe to swap", dayIndex)
+	}
+
+	currentRecipes, err := p.RecipeSearcher.GetByIds(ctx, []string{plan.Plan[dayIndex].RecipeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the recipe to swap: %w", err)
+	}
+	if len(currentRecipes) == 0 {
+		return nil, fmt.Errorf("recipe %s no longer exists", plan.Plan[dayIndex].RecipeID)
+	}
+	current := currentRecipes[0]
+
+	excludeIDs := p.receiptIDsRecentlyUsed(ctx, userID, plan.WeekStart, pCtx.CookingFrequency)
+	for _, day := range plan.Plan {
+		if day.RecipeID != "" {
+			excludeIDs = append(excludeIDs, day.RecipeID)
+		}
+	}
+	filter := plan.Exclusions.apply(shared.RecipeFilter{ExcludeIDs: excludeIDs, UserID: userID})
+
+	var candidates []value.Recipe
+	if mode == SwapDifferent {
+		pool, err := p.RecipeSearcher.RandomRecipes(ctx, swapCandidatePool, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for different recipes: %w", err)
+		}
+		for _, rec := range pool {
+			if !sharesProfile(rec, current) {
+				candidates = append(candidates, rec)
+			}
+		}
+	} else {
+		candidates, err = p.RecipeSearcher.RecipeSemanticSearch(ctx, swapQuery(current), filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for similar recipes: %w", err)
+		}
+	}
+
+	return candidates[:min(len(candidates), limit)], nil
+}
+
+// swapQuery describes a recipe for a semantic search of similar dishes.
+func swapQuery(rec value.Recipe) string {
+	parts := []string{rec.Title}
+	for _, facet := range []string{rec.Cuisine, rec.MainProtein, rec.MealType} {
+		if facet != "" {
+			parts = append(parts, facet)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sharesProfile reports whether two recipes have the same main protein or cuisine.
+func sharesProfile(a, b value.Recipe) bool {
+	return (a.MainProtein != "" && strings.EqualFold(a.MainProtein, b.MainProtein)) ||
+		(a.Cuisine != "" && strings.EqualFold(a.Cuisine, b.Cuisine))
+}
+
+// SwapDay replaces the recipe of the Cook day at dayIndex, and of the Reuse days that
+// eat its leftovers, with rec. The shopping list is dropped; the Chef rebuilds it on confirm.
+func SwapDay(plan *MealPlan, dayIndex int, rec value.Recipe) error {
+	if dayIndex < 0 || dayIndex >= len(plan.Plan) {
+		return fmt.Errorf("day %d is outside the plan", dayIndex)
+	}
+	cook := plan.Plan[dayIndex]
+	if cook.RecipeID == "" || cook.IsReuse() {
+		return fmt.Errorf("%s is not a Cook day", cook.Day)
+	}
+	if cook.Locked {
+		return fmt.Errorf("%s is locked", cook.Day)
+	}
+
+	for i, day := range plan.Plan {
+		switch {
+		case i == dayIndex:
+			plan.Plan[i] = DayPlan{
+				Day:         day.Day,
+				RecipeID:    rec.ID,
+				RecipeTitle: "Cook: " + rec.Title,
+				SideDishes:  rec.SideDishes,
+				PrepTime:    rec.PrepTime,
+			}
+		case day.RecipeID == cook.RecipeID && day.IsReuse():
+			plan.Plan[i] = DayPlan{
+				Day:         day.Day,
+				RecipeID:    rec.ID,
+				RecipeTitle: "Leftovers: " + rec.Title,
+				SideDishes:  rec.SideDishes,
+			}
+		}
+	}
+	plan.ShoppingList = nil
+	return nil
+}
@@ -0,0 +1,46 @@
+package rating
+
+import "time"
+
+const (
+	// FavoriteStars is the lowest rating that makes a recipe a favorite.
+	FavoriteStars = 4
+	// DislikedStars is the highest rating that makes a recipe disliked.
+	DislikedStars = 2
+	// DislikeCooldown is how long a disliked recipe stays out of searches.
+	// "Never suggest again" excludes a recipe permanently instead.
+	DislikeCooldown = 12 * 7 * 24 * time.Hour
+)
+
+// Rating is a user's verdict on a recipe they cooked. Stars is 0 until the user picks one.
+type Rating struct {
+	UserID       string    `json:"user_id"`
+	RecipeID     string    `json:"recipe_id"`
+	Stars        int       `json:"stars"`
+	Comment      string    `json:"comment,omitempty"`
+	NeverSuggest bool      `json:"never_suggest"`
+	MealPlanID   int64     `json:"meal_plan_id"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// IsFavorite reports whether the user rated the recipe highly.
+func (r Rating) IsFavorite() bool {
+	return !r.NeverSuggest && r.Stars >= FavoriteStars
+}
+
+// IsDisliked reports whether the user rated the recipe poorly.
+func (r Rating) IsDisliked() bool {
+	return r.Stars > 0 && r.Stars <= DislikedStars
+}
+
+// Preferences summarizes a user's ratings for recipe search.
+type Preferences struct {
+	Excluded  []string            // Never suggested, or disliked within the cooldown
+	Favorites map[string]struct{} // Ranked ahead of other results
+}
+
+// IsFavorite reports whether recipeID is one of the user's favorites.
+func (p Preferences) IsFavorite(recipeID string) bool {
+	_, ok := p.Favorites[recipeID]
+	return ok
+}
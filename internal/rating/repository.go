@@ -0,0 +1,138 @@
+package rating
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	ratingdb "ai-meal-planner/internal/rating/db"
+)
+
+// Repository handles persistence of recipe ratings and of the prompts asking for them.
+type Repository struct {
+	queries *ratingdb.Queries
+	db      *sql.DB
+}
+
+// NewRepository creates a new rating repository.
+func NewRepository(d *sql.DB) *Repository {
+	return &Repository{
+		queries: ratingdb.New(d),
+		db:      d,
+	}
+}
+
+// Rate records a 1-5 star rating, keeping any earlier comment.
+func (r *Repository) Rate(ctx context.Context, userID, recipeID string, mealPlanID int64, stars int) error {
+	if stars < 1 || stars > 5 {
+		return fmt.Errorf("invalid rating %d: must be between 1 and 5", stars)
+	}
+	if err := r.queries.UpsertRatingStars(ctx, ratingdb.UpsertRatingStarsParams{
+		UserID:     userID,
+		RecipeID:   recipeID,
+		Stars:      int64(stars),
+		MealPlanID: mealPlanID,
+		UpdatedAt:  time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed to save rating: %w", err)
+	}
+	return nil
+}
+
+// Comment records a free-text comment, keeping any earlier stars.
+func (r *Repository) Comment(ctx context.Context, userID, recipeID string, mealPlanID int64, comment string) error {
+	if err := r.queries.UpsertRatingComment(ctx, ratingdb.UpsertRatingCommentParams{
+		UserID:     userID,
+		RecipeID:   recipeID,
+		Comment:    comment,
+		MealPlanID: mealPlanID,
+		UpdatedAt:  time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed to save rating comment: %w", err)
+	}
+	return nil
+}
+
+// NeverSuggest permanently excludes a recipe from the user's searches.
+func (r *Repository) NeverSuggest(ctx context.Context, userID, recipeID string, mealPlanID int64) error {
+	if err := r.queries.UpsertRatingNeverSuggest(ctx, ratingdb.UpsertRatingNeverSuggestParams{
+		UserID:     userID,
+		RecipeID:   recipeID,
+		MealPlanID: mealPlanID,
+		UpdatedAt:  time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed to save never-suggest flag: %w", err)
+	}
+	return nil
+}
+
+// ListByUser returns every rating of a user, most recent first.
+func (r *Repository) ListByUser(ctx context.Context, userID string) ([]Rating, error) {
+	rows, err := r.queries.ListRatingsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ratings for user %s: %w", userID, err)
+	}
+
+	ratings := make([]Rating, 0, len(rows))
+	for _, row := range rows {
+		ratings = append(ratings, Rating{
+			UserID:       row.UserID,
+			RecipeID:     row.RecipeID,
+			Stars:        int(row.Stars),
+			Comment:      row.Comment,
+			NeverSuggest: row.NeverSuggest,
+			MealPlanID:   row.MealPlanID,
+			UpdatedAt:    row.UpdatedAt,
+		})
+	}
+	return ratings, nil
+}
+
+// Preferences turns a user's ratings into search preferences as of now.
+func (r *Repository) Preferences(ctx context.Context, userID string, now time.Time) (Preferences, error) {
+	ratings, err := r.ListByUser(ctx, userID)
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	prefs := Preferences{Favorites: make(map[string]struct{})}
+	for _, rt := range ratings {
+		switch {
+		case rt.NeverSuggest:
+			prefs.Excluded = append(prefs.Excluded, rt.RecipeID)
+		case rt.IsDisliked() && now.Sub(rt.UpdatedAt) < DislikeCooldown:
+			prefs.Excluded = append(prefs.Excluded, rt.RecipeID)
+		case rt.IsFavorite():
+			prefs.Favorites[rt.RecipeID] = struct{}{}
+		}
+	}
+	return prefs, nil
+}
+
+// ClaimPrompt records that the user is being asked to rate a plan day. It returns false
+// when the day was already claimed, so each day is only asked about once.
+func (r *Repository) ClaimPrompt(ctx context.Context, userID string, mealPlanID int64, dayIndex int) (bool, error) {
+	claimed, err := r.queries.InsertRatingPrompt(ctx, ratingdb.InsertRatingPromptParams{
+		MealPlanID: mealPlanID,
+		DayIndex:   int64(dayIndex),
+		UserID:     userID,
+		SentAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record rating prompt: %w", err)
+	}
+	return claimed > 0, nil
+}
+
+// ReleasePrompt forgets a claimed rating prompt that could not be delivered, so the next
+// run asks about the day again.
+func (r *Repository) ReleasePrompt(ctx context.Context, mealPlanID int64, dayIndex int) error {
+	if err := r.queries.DeleteRatingPrompt(ctx, ratingdb.DeleteRatingPromptParams{
+		MealPlanID: mealPlanID,
+		DayIndex:   int64(dayIndex),
+	}); err != nil {
+		return fmt.Errorf("failed to release rating prompt: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package rating
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"ai-meal-planner/internal/database"
+)
+
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "ratings.db")
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	return NewRepository(db.SQL)
+}
+
+func TestRepositoryPreferences(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	if err := repo.Rate(ctx, "u1", "loved", 1, 5); err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+	if err := repo.Rate(ctx, "u1", "meh", 1, 3); err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+	if err := repo.Rate(ctx, "u1", "hated", 1, 1); err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+	if err := repo.Comment(ctx, "u1", "hated", 1, "too salty"); err != nil {
+		t.Fatalf("Comment() error = %v", err)
+	}
+	// Never suggesting a favorite wins over its stars
+	if err := repo.Rate(ctx, "u1", "banned", 1, 5); err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+	if err := repo.NeverSuggest(ctx, "u1", "banned", 1); err != nil {
+		t.Fatalf("NeverSuggest() error = %v", err)
+	}
+	if err := repo.Rate(ctx, "u2", "meh", 2, 5); err != nil {
+		t.Fatalf("Rate() error = %v", err)
+	}
+	if err := repo.Rate(ctx, "u1", "loved", 1, 6); err == nil {
+		t.Error("Rate() with 6 stars should fail")
+	}
+
+	ratings, err := repo.ListByUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListByUser() error = %v", err)
+	}
+	if len(ratings) != 4 {
+		t.Fatalf("ListByUser() returned %d ratings, want 4", len(ratings))
+	}
+	for _, r := range ratings {
+		if r.RecipeID == "hated" && (r.Stars != 1 || r.Comment != "too salty") {
+			t.Errorf("comment should keep the stars, got %+v", r)
+		}
+	}
+
+	prefs, err := repo.Preferences(ctx, "u1", time.Now())
+	if err != nil {
+		t.Fatalf("Preferences() error = %v", err)
+	}
+	slices.Sort(prefs.Excluded)
+	if !slices.Equal(prefs.Excluded, []string{"banned", "hated"}) {
+		t.Errorf("Excluded = %v, want [banned hated]", prefs.Excluded)
+	}
+	if !prefs.IsFavorite("loved") || prefs.IsFavorite("meh") || prefs.IsFavorite("banned") || len(prefs.Favorites) != 1 {
+		t.Errorf("Favorites = %v, want only loved", prefs.Favorites)
+	}
+
+	// Dislikes wear off after the cooldown; never-suggest does not
+	later, err := repo.Preferences(ctx, "u1", time.Now().Add(DislikeCooldown+time.Hour))
+	if err != nil {
+		t.Fatalf("Preferences() error = %v", err)
+	}
+	if !slices.Equal(later.Excluded, []string{"banned"}) {
+		t.Errorf("Excluded after cooldown = %v, want [banned]", later.Excluded)
+	}
+}
+
+func TestRepositoryClaimPrompt(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	for i, want := range []bool{true, false} {
+		claimed, err := repo.ClaimPrompt(ctx, "u1", 7, 2)
+		if err != nil {
+			t.Fatalf("ClaimPrompt() error = %v", err)
+		}
+		if claimed != want {
+			t.Errorf("ClaimPrompt() call %d = %v, want %v", i+1, claimed, want)
+		}
+	}
+	if claimed, _ := repo.ClaimPrompt(ctx, "u1", 7, 3); !claimed {
+		t.Error("ClaimPrompt() for another day should succeed")
+	}
+
+	if err := repo.ReleasePrompt(ctx, 7, 2); err != nil {
+		t.Fatalf("ReleasePrompt() error = %v", err)
+	}
+	if claimed, _ := repo.ClaimPrompt(ctx, "u1", 7, 2); !claimed {
+		t.Error("ClaimPrompt() after ReleasePrompt() should succeed")
+	}
+}
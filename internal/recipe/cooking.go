@@ -0,0 +1,122 @@
+package recipe
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	rangeQtyRe   = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)(\s*(?:-|–|a|to)\s*)(\d+(?:[.,]\d+)?)\b`)
+	mixedQtyRe   = regexp.MustCompile(`^(\d+)\s+(\d+)/(\d+)\b`)
+	fractionRe   = regexp.MustCompile(`^(\d+)/(\d+)\b`)
+	unicodeQtyRe = regexp.MustCompile(`^(\d+)?\s*([½⅓⅔¼¾⅛])`)
+	decimalQtyRe = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)`)
+
+	// timerRe finds durations such as "cozinhe por 20 minutos", "bake 25-30 min" or "asse por 1 hora".
+	// For ranges the lower bound is used so the cook checks on the dish early rather than late.
+	timerRe = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)(?:\s*(?:-|–|a|to)\s*\d+(?:[.,]\d+)?)?\s*(minutos|minuto|minutes|minute|mins|min|horas|hora|hours|hour|hrs|hr|h)\b`)
+)
+
+var unicodeFractions = map[string]float64{
+	"½": 0.5, "⅓": 1.0 / 3, "⅔": 2.0 / 3, "¼": 0.25, "¾": 0.75, "⅛": 0.125,
+}
+
+// ScaleIngredient multiplies the quantity at the start of an ingredient line, e.g.
+// "1 1/2 xícara de farinha" scaled by 2 becomes "3 xícara de farinha".
+// Lines without a leading quantity ("sal a gosto") are returned unchanged.
+func ScaleIngredient(line string, factor float64) string {
+	trimmed := strings.TrimSpace(line)
+	if factor == 1 || factor <= 0 || trimmed == "" {
+		return line
+	}
+	// Keep the decimal separator the recipe was written with: "1,5 kg" scales to "2,1 kg"
+	comma := strings.Contains(decimalQtyRe.FindString(trimmed), ",")
+
+	if m := rangeQtyRe.FindStringSubmatch(trimmed); m != nil {
+		low, high := parseNumber(m[1]), parseNumber(m[3])
+		return formatQuantity(low*factor, comma) + m[2] + formatQuantity(high*factor, comma) + trimmed[len(m[0]):]
+	}
+	if m := mixedQtyRe.FindStringSubmatch(trimmed); m != nil {
+		whole, num, den := parseNumber(m[1]), parseNumber(m[2]), parseNumber(m[3])
+		if den != 0 {
+			return formatQuantity((whole+num/den)*factor, false) + trimmed[len(m[0]):]
+		}
+	}
+	if m := fractionRe.FindStringSubmatch(trimmed); m != nil {
+		num, den := parseNumber(m[1]), parseNumber(m[2])
+		if den != 0 {
+			return formatQuantity(num/den*factor, false) + trimmed[len(m[0]):]
+		}
+	}
+	if m := unicodeQtyRe.FindStringSubmatch(trimmed); m != nil {
+		qty := unicodeFractions[m[2]]
+		if m[1] != "" {
+			qty += parseNumber(m[1])
+		}
+		return formatQuantity(qty*factor, false) + trimmed[len(m[0]):]
+	}
+	if m := decimalQtyRe.FindStringSubmatch(trimmed); m != nil {
+		return formatQuantity(parseNumber(m[1])*factor, comma) + trimmed[len(m[0]):]
+	}
+	return line
+}
+
+// StepTimers returns the durations mentioned in a cooking step, in order of appearance.
+func StepTimers(step string) []time.Duration {
+	var timers []time.Duration
+	seen := make(map[time.Duration]bool)
+	for _, m := range timerRe.FindAllStringSubmatch(step, -1) {
+		value := parseNumber(m[1])
+		unit := time.Minute
+		if strings.HasPrefix(strings.ToLower(m[2]), "h") {
+			unit = time.Hour
+		}
+		d := time.Duration(value * float64(unit)).Round(time.Minute)
+		if d <= 0 || seen[d] {
+			continue
+		}
+		seen[d] = true
+		timers = append(timers, d)
+	}
+	return timers
+}
+
+func parseNumber(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return v
+}
+
+// formatQuantity renders whole numbers plainly, common fractions as "1 1/2",
+// and anything else with one decimal place.
+func formatQuantity(v float64, comma bool) string {
+	whole := math.Floor(v)
+	frac := v - whole
+	if frac < 0.05 {
+		return strconv.Itoa(int(whole))
+	}
+	if frac > 0.95 {
+		return strconv.Itoa(int(whole) + 1)
+	}
+
+	for _, f := range []struct {
+		value float64
+		text  string
+	}{{0.25, "1/4"}, {1.0 / 3, "1/3"}, {0.5, "1/2"}, {2.0 / 3, "2/3"}, {0.75, "3/4"}} {
+		if math.Abs(frac-f.value) < 0.03 {
+			if whole == 0 {
+				return f.text
+			}
+			return fmt.Sprintf("%d %s", int(whole), f.text)
+		}
+	}
+
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if comma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
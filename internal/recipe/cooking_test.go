@@ -0,0 +1,50 @@
+package recipe
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScaleIngredient(t *testing.T) {
+	tests := []struct {
+		line   string
+		factor float64
+		want   string
+	}{
+		{"2 ovos", 1.5, "3 ovos"},
+		{"200g de farinha", 0.5, "100g de farinha"},
+		{"1 1/2 xícara de leite", 2, "3 xícara de leite"},
+		{"1/2 colher de sal", 3, "1 1/2 colher de sal"},
+		{"½ cebola", 2, "1 cebola"},
+		{"1,5 kg de carne", 1.4, "2,1 kg de carne"},
+		{"2-3 dentes de alho", 2, "4-6 dentes de alho"},
+		{"Sal a gosto", 2, "Sal a gosto"},
+		{"3 tomates", 1, "3 tomates"},
+	}
+
+	for _, tt := range tests {
+		if got := ScaleIngredient(tt.line, tt.factor); got != tt.want {
+			t.Errorf("ScaleIngredient(%q, %v) = %q, want %q", tt.line, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestStepTimers(t *testing.T) {
+	tests := []struct {
+		step string
+		want []time.Duration
+	}{
+		{"Cozinhe por 20 minutos em fogo baixo.", []time.Duration{20 * time.Minute}},
+		{"Bake 25-30 min, then rest for 5 minutes.", []time.Duration{25 * time.Minute, 5 * time.Minute}},
+		{"Asse por 1 hora a 180 graus.", []time.Duration{time.Hour}},
+		{"Deixe marinar por 1,5 horas.", []time.Duration{90 * time.Minute}},
+		{"Misture 200 g de farinha.", nil},
+	}
+
+	for _, tt := range tests {
+		if got := StepTimers(tt.step); !slices.Equal(got, tt.want) {
+			t.Errorf("StepTimers(%q) = %v, want %v", tt.step, got, tt.want)
+		}
+	}
+}
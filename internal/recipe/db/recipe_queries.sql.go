@@ -7,10 +7,22 @@ package recipedb
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 	"time"
 )
 
+const countRecipes = `-- name: CountRecipes :one
+SELECT COUNT(*) FROM recipes
+`
+
+func (q *Queries) CountRecipes(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRecipes)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deleteRecipeByID = `-- name: DeleteRecipeByID :exec
 DELETE FROM recipes WHERE id = ?
 `
@@ -20,6 +32,16 @@ func (q *Queries) DeleteRecipeByID(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteRecipeIngredients = `-- name: DeleteRecipeIngredients :exec
+DELETE FROM recipe_ingredients
+WHERE recipe_id = ?
+`
+
+func (q *Queries) DeleteRecipeIngredients(ctx context.Context, recipeID string) error {
+	_, err := q.db.ExecContext(ctx, deleteRecipeIngredients, recipeID)
+	return err
+}
+
 const deleteRecipeTags = `-- name: DeleteRecipeTags :exec
 DELETE FROM recipe_tags
 WHERE recipe_id = ?
@@ -30,6 +52,67 @@ func (q *Queries) DeleteRecipeTags(ctx context.Context, recipeID string) error {
 	return err
 }
 
+const deleteTagSuggestion = `-- name: DeleteTagSuggestion :exec
+DELETE FROM tag_suggestions WHERE tag = ?
+`
+
+func (q *Queries) DeleteTagSuggestion(ctx context.Context, tag string) error {
+	_, err := q.db.ExecContext(ctx, deleteTagSuggestion, tag)
+	return err
+}
+
+const deleteTaxonomyTag = `-- name: DeleteTaxonomyTag :exec
+DELETE FROM tag_taxonomy WHERE tag = ?
+`
+
+func (q *Queries) DeleteTaxonomyTag(ctx context.Context, tag string) error {
+	_, err := q.db.ExecContext(ctx, deleteTaxonomyTag, tag)
+	return err
+}
+
+const expandTags = `-- name: ExpandTags :many
+WITH RECURSIVE wanted(tag) AS (
+    SELECT tag FROM tag_synonyms WHERE synonym IN (/*SLICE:tags*/?)
+    UNION
+    SELECT tag_taxonomy.tag FROM tag_taxonomy JOIN wanted ON tag_taxonomy.parent = wanted.tag
+)
+SELECT synonym FROM tag_synonyms
+WHERE tag IN (SELECT tag FROM wanted)
+`
+
+func (q *Queries) ExpandTags(ctx context.Context, tags []string) ([]string, error) {
+	query := expandTags
+	var queryParams []interface{}
+	if len(tags) > 0 {
+		for _, v := range tags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tags*/?", strings.Repeat(",?", len(tags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var synonym string
+		if err := rows.Scan(&synonym); err != nil {
+			return nil, err
+		}
+		items = append(items, synonym)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRandomRecipes = `-- name: GetRandomRecipes :many
 SELECT id, data, updated_at FROM recipes
 WHERE id NOT IN (/*SLICE:exclude_ids*/?)
@@ -88,6 +171,64 @@ func (q *Queries) GetRecipeByID(ctx context.Context, id string) (Recipe, error)
 	return i, err
 }
 
+const getRecipeBySourceURL = `-- name: GetRecipeBySourceURL :one
+SELECT id, data, updated_at FROM recipes
+WHERE json_extract(data, '$.source_url') = CAST(? AS TEXT)
+LIMIT 1
+`
+
+func (q *Queries) GetRecipeBySourceURL(ctx context.Context, sourceUrl string) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, getRecipeBySourceURL, sourceUrl)
+	var i Recipe
+	err := row.Scan(&i.ID, &i.Data, &i.UpdatedAt)
+	return i, err
+}
+
+const getRecipeIDsByIngredients = `-- name: GetRecipeIDsByIngredients :many
+SELECT recipe_id
+FROM recipe_ingredients
+WHERE ingredient IN (/*SLICE:ingredients*/?)
+UNION
+SELECT id FROM recipes
+WHERE NOT EXISTS (
+    SELECT 1 FROM recipe_ingredients
+    WHERE recipe_ingredients.recipe_id = recipes.id
+)
+`
+
+func (q *Queries) GetRecipeIDsByIngredients(ctx context.Context, ingredients []string) ([]string, error) {
+	query := getRecipeIDsByIngredients
+	var queryParams []interface{}
+	if len(ingredients) > 0 {
+		for _, v := range ingredients {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ingredients*/?", strings.Repeat(",?", len(ingredients))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ingredients*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var recipe_id string
+		if err := rows.Scan(&recipe_id); err != nil {
+			return nil, err
+		}
+		items = append(items, recipe_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRecipeIDsByTags = `-- name: GetRecipeIDsByTags :many
 SELECT DISTINCT recipe_id
 FROM recipe_tags
@@ -127,6 +268,93 @@ func (q *Queries) GetRecipeIDsByTags(ctx context.Context, tags []string) ([]stri
 	return items, nil
 }
 
+const getRecipeIDsOutsideFilter = `-- name: GetRecipeIDsOutsideFilter :many
+SELECT id FROM recipes
+WHERE (CAST(?1 AS INTEGER) > 0 AND (prep_minutes IS NULL OR prep_minutes > CAST(?1 AS INTEGER)))
+   OR (CAST(?2 AS INTEGER) > 0 AND (servings IS NULL OR servings < CAST(?2 AS INTEGER)))
+   OR (CAST(?3 AS TEXT) != '' AND (meal_type IS NULL OR meal_type != CAST(?3 AS TEXT)))
+   OR (CAST(?4 AS TEXT) != '' AND (cuisine IS NULL OR cuisine != CAST(?4 AS TEXT)))
+   OR (CAST(?5 AS TEXT) != '' AND (main_protein IS NULL OR main_protein != CAST(?5 AS TEXT)))
+`
+
+type GetRecipeIDsOutsideFilterParams struct {
+	MaxPrepMinutes int64
+	MinServings    int64
+	MealType       string
+	Cuisine        string
+	MainProtein    string
+}
+
+func (q *Queries) GetRecipeIDsOutsideFilter(ctx context.Context, arg GetRecipeIDsOutsideFilterParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getRecipeIDsOutsideFilter,
+		arg.MaxPrepMinutes,
+		arg.MinServings,
+		arg.MealType,
+		arg.Cuisine,
+		arg.MainProtein,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecipeIDsWithoutTags = `-- name: GetRecipeIDsWithoutTags :many
+SELECT id FROM recipes
+WHERE NOT EXISTS (
+    SELECT 1 FROM recipe_tags
+    WHERE recipe_tags.recipe_id = recipes.id AND tag IN (/*SLICE:tags*/?)
+)
+`
+
+func (q *Queries) GetRecipeIDsWithoutTags(ctx context.Context, tags []string) ([]string, error) {
+	query := getRecipeIDsWithoutTags
+	var queryParams []interface{}
+	if len(tags) > 0 {
+		for _, v := range tags {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:tags*/?", strings.Repeat(",?", len(tags))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:tags*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRecipesByIDs = `-- name: GetRecipesByIDs :many
 SELECT id, data, updated_at FROM recipes
 WHERE id IN (/*SLICE:ids*/?)
@@ -165,6 +393,95 @@ func (q *Queries) GetRecipesByIDs(ctx context.Context, ids []string) ([]Recipe,
 	return items, nil
 }
 
+const getTagSuggestion = `-- name: GetTagSuggestion :one
+SELECT tag, label_pt, recipe_id, occurrences, created_at FROM tag_suggestions
+WHERE tag = ?
+`
+
+func (q *Queries) GetTagSuggestion(ctx context.Context, tag string) (TagSuggestion, error) {
+	row := q.db.QueryRowContext(ctx, getTagSuggestion, tag)
+	var i TagSuggestion
+	err := row.Scan(
+		&i.Tag,
+		&i.LabelPt,
+		&i.RecipeID,
+		&i.Occurrences,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTagsBySynonyms = `-- name: GetTagsBySynonyms :many
+SELECT tag_synonyms.synonym, tag_taxonomy.tag, tag_taxonomy.label_pt, tag_taxonomy.category, tag_taxonomy.parent
+FROM tag_synonyms
+JOIN tag_taxonomy ON tag_taxonomy.tag = tag_synonyms.tag
+WHERE tag_synonyms.synonym IN (/*SLICE:synonyms*/?)
+`
+
+type GetTagsBySynonymsRow struct {
+	Synonym  string
+	Tag      string
+	LabelPt  string
+	Category string
+	Parent   sql.NullString
+}
+
+func (q *Queries) GetTagsBySynonyms(ctx context.Context, synonyms []string) ([]GetTagsBySynonymsRow, error) {
+	query := getTagsBySynonyms
+	var queryParams []interface{}
+	if len(synonyms) > 0 {
+		for _, v := range synonyms {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:synonyms*/?", strings.Repeat(",?", len(synonyms))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:synonyms*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsBySynonymsRow
+	for rows.Next() {
+		var i GetTagsBySynonymsRow
+		if err := rows.Scan(
+			&i.Synonym,
+			&i.Tag,
+			&i.LabelPt,
+			&i.Category,
+			&i.Parent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTaxonomyTag = `-- name: GetTaxonomyTag :one
+SELECT tag, label_pt, category, parent FROM tag_taxonomy
+WHERE tag = ?
+`
+
+func (q *Queries) GetTaxonomyTag(ctx context.Context, tag string) (TagTaxonomy, error) {
+	row := q.db.QueryRowContext(ctx, getTaxonomyTag, tag)
+	var i TagTaxonomy
+	err := row.Scan(
+		&i.Tag,
+		&i.LabelPt,
+		&i.Category,
+		&i.Parent,
+	)
+	return i, err
+}
+
 const insertRecipe = `-- name: InsertRecipe :exec
 INSERT INTO recipes (id, data, updated_at)
 VALUES (?, ?, ?)
@@ -185,6 +502,22 @@ func (q *Queries) InsertRecipe(ctx context.Context, arg InsertRecipeParams) erro
 	return err
 }
 
+const insertRecipeIngredient = `-- name: InsertRecipeIngredient :exec
+INSERT INTO recipe_ingredients (recipe_id, ingredient)
+VALUES (?, ?)
+ON CONFLICT (recipe_id, ingredient) DO NOTHING
+`
+
+type InsertRecipeIngredientParams struct {
+	RecipeID   string
+	Ingredient string
+}
+
+func (q *Queries) InsertRecipeIngredient(ctx context.Context, arg InsertRecipeIngredientParams) error {
+	_, err := q.db.ExecContext(ctx, insertRecipeIngredient, arg.RecipeID, arg.Ingredient)
+	return err
+}
+
 const insertRecipeTag = `-- name: InsertRecipeTag :exec
 INSERT INTO recipe_tags (recipe_id, tag)
 VALUES (?, ?)
@@ -201,6 +534,22 @@ func (q *Queries) InsertRecipeTag(ctx context.Context, arg InsertRecipeTagParams
 	return err
 }
 
+const insertTagSynonym = `-- name: InsertTagSynonym :exec
+INSERT INTO tag_synonyms (synonym, tag)
+VALUES (?, ?)
+ON CONFLICT (synonym) DO UPDATE SET tag = EXCLUDED.tag
+`
+
+type InsertTagSynonymParams struct {
+	Synonym string
+	Tag     string
+}
+
+func (q *Queries) InsertTagSynonym(ctx context.Context, arg InsertTagSynonymParams) error {
+	_, err := q.db.ExecContext(ctx, insertTagSynonym, arg.Synonym, arg.Tag)
+	return err
+}
+
 const listAllRecipes = `-- name: ListAllRecipes :many
 SELECT id, data, updated_at FROM recipes
 ORDER BY updated_at DESC
@@ -229,6 +578,63 @@ func (q *Queries) ListAllRecipes(ctx context.Context) ([]Recipe, error) {
 	return items, nil
 }
 
+const listRecipeIDsBelowExtractionVersion = `-- name: ListRecipeIDsBelowExtractionVersion :many
+SELECT id FROM recipes
+WHERE COALESCE(json_extract(data, '$.extraction_version'), 0) < CAST(? AS INTEGER)
+ORDER BY updated_at DESC
+`
+
+func (q *Queries) ListRecipeIDsBelowExtractionVersion(ctx context.Context, version int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipeIDsBelowExtractionVersion, version)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecipeTags = `-- name: ListRecipeTags :many
+SELECT recipe_id, tag FROM recipe_tags
+ORDER BY recipe_id, tag
+`
+
+func (q *Queries) ListRecipeTags(ctx context.Context) ([]RecipeTag, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipeTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RecipeTag
+	for rows.Next() {
+		var i RecipeTag
+		if err := rows.Scan(&i.RecipeID, &i.Tag); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRecipes = `-- name: ListRecipes :many
 SELECT id, data, updated_at FROM recipes
 WHERE id NOT IN (/*SLICE:exclude_ids*/?)
@@ -268,6 +674,168 @@ func (q *Queries) ListRecipes(ctx context.Context, excludeIds []string) ([]Recip
 	return items, nil
 }
 
+const listTagCounts = `-- name: ListTagCounts :many
+SELECT tag, COUNT(*) AS recipe_count
+FROM recipe_tags
+GROUP BY tag
+ORDER BY recipe_count DESC, tag
+`
+
+type ListTagCountsRow struct {
+	Tag         string
+	RecipeCount int64
+}
+
+func (q *Queries) ListTagCounts(ctx context.Context) ([]ListTagCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTagCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagCountsRow
+	for rows.Next() {
+		var i ListTagCountsRow
+		if err := rows.Scan(&i.Tag, &i.RecipeCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagSuggestions = `-- name: ListTagSuggestions :many
+SELECT tag, label_pt, recipe_id, occurrences, created_at FROM tag_suggestions
+ORDER BY occurrences DESC, tag
+`
+
+func (q *Queries) ListTagSuggestions(ctx context.Context) ([]TagSuggestion, error) {
+	rows, err := q.db.QueryContext(ctx, listTagSuggestions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagSuggestion
+	for rows.Next() {
+		var i TagSuggestion
+		if err := rows.Scan(
+			&i.Tag,
+			&i.LabelPt,
+			&i.RecipeID,
+			&i.Occurrences,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagSynonyms = `-- name: ListTagSynonyms :many
+SELECT synonym, tag FROM tag_synonyms
+ORDER BY tag, synonym
+`
+
+func (q *Queries) ListTagSynonyms(ctx context.Context) ([]TagSynonym, error) {
+	rows, err := q.db.QueryContext(ctx, listTagSynonyms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagSynonym
+	for rows.Next() {
+		var i TagSynonym
+		if err := rows.Scan(&i.Synonym, &i.Tag); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaxonomyTags = `-- name: ListTaxonomyTags :many
+SELECT tag, label_pt, category, parent FROM tag_taxonomy
+ORDER BY category, tag
+`
+
+func (q *Queries) ListTaxonomyTags(ctx context.Context) ([]TagTaxonomy, error) {
+	rows, err := q.db.QueryContext(ctx, listTaxonomyTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagTaxonomy
+	for rows.Next() {
+		var i TagTaxonomy
+		if err := rows.Scan(
+			&i.Tag,
+			&i.LabelPt,
+			&i.Category,
+			&i.Parent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const repointTagChildren = `-- name: RepointTagChildren :exec
+UPDATE tag_taxonomy
+SET parent = ?
+WHERE parent = ?
+`
+
+type RepointTagChildrenParams struct {
+	ToTag   sql.NullString
+	FromTag sql.NullString
+}
+
+func (q *Queries) RepointTagChildren(ctx context.Context, arg RepointTagChildrenParams) error {
+	_, err := q.db.ExecContext(ctx, repointTagChildren, arg.ToTag, arg.FromTag)
+	return err
+}
+
+const repointTagSynonyms = `-- name: RepointTagSynonyms :exec
+UPDATE tag_synonyms
+SET tag = ?
+WHERE tag = ?
+`
+
+type RepointTagSynonymsParams struct {
+	ToTag   string
+	FromTag string
+}
+
+func (q *Queries) RepointTagSynonyms(ctx context.Context, arg RepointTagSynonymsParams) error {
+	_, err := q.db.ExecContext(ctx, repointTagSynonyms, arg.ToTag, arg.FromTag)
+	return err
+}
+
 const updateRecipeData = `-- name: UpdateRecipeData :exec
 UPDATE recipes
 SET data = ?
@@ -283,3 +851,52 @@ func (q *Queries) UpdateRecipeData(ctx context.Context, arg UpdateRecipeDataPara
 	_, err := q.db.ExecContext(ctx, updateRecipeData, arg.Data, arg.ID)
 	return err
 }
+
+const upsertTagSuggestion = `-- name: UpsertTagSuggestion :exec
+INSERT INTO tag_suggestions (tag, label_pt, recipe_id, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (tag) DO UPDATE SET occurrences = tag_suggestions.occurrences + 1
+`
+
+type UpsertTagSuggestionParams struct {
+	Tag       string
+	LabelPt   string
+	RecipeID  string
+	CreatedAt time.Time
+}
+
+func (q *Queries) UpsertTagSuggestion(ctx context.Context, arg UpsertTagSuggestionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTagSuggestion,
+		arg.Tag,
+		arg.LabelPt,
+		arg.RecipeID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const upsertTaxonomyTag = `-- name: UpsertTaxonomyTag :exec
+INSERT INTO tag_taxonomy (tag, label_pt, category, parent)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (tag) DO UPDATE SET
+    label_pt = EXCLUDED.label_pt,
+    category = EXCLUDED.category,
+    parent = EXCLUDED.parent
+`
+
+type UpsertTaxonomyTagParams struct {
+	Tag      string
+	LabelPt  string
+	Category string
+	Parent   sql.NullString
+}
+
+func (q *Queries) UpsertTaxonomyTag(ctx context.Context, arg UpsertTaxonomyTagParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTaxonomyTag,
+		arg.Tag,
+		arg.LabelPt,
+		arg.Category,
+		arg.Parent,
+	)
+	return err
+}
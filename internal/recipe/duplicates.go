@@ -0,0 +1,194 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"ai-meal-planner/internal/llm"
+	"ai-meal-planner/internal/value"
+)
+
+const (
+	// titleSimilarityThreshold accepts small differences such as typos,
+	// punctuation or a missing accent, but not a different dish.
+	titleSimilarityThreshold = 0.9
+
+	// embeddingSimilarityThreshold is deliberately high: recipes for the same
+	// dish from different sites score around 0.95, variations on a theme lower.
+	embeddingSimilarityThreshold = 0.92
+)
+
+// DuplicateReason explains why a recipe was considered a duplicate.
+type DuplicateReason string
+
+const (
+	DuplicateSourceURL DuplicateReason = "source_url"
+	DuplicateTitle     DuplicateReason = "title"
+	DuplicateSimilar   DuplicateReason = "similar"
+)
+
+// Duplicate is an existing recipe matching a newly clipped one.
+type Duplicate struct {
+	Recipe value.Recipe
+	Reason DuplicateReason
+	Score  float64
+}
+
+// DuplicateDetector checks a candidate recipe against the catalog before it is published.
+type DuplicateDetector struct {
+	recipeRepo *Repository
+	vectorRepo *llm.VectorRepository
+	embedGen   llm.EmbeddingGenerator
+}
+
+// NewDuplicateDetector creates a new DuplicateDetector.
+// vectorRepo and embedGen are optional; without them only URL and title checks run.
+func NewDuplicateDetector(recipeRepo *Repository, vectorRepo *llm.VectorRepository, embedGen llm.EmbeddingGenerator) *DuplicateDetector {
+	return &DuplicateDetector{
+		recipeRepo: recipeRepo,
+		vectorRepo: vectorRepo,
+		embedGen:   embedGen,
+	}
+}
+
+// FindDuplicate returns the closest existing recipe for the candidate, or nil if it looks new.
+// Checks run from cheapest to most expensive: source URL, title, then embedding similarity.
+func (d *DuplicateDetector) FindDuplicate(ctx context.Context, candidate value.Recipe) (*Duplicate, error) {
+	if sourceURL := NormalizeSourceURL(candidate.SourceURL); sourceURL != "" {
+		existing, err := d.recipeRepo.GetBySourceURL(ctx, sourceURL)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return &Duplicate{Recipe: *existing, Reason: DuplicateSourceURL, Score: 1}, nil
+		}
+	}
+
+	recipes, err := d.recipeRepo.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *Duplicate
+	for _, rec := range recipes {
+		score := titleSimilarity(candidate.Title, rec.Title)
+		if score >= titleSimilarityThreshold && (best == nil || score > best.Score) {
+			best = &Duplicate{Recipe: rec, Reason: DuplicateTitle, Score: score}
+		}
+	}
+	if best != nil {
+		return best, nil
+	}
+
+	if d.vectorRepo == nil || d.embedGen == nil {
+		return nil, nil
+	}
+
+	embedding, err := d.embedGen.GenerateEmbedding(ctx, candidate.ToEmbeddingText())
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed candidate recipe: %w", err)
+	}
+
+	scored, err := d.vectorRepo.FindSimilarScored(ctx, embedding, 1, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(scored) == 0 || scored[0].Score < embeddingSimilarityThreshold {
+		return nil, nil
+	}
+
+	existing, err := d.recipeRepo.Get(ctx, scored[0].RecipeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load similar recipe: %w", err)
+	}
+	return &Duplicate{Recipe: existing, Reason: DuplicateSimilar, Score: scored[0].Score}, nil
+}
+
+// NormalizeSourceURL reduces a page URL to a canonical form so the same page
+// shared with tracking parameters, a fragment or a trailing slash still matches.
+func NormalizeSourceURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = "https"
+	u.Host = strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || lower == "fbclid" || lower == "gclid" {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// normalizeTitle lowercases, folds accents and drops punctuation so
+// "Pão de Queijo!" and "pao de queijo" compare equal.
+func normalizeTitle(title string) string {
+	title = accentFolder.Replace(strings.ToLower(title))
+	var sb strings.Builder
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// titleSimilarity returns 1 for identical normalized titles, decreasing with edit distance.
+func titleSimilarity(a, b string) float64 {
+	ra, rb := []rune(normalizeTitle(a)), []rune(normalizeTitle(b))
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	longest := max(len(ra), len(rb))
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
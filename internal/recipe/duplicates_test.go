@@ -0,0 +1,109 @@
+package recipe
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"ai-meal-planner/internal/database"
+	"ai-meal-planner/internal/llm"
+	"ai-meal-planner/internal/llm/llmtest"
+	"ai-meal-planner/internal/value"
+)
+
+func TestDuplicateDetectorFindDuplicate(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "recipes.db")
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewRepository(db.SQL)
+	vectorRepo := llm.NewVectorRepository(db.SQL)
+
+	existing := []value.Recipe{
+		{ID: "pao", Title: "Pão de Queijo", SourceURL: NormalizeSourceURL("https://www.example.com/pao-de-queijo/"), UpdatedAt: "2023-01-01T00:00:00Z"},
+		{ID: "stew", Title: "Beef Stew", UpdatedAt: "2023-01-01T00:00:00Z"},
+	}
+	for _, rec := range existing {
+		if err := repo.Save(ctx, rec); err != nil {
+			t.Fatalf("save recipe %s: %v", rec.ID, err)
+		}
+	}
+	if err := vectorRepo.Save(ctx, "stew", []float32{1, 0, 0}, "hash", llm.EmbeddingMetadata{}); err != nil {
+		t.Fatalf("save embedding: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		candidate  value.Recipe
+		embedding  []float32
+		wantID     string
+		wantReason DuplicateReason
+	}{
+		{
+			name:       "same page with tracking parameters",
+			candidate:  value.Recipe{Title: "Cheese Bread", SourceURL: "http://example.com/pao-de-queijo?utm_source=telegram#recipe"},
+			embedding:  []float32{0, 1, 0},
+			wantID:     "pao",
+			wantReason: DuplicateSourceURL,
+		},
+		{
+			name:       "near identical title",
+			candidate:  value.Recipe{Title: "Pao de queijo!"},
+			embedding:  []float32{0, 1, 0},
+			wantID:     "pao",
+			wantReason: DuplicateTitle,
+		},
+		{
+			name:       "similar embedding",
+			candidate:  value.Recipe{Title: "Slow Cooker Beef Casserole"},
+			embedding:  []float32{0.99, 0.05, 0},
+			wantID:     "stew",
+			wantReason: DuplicateSimilar,
+		},
+		{
+			name:      "new recipe",
+			candidate: value.Recipe{Title: "Lemon Tart"},
+			embedding: []float32{0, 0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewDuplicateDetector(repo, vectorRepo, &llmtest.MockEmbeddingGenerator{Values: tt.embedding})
+			dup, err := detector.FindDuplicate(ctx, tt.candidate)
+			if err != nil {
+				t.Fatalf("FindDuplicate() error = %v", err)
+			}
+			if tt.wantID == "" {
+				if dup != nil {
+					t.Fatalf("expected no duplicate, got %+v", dup)
+				}
+				return
+			}
+			if dup == nil {
+				t.Fatalf("expected duplicate %q, got none", tt.wantID)
+			}
+			if dup.Recipe.ID != tt.wantID || dup.Reason != tt.wantReason {
+				t.Errorf("got %s (%s), want %s (%s)", dup.Recipe.ID, dup.Reason, tt.wantID, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestPostDataSourceURL(t *testing.T) {
+	post := PostData{HTML: `<p><em>Imported from: <a href="https://example.com/bolo?a=1&amp;b=2">https://example.com/bolo</a></em></p><h2>Ingredients</h2>`}
+	if got := post.SourceURL(); got != "https://example.com/bolo?a=1&b=2" {
+		t.Errorf("SourceURL() = %q", got)
+	}
+	if got := (PostData{HTML: "<p>Grandma's recipe</p>"}).SourceURL(); got != "" {
+		t.Errorf("SourceURL() for a hand-written post = %q, want empty", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"text/template"
 	"time"
 )
@@ -19,17 +20,33 @@ import (
 //go:embed extractor_prompt.md
 var extractorPrompt string
 
+// ExtractionVersion is bumped whenever the extractor starts returning new fields. It
+// never goes down: recipes stored below it are the ones a backfill extracts again.
+// Version 2 added steps, minutes, yield, cuisine, equipment, feature image and source URL.
+// Version 3 added meal type and main protein.
+// Version 4 added ingredient names in both languages.
+const ExtractionVersion = 4
+
 type ExtractorResult struct {
 	Recipe value.Recipe
 	Meta   shared.AgentMeta
 }
 
 type extractionResponse struct {
-	Title       string   `json:"title"`
-	SideDishes  []string `json:"side_dishes"`
-	Ingredients []string `json:"ingredients"`
-	PrepTime    string   `json:"prep_time"`
-	Servings    string   `json:"servings"`
+	Title           string    `json:"title"`
+	SideDishes      []string  `json:"side_dishes"`
+	Ingredients     []string  `json:"ingredients"`
+	IngredientNames []TagPair `json:"ingredient_names"`
+	Steps           []string  `json:"steps"`
+	PrepTime        string    `json:"prep_time"`
+	TotalMinutes    int       `json:"total_minutes"`
+	ActiveMinutes   int       `json:"active_minutes"`
+	Servings        string    `json:"servings"`
+	Yield           int       `json:"yield"`
+	Cuisine         string    `json:"cuisine"`
+	MealType        string    `json:"meal_type"`
+	MainProtein     string    `json:"main_protein"`
+	Equipment       []string  `json:"equipment"`
 }
 
 // Extractor encapsulates dependencies for value.recipe extraction and embedding processes.
@@ -79,14 +96,41 @@ func (e *Extractor) ExtractRecipe(
 			)
 	}
 
+	// Prefer numbers parsed from the text the user sees; the model's own numbers are a fallback
+	totalMinutes := ParseMinutes(extracted.PrepTime)
+	if totalMinutes == 0 {
+		totalMinutes = extracted.TotalMinutes
+	}
+	activeMinutes := extracted.ActiveMinutes
+	if totalMinutes > 0 {
+		activeMinutes = min(activeMinutes, totalMinutes)
+	}
+	yield := ParseServings(extracted.Servings)
+	if yield == 0 {
+		yield = extracted.Yield
+	}
+
 	rec := value.Recipe{
-		ID:          data.ID,
-		Title:       extracted.Title,
-		SideDishes:  extracted.SideDishes,
-		Ingredients: extracted.Ingredients,
-		PrepTime:    extracted.PrepTime,
-		Servings:    extracted.Servings,
-		UpdatedAt:   data.UpdatedAt,
+		ID:                data.ID,
+		Title:             extracted.Title,
+		SideDishes:        extracted.SideDishes,
+		Ingredients:       extracted.Ingredients,
+		IngredientNames:   normalizeIngredientNames(extracted.IngredientNames),
+		Steps:             extracted.Steps,
+		PrepTime:          extracted.PrepTime,
+		TotalMinutes:      totalMinutes,
+		ActiveMinutes:     activeMinutes,
+		Servings:          extracted.Servings,
+		Yield:             yield,
+		Cuisine:           strings.ToLower(strings.TrimSpace(extracted.Cuisine)),
+		MealType:          strings.ToLower(strings.TrimSpace(extracted.MealType)),
+		MainProtein:       strings.ToLower(strings.TrimSpace(extracted.MainProtein)),
+		Equipment:         extracted.Equipment,
+		FeatureImage:      data.Image(),
+		UpdatedAt:         data.UpdatedAt,
+		SourceURL:         NormalizeSourceURL(data.SourceURL()),
+		PostURL:           data.URL,
+		ExtractionVersion: ExtractionVersion,
 	}
 
 	return ExtractorResult{
@@ -107,9 +151,7 @@ func (e *Extractor) ProcessAndSaveEmbedding(
 	force bool,
 ) (embedding []float32, meta shared.AgentMeta, err error) {
 	embeddingSourceText := rec.ToEmbeddingText()
-	hasher := md5.New()
-	hasher.Write([]byte(embeddingSourceText))
-	currentTextHash := hex.EncodeToString(hasher.Sum(nil))
+	currentTextHash := embeddingTextHash(embeddingSourceText)
 
 	// Initialize meta for embedding generation
 	embedMeta := shared.AgentMeta{AgentName: "Embedding"}
@@ -163,6 +205,33 @@ func (e *Extractor) ProcessAndSaveEmbedding(
 	return embedding, embedMeta, nil
 }
 
+// normalizeIngredientNames lowercases the extracted names and flattens them into one
+// deduplicated list holding both languages.
+func normalizeIngredientNames(pairs []TagPair) []string {
+	normalized := make([]TagPair, 0, len(pairs))
+	for _, pair := range pairs {
+		pair = TagPair{Portuguese: normalizeTag(pair.Portuguese), English: normalizeTag(pair.English)}
+		if pair.Portuguese == "" {
+			pair.Portuguese = pair.English
+		}
+		if pair.English == "" {
+			pair.English = pair.Portuguese
+		}
+		if pair.English == "" {
+			continue
+		}
+		normalized = append(normalized, pair)
+	}
+	return flattenTagPairs(normalized)
+}
+
+// embeddingTextHash identifies the text an embedding was generated from, so unchanged recipes can reuse it.
+func embeddingTextHash(text string) string {
+	hasher := md5.New()
+	hasher.Write([]byte(text))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
 func buildExtractorPrompt(data PostData) (string, error) {
 	tmpl, err := template.New("normalizer").Parse(extractorPrompt)
 	if err != nil {
@@ -0,0 +1,59 @@
+package recipe
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// durationTokenRe matches a number or range followed by a time unit: "1h", "45-60 min", "1,5 horas".
+var durationTokenRe = regexp.MustCompile(`(\d+(?:[.,]\d+)?)(?:\s*(?:-|–|a|to)\s*(\d+(?:[.,]\d+)?))?\s*(horas|hora|hours|hour|hrs|hr|h|minutos|minuto|minutes|minute|mins|min|m)\b`)
+
+// hourMinutesRe matches the compact "1h15" form, where the minutes follow the hour unit directly.
+var hourMinutesRe = regexp.MustCompile(`(\d)\s*(?:horas|hora|hours|hour|hrs|hr|h)(\d{1,2})(?:\s*(?:minutos|minuto|minutes|minute|mins|min|m)\b)?`)
+
+// hourAndHalfRe matches a half hour spoken after the hours: "1 hora e meia", "2 hours and a half".
+var hourAndHalfRe = regexp.MustCompile(`(\d+)\s*(?:horas|hora|hours|hour|hrs|hr|h)\s+(?:e\s+mei[ao]|and\s+a\s+half)\b`)
+
+// servingsRe matches the first number or range in a servings string: "4 pessoas", "serves 4-6".
+var servingsRe = regexp.MustCompile(`(\d+)(?:\s*(?:-|–|a|to)\s*(\d+))?`)
+
+// ParseMinutes turns a free-text duration such as "30 mins", "1h15", "1 hora e meia"
+// or "45-60 min" into minutes. Ranges use the upper bound so time filters stay conservative.
+// Returns 0 when no duration can be read.
+func ParseMinutes(s string) int {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0
+	}
+
+	// Only numbers with a time unit count, so "serves 4, 20 min" reads as 20
+	s = hourMinutesRe.ReplaceAllString(s, "${1}h ${2}min")
+	s = hourAndHalfRe.ReplaceAllString(s, "${1}h 30min")
+	total := 0.0
+	for _, m := range durationTokenRe.FindAllStringSubmatch(s, -1) {
+		value := parseNumber(m[1])
+		if m[2] != "" {
+			value = parseNumber(m[2])
+		}
+		if strings.HasPrefix(m[3], "h") {
+			value *= 60
+		}
+		total += value
+	}
+
+	if strings.Contains(s, "meia hora") || strings.Contains(s, "half an hour") {
+		total += 30
+	}
+	return int(math.Round(total))
+}
+
+// ParseServings reads the number of servings from text such as "4 pessoas" or "serves 4-6".
+// Ranges use the lower bound so quantities are never overstated. Returns 0 when no number is found.
+func ParseServings(s string) int {
+	m := servingsRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	return int(parseNumber(m[1]))
+}
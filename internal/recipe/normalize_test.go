@@ -0,0 +1,57 @@
+package recipe
+
+import "testing"
+
+func TestParseMinutes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"30 mins", 30},
+		{"1h15", 75},
+		{"1 h 15 mins", 75},
+		{"1 hora e 30 minutos", 90},
+		{"1,5 horas", 90},
+		{"45-60 min", 60},
+		{"meia hora", 30},
+		{"1 hora e meia", 90},
+		{"2 horas e meio", 150},
+		{"1 hour and a half", 90},
+		{"2 hours", 120},
+		{"1h15min", 75},
+		{"1h 15min", 75},
+		{"Serves 4, ready in 20 minutes", 20},
+		{"Step 2: 10 min", 10},
+		{"2 hours, 4 servings", 120},
+		{"5 ingredientes, 1h30", 90},
+		{"forno a 180 graus por 40 minutos", 40},
+		{"3 eggs", 0},
+		{"Unknown", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseMinutes(tt.in); got != tt.want {
+			t.Errorf("ParseMinutes(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseServings(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"4 pessoas", 4},
+		{"Serves 4-6", 4},
+		{"6 a 8 porções", 6},
+		{"20 pieces", 20},
+		{"a family", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseServings(tt.in); got != tt.want {
+			t.Errorf("ParseServings(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,45 @@
 package recipe
 
+import (
+	"html"
+	"regexp"
+)
+
+// importedFromRe matches the attribution line the clipper writes at the top of every clipped post.
+var importedFromRe = regexp.MustCompile(`Imported from:\s*<a href="([^"]+)"`)
+
+// imgSrcRe matches the first image embedded in the post body.
+var imgSrcRe = regexp.MustCompile(`<img[^>]+src="([^"]+)"`)
+
 type PostData struct {
-	ID        string
-	Title     string
-	UpdatedAt string
-	HTML      string
-	Tags      []string
+	ID           string
+	Title        string
+	URL          string
+	UpdatedAt    string
+	HTML         string
+	FeatureImage string
+	Tags         []string
+}
+
+// SourceURL returns the page a clipped post was imported from,
+// or an empty string for posts written directly in Ghost.
+func (p PostData) SourceURL() string {
+	m := importedFromRe.FindStringSubmatch(p.HTML)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(m[1])
+}
+
+// Image returns the post's feature image, falling back to the first image in the body
+// (clipped posts embed the source photo there).
+func (p PostData) Image() string {
+	if p.FeatureImage != "" {
+		return p.FeatureImage
+	}
+	m := imgSrcRe.FindStringSubmatch(p.HTML)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(m[1])
 }
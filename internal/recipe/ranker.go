@@ -0,0 +1,167 @@
+package recipe
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/rating"
+	"ai-meal-planner/internal/value"
+)
+
+// CookedRecipe is a recipe from one of the user's confirmed plans.
+type CookedRecipe struct {
+	RecipeID    string
+	CookedAt    time.Time
+	Cuisine     string
+	MainProtein string
+}
+
+// RankingContext is what a Ranker knows about the user a search runs for.
+type RankingContext struct {
+	Now         time.Time
+	History     []CookedRecipe // Most recent first
+	Preferences rating.Preferences
+}
+
+// Ranker reorders search candidates for a user, best first. Candidates arrive in
+// retrieval order, most similar first for semantic searches.
+type Ranker interface {
+	Rank(candidates []value.Recipe, rc RankingContext) []value.Recipe
+}
+
+// diversityMinMeals keeps one or two recent meals from counting as a whole pattern;
+// it is roughly the number of Cook days in a week.
+const diversityMinMeals = 5
+
+// seasonalTags are the tags that suit cold or warm weather, in both languages.
+var seasonalTags = map[string][]string{
+	"cold": {"soup", "sopa", "stew", "ensopado", "caldo", "comfort food", "roast", "assado", "oven", "forno", "pressure cooker", "panela de pressão"},
+	"warm": {"salad", "salada", "grilled", "grelhado", "cold", "frio", "no-cook", "sem fogo", "ceviche", "smoothie"},
+}
+
+// DefaultRanker scores candidates by their retrieval position, then adjusts for the user's
+// history: recently cooked recipes sink, favorites not cooked in a while resurface,
+// proteins and cuisines repeated in the last weeks lose ground, and dishes that suit
+// the season gain a little.
+type DefaultRanker struct {
+	RecencyWeight   float64       // Penalty for a recipe cooked just now
+	RecencyHalfLife time.Duration // Time for the recency penalty to halve
+	FavoriteWeight  float64       // Bonus for a favorite not cooked in a long time
+	DiversityWeight float64       // Penalty when every recent meal shares the protein or cuisine
+	DiversityWindow time.Duration // How far back diversity looks
+	SeasonWeight    float64       // Bonus for dishes that suit the season
+	SouthernSeasons bool          // Seasons follow the southern hemisphere
+}
+
+// NewDefaultRanker creates a DefaultRanker with weights tuned on the ranking scenarios in
+// internal/llm/testdata. Seasons follow the southern hemisphere, where the household cooks.
+func NewDefaultRanker() *DefaultRanker {
+	return &DefaultRanker{
+		RecencyWeight:   1.0,
+		RecencyHalfLife: 3 * 7 * 24 * time.Hour,
+		FavoriteWeight:  0.5,
+		DiversityWeight: 0.6,
+		DiversityWindow: 3 * 7 * 24 * time.Hour,
+		SeasonWeight:    0.3,
+		SouthernSeasons: true,
+	}
+}
+
+// Rank implements Ranker.
+func (r *DefaultRanker) Rank(candidates []value.Recipe, rc RankingContext) []value.Recipe {
+	if len(candidates) < 2 {
+		return candidates
+	}
+
+	lastCooked := make(map[string]time.Time, len(rc.History))
+	proteins := make(map[string]int)
+	cuisines := make(map[string]int)
+	recentMeals := 0
+	for _, cooked := range rc.History {
+		if at, ok := lastCooked[cooked.RecipeID]; !ok || cooked.CookedAt.After(at) {
+			lastCooked[cooked.RecipeID] = cooked.CookedAt
+		}
+		if rc.Now.Sub(cooked.CookedAt) <= r.DiversityWindow {
+			recentMeals++
+			if cooked.MainProtein != "" && cooked.MainProtein != "none" {
+				proteins[cooked.MainProtein]++
+			}
+			if cooked.Cuisine != "" {
+				cuisines[cooked.Cuisine]++
+			}
+		}
+	}
+	season := r.season(rc.Now)
+
+	scores := make(map[string]float64, len(candidates))
+	for i, rec := range candidates {
+		score := 1 - float64(i)/float64(len(candidates))
+
+		// decay is 1 for a recipe cooked just now and tends to 0 as it ages; never cooked is 0
+		decay := 0.0
+		if at, ok := lastCooked[rec.ID]; ok {
+			decay = math.Exp2(-rc.Now.Sub(at).Hours() / r.RecencyHalfLife.Hours())
+		}
+		score -= r.RecencyWeight * decay
+		if rc.Preferences.IsFavorite(rec.ID) {
+			score += r.FavoriteWeight * (1 - decay)
+		}
+
+		meals := float64(max(recentMeals, diversityMinMeals))
+		score -= r.DiversityWeight * float64(proteins[rec.MainProtein]) / meals
+		score -= r.DiversityWeight / 2 * float64(cuisines[rec.Cuisine]) / meals
+
+		if hasAnyTag(rec, seasonalTags[season]) {
+			score += r.SeasonWeight
+		}
+		scores[rec.ID] = score
+	}
+
+	ranked := slices.Clone(candidates)
+	slices.SortStableFunc(ranked, func(a, b value.Recipe) int {
+		switch {
+		case scores[a.ID] > scores[b.ID]:
+			return -1
+		case scores[a.ID] < scores[b.ID]:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return ranked
+}
+
+// season returns "cold" from May to September and "warm" from November to March in the
+// southern hemisphere, the other way around in the northern one, and "" in between.
+func (r *DefaultRanker) season(now time.Time) string {
+	month := now.Month()
+	var cold, warm bool
+	switch {
+	case month >= time.May && month <= time.September:
+		cold = true
+	case month >= time.November || month <= time.March:
+		warm = true
+	}
+	if !r.SouthernSeasons {
+		cold, warm = warm, cold
+	}
+	switch {
+	case cold:
+		return "cold"
+	case warm:
+		return "warm"
+	default:
+		return ""
+	}
+}
+
+func hasAnyTag(rec value.Recipe, tags []string) bool {
+	for _, tag := range rec.Tags {
+		if slices.Contains(tags, strings.ToLower(tag)) {
+			return true
+		}
+	}
+	return false
+}
@@ -59,7 +59,8 @@ func TestExtractor_ExtractRecipe(t *testing.T) {
 	post := PostData{
 		ID:    "1",
 		Title: "Test Recipe",
-		HTML:  "<h1>Test Recipe</h1><p>Ingredients: ...</p>",
+		URL:   "https://blog.example.com/test-recipe/",
+		HTML:  `<p><i>Imported from: <a href="https://www.example.com/test/">https://www.example.com/test/</a></i></p><img src="https://img.test/a.jpg"><h1>Test Recipe</h1><p>Ingredients: ...</p>`,
 	}
 
 	t.Run("Success", func(t *testing.T) {
@@ -67,9 +68,18 @@ func TestExtractor_ExtractRecipe(t *testing.T) {
 			Response: `{
 				"title": "Test Recipe",
 				"ingredients": ["Ingredient 1", "Ingredient 2"],
+				"ingredient_names": [{"pt-BR": "Camarão ", "en": "shrimp"}, {"pt-BR": "alho", "en": ""}, {"pt-BR": "", "en": ""}],
 				"tags": ["test", "recipe"],
+				"steps": ["Mix", "Bake"],
 				"prep_time": "30 mins",
-				"servings": "4"
+				"total_minutes": 30,
+				"active_minutes": 45,
+				"servings": "4",
+				"yield": 4,
+				"cuisine": "Italian",
+				"meal_type": "main",
+				"main_protein": " Chicken",
+				"equipment": ["oven"]
 			}`,
 		}
 		// Extractor doesn't directly use EmbeddingGenerator or VectorRepository for ExtractRecipe
@@ -98,11 +108,48 @@ func TestExtractor_ExtractRecipe(t *testing.T) {
 		if extractorResult.Recipe.Servings != "4" {
 			t.Errorf("Expected Servings '4', got '%s'", extractorResult.Recipe.Servings)
 		}
+		rec := extractorResult.Recipe
+		if strings.Join(rec.Steps, "|") != "Mix|Bake" || rec.Yield != 4 || rec.Cuisine != "italian" || len(rec.Equipment) != 1 {
+			t.Errorf("unexpected extended fields: %+v", rec)
+		}
+		if rec.MealType != "main" || rec.MainProtein != "chicken" {
+			t.Errorf("unexpected extended fields: %+v", rec)
+		}
+		if got := strings.Join(rec.IngredientNames, "|"); got != "camarão|shrimp|alho" {
+			t.Errorf("IngredientNames = %q, want camarão|shrimp|alho", got)
+		}
+		if rec.TotalMinutes != 30 || rec.ActiveMinutes != 30 {
+			t.Errorf("Expected active minutes capped at total, got %d/%d", rec.ActiveMinutes, rec.TotalMinutes)
+		}
+		if rec.FeatureImage != "https://img.test/a.jpg" || rec.SourceURL != "https://example.com/test" {
+			t.Errorf("FeatureImage = %q, SourceURL = %q", rec.FeatureImage, rec.SourceURL)
+		}
+		if rec.PostURL != "https://blog.example.com/test-recipe/" {
+			t.Errorf("PostURL = %q, want the Ghost post URL", rec.PostURL)
+		}
+		if rec.ExtractionVersion != ExtractionVersion {
+			t.Errorf("ExtractionVersion = %d, want %d", rec.ExtractionVersion, ExtractionVersion)
+		}
 		if extractorResult.Meta.AgentName != "Extractor" {
 			t.Errorf("Expected agent name 'Extractor', got '%s'", extractorResult.Meta.AgentName)
 		}
 	})
 
+	t.Run("UnknownTotalKeepsActiveMinutes", func(t *testing.T) {
+		mockTextGeneration := &llmtest.MockTextGenerator{
+			Response: `{"title": "Test Recipe", "ingredients": ["Ingredient 1"], "prep_time": "", "active_minutes": 20}`,
+		}
+		extractor := NewExtractor(mockTextGeneration, nil, nil)
+
+		extractorResult, err := extractor.ExtractRecipe(ctx, post)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rec := extractorResult.Recipe; rec.TotalMinutes != 0 || rec.ActiveMinutes != 20 {
+			t.Errorf("Expected active minutes kept when the total is unknown, got %d/%d", rec.ActiveMinutes, rec.TotalMinutes)
+		}
+	})
+
 	t.Run("LLMError", func(t *testing.T) {
 		mockTextGeneration := &llmtest.MockTextGenerator{ShouldError: true}
 		extractor := NewExtractor(mockTextGeneration, nil, nil)
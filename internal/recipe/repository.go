@@ -2,11 +2,14 @@ package recipe
 
 import (
 	db "ai-meal-planner/internal/recipe/db"
+	"ai-meal-planner/internal/shared"
 	"ai-meal-planner/internal/value"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -81,44 +84,77 @@ func (r *Repository) Save(ctx context.Context, rec value.Recipe) error {
 		}
 	}
 
-	return nil
+	return replaceRecipeIngredients(ctx, r.queries, rec.ID, rec.IngredientNames)
 }
 
 // UpdateTags replaces a recipe's generated tags without changing the Ghost
 // source timestamp or any other normalized field.
 func (r *Repository) UpdateTags(ctx context.Context, rec value.Recipe) error {
+	return r.Update(ctx, rec)
+}
+
+// Update overwrites a stored recipe and its tags, bypassing the source timestamp
+// check in Save. Used when the same Ghost revision is normalized again.
+func (r *Repository) Update(ctx context.Context, rec value.Recipe) error {
 	recipeJSON, err := json.Marshal(rec)
 	if err != nil {
-		return fmt.Errorf("failed to marshal retagged recipe: %w", err)
+		return fmt.Errorf("failed to marshal recipe to JSON: %w", err)
 	}
 
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin retag transaction: %w", err)
+		return fmt.Errorf("failed to begin recipe update transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	queries := r.queries.WithTx(tx)
+	if err := updateRecipe(ctx, queries, rec.ID, string(recipeJSON), rec.Tags); err != nil {
+		return err
+	}
+	if err := replaceRecipeIngredients(ctx, queries, rec.ID, rec.IngredientNames); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recipe update transaction: %w", err)
+	}
+	return nil
+}
+
+// updateRecipe overwrites a recipe's JSON document and replaces its tags using the given queries.
+func updateRecipe(ctx context.Context, queries *db.Queries, id, data string, tags []string) error {
 	if err := queries.UpdateRecipeData(ctx, db.UpdateRecipeDataParams{
-		Data: string(recipeJSON),
-		ID:   rec.ID,
+		Data: data,
+		ID:   id,
 	}); err != nil {
-		return fmt.Errorf("failed to update retagged recipe: %w", err)
+		return fmt.Errorf("failed to update recipe: %w", err)
 	}
-	if err := queries.DeleteRecipeTags(ctx, rec.ID); err != nil {
+	if err := queries.DeleteRecipeTags(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete old recipe tags: %w", err)
 	}
-	for _, tag := range rec.Tags {
+	for _, tag := range tags {
 		if err := queries.InsertRecipeTag(ctx, db.InsertRecipeTagParams{
-			RecipeID: rec.ID,
+			RecipeID: id,
 			Tag:      tag,
 		}); err != nil {
 			return fmt.Errorf("failed to insert recipe tag %q: %w", tag, err)
 		}
 	}
+	return nil
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit retag transaction: %w", err)
+// replaceRecipeIngredients replaces the indexed ingredient names of a recipe using the given queries.
+func replaceRecipeIngredients(ctx context.Context, queries *db.Queries, id string, ingredients []string) error {
+	if err := queries.DeleteRecipeIngredients(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete old recipe ingredients: %w", err)
+	}
+	for _, ingredient := range ingredients {
+		if err := queries.InsertRecipeIngredient(ctx, db.InsertRecipeIngredientParams{
+			RecipeID:   id,
+			Ingredient: ingredient,
+		}); err != nil {
+			return fmt.Errorf("failed to insert recipe ingredient %q: %w", ingredient, err)
+		}
 	}
 	return nil
 }
@@ -138,6 +174,34 @@ func (r *Repository) Get(ctx context.Context, id string) (value.Recipe, error) {
 	return rec, nil
 }
 
+// GetBySourceURL retrieves the recipe clipped from the given page.
+// The URL must already be normalized with NormalizeSourceURL. Returns nil if none exists.
+func (r *Repository) GetBySourceURL(ctx context.Context, sourceURL string) (*value.Recipe, error) {
+	dbRecipe, err := r.queries.GetRecipeBySourceURL(ctx, sourceURL)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get recipe by source URL: %w", err)
+	}
+
+	var rec value.Recipe
+	if err := json.Unmarshal([]byte(dbRecipe.Data), &rec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal recipe JSON: %w", err)
+	}
+
+	return &rec, nil
+}
+
+// ListOutdatedIDs returns the IDs of recipes normalized by an extractor older than version.
+func (r *Repository) ListOutdatedIDs(ctx context.Context, version int) ([]string, error) {
+	ids, err := r.queries.ListRecipeIDsBelowExtractionVersion(ctx, int64(version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outdated recipes: %w", err)
+	}
+	return ids, nil
+}
+
 // GetByIds retrieves multiple recipes by their IDs.
 func (r *Repository) GetByIds(ctx context.Context, ids []string) ([]value.Recipe, error) {
 	dbRecipes, err := r.queries.GetRecipesByIDs(ctx, ids)
@@ -202,6 +266,15 @@ func (r *Repository) GetRandomReipes(
 	return mapRowsToRecipe(rows), nil
 }
 
+// Count returns the size of the recipe library.
+func (r *Repository) Count(ctx context.Context) (int, error) {
+	count, err := r.queries.CountRecipes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recipes: %w", err)
+	}
+	return int(count), nil
+}
+
 func (r *Repository) Delete(ctx context.Context, id string) error {
 	if err := r.queries.DeleteRecipeByID(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete recipe: %w", err)
@@ -209,11 +282,58 @@ func (r *Repository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// RecipeIDsOutsideFilter returns the IDs of recipes that fail any of the filter's SQL
+// constraints: prep time, servings, meal type, cuisine, main protein and required tags.
+// Recipes with unknown values are included, since they cannot be shown to match.
+// Exclusions by ID, tag and cooking history are left to the caller.
+func (r *Repository) RecipeIDsOutsideFilter(ctx context.Context, filter shared.RecipeFilter) ([]string, error) {
+	var ids []string
+
+	params := db.GetRecipeIDsOutsideFilterParams{
+		MaxPrepMinutes: int64(filter.MaxPrepMinutes),
+		MinServings:    int64(filter.MinServings),
+		MealType:       strings.ToLower(strings.TrimSpace(filter.MealType)),
+		Cuisine:        strings.ToLower(strings.TrimSpace(filter.Cuisine)),
+		MainProtein:    strings.ToLower(strings.TrimSpace(filter.MainProtein)),
+	}
+	if params != (db.GetRecipeIDsOutsideFilterParams{}) {
+		outside, err := r.queries.GetRecipeIDsOutsideFilter(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter recipes by limits and facets: %w", err)
+		}
+		ids = append(ids, outside...)
+	}
+
+	// Each required tag is met by the tag itself or anything the taxonomy expands it to,
+	// so "poultry" is satisfied by a recipe tagged "frango"
+	for _, tag := range slices.Compact(slices.Sorted(slices.Values(filter.IncludeTags))) {
+		group := []string{normalizeTag(tag)}
+		expanded, err := r.queries.ExpandTags(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand tag %q: %w", tag, err)
+		}
+		missing, err := r.queries.GetRecipeIDsWithoutTags(ctx, append(group, expanded...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter recipes by required tags: %w", err)
+		}
+		ids = append(ids, missing...)
+	}
+
+	return slices.Compact(slices.Sorted(slices.Values(ids))), nil
+}
+
+// RecipeIDsByTags returns the IDs of recipes carrying any of the tags. Tags are expanded
+// through the taxonomy, so "poultry" also matches recipes tagged "chicken" or "frango".
 func (r *Repository) RecipeIDsByTags(
 	ctx context.Context,
 	tags []string,
 ) ([]string, error) {
-	ids, err := r.queries.GetRecipeIDsByTags(ctx, tags)
+	expanded, err := r.queries.ExpandTags(ctx, tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand tags: %w", err)
+	}
+
+	ids, err := r.queries.GetRecipeIDsByTags(ctx, append(slices.Clone(tags), expanded...))
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +341,32 @@ func (r *Repository) RecipeIDsByTags(
 	return ids, nil
 }
 
+// RecipeIDsByIngredients returns the IDs of recipes containing any of the ingredients, in
+// either language. Ingredients are expanded through the taxonomy, so "shellfish" also
+// matches recipes with "shrimp" or "camarão". Recipes whose ingredients were never indexed
+// are returned too, since nothing shows they are free of them; backfill indexes them.
+func (r *Repository) RecipeIDsByIngredients(
+	ctx context.Context,
+	ingredients []string,
+) ([]string, error) {
+	names := make([]string, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		names = append(names, normalizeTag(ingredient))
+	}
+
+	expanded, err := r.queries.ExpandTags(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand ingredients: %w", err)
+	}
+
+	ids, err := r.queries.GetRecipeIDsByIngredients(ctx, append(names, expanded...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipes by ingredients: %w", err)
+	}
+
+	return ids, nil
+}
+
 func mapRowsToRecipe(rows []db.Recipe) []value.Recipe {
 	var recipes []value.Recipe
 	for _, dbRec := range rows {
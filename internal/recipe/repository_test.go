@@ -3,9 +3,12 @@ package recipe
 import (
 	"context"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"ai-meal-planner/internal/database"
+	"ai-meal-planner/internal/shared"
 	"ai-meal-planner/internal/value"
 )
 
@@ -47,3 +50,113 @@ func TestRepositoryGetByIdsPreservesRequestedOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestRepositoryRecipeIDsOutsideFilter(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "recipes.db")
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+
+	repo := NewRepository(db.SQL)
+	ctx := context.Background()
+	for _, rec := range []value.Recipe{
+		{ID: "quick", Title: "Omelette", TotalMinutes: 15, Yield: 2, MealType: "breakfast", Cuisine: "french", MainProtein: "eggs", Tags: []string{"vegetarian", "quick"}},
+		{ID: "slow", Title: "Feijoada", TotalMinutes: 180, Yield: 8, MealType: "main", Cuisine: "brazilian", MainProtein: "pork", Tags: []string{"stew"}},
+		{ID: "unknown", Title: "Old Recipe", Tags: []string{"vegetarian"}},
+		{ID: "chicken", Title: "Frango Assado", TotalMinutes: 20, Yield: 4, MealType: "main", Cuisine: "brazilian", MainProtein: "chicken", Tags: []string{"frango", "quick"}},
+	} {
+		if err := repo.Save(ctx, rec); err != nil {
+			t.Fatalf("save recipe %s: %v", rec.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter shared.RecipeFilter
+		want   []string
+	}{
+		{"no constraints", shared.RecipeFilter{}, nil},
+		{"quick meals", shared.RecipeFilter{MaxPrepMinutes: 30}, []string{"slow", "unknown"}},
+		{"large batches", shared.RecipeFilter{MinServings: 4}, []string{"quick", "unknown"}},
+		{"both limits", shared.RecipeFilter{MaxPrepMinutes: 200, MinServings: 4}, []string{"quick", "unknown"}},
+		{"meal type", shared.RecipeFilter{MealType: "Main"}, []string{"quick", "unknown"}},
+		{"cuisine", shared.RecipeFilter{Cuisine: "brazilian"}, []string{"quick", "unknown"}},
+		{"main protein", shared.RecipeFilter{MainProtein: "eggs"}, []string{"chicken", "slow", "unknown"}},
+		{"all include tags required", shared.RecipeFilter{IncludeTags: []string{"vegetarian", "quick"}}, []string{"chicken", "slow", "unknown"}},
+		{"single include tag", shared.RecipeFilter{IncludeTags: []string{"vegetarian"}}, []string{"chicken", "slow"}},
+		{"include tag matches its synonyms", shared.RecipeFilter{IncludeTags: []string{"Vegetariano"}}, []string{"chicken", "slow"}},
+		{"include tag matches narrower tags", shared.RecipeFilter{IncludeTags: []string{"poultry", "quick"}}, []string{"quick", "slow", "unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.RecipeIDsOutsideFilter(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("RecipeIDsOutsideFilter() error = %v", err)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// cookedMeals is a CookingHistory serving fixed meals.
+type cookedMeals []shared.CookedMeal
+
+func (c cookedMeals) CookedSince(ctx context.Context, userID string, since time.Time) ([]shared.CookedMeal, error) {
+	var meals []shared.CookedMeal
+	for _, meal := range c {
+		if !meal.CookedAt.Before(since) {
+			meals = append(meals, meal)
+		}
+	}
+	return meals, nil
+}
+
+func TestSearchServiceSkipsRecentlyCooked(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "recipes.db")
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+
+	repo := NewRepository(db.SQL)
+	ctx := context.Background()
+	for _, id := range []string{"recent", "old", "never"} {
+		if err := repo.Save(ctx, value.Recipe{ID: id, Title: id, UpdatedAt: "2023-01-01T00:00:00Z"}); err != nil {
+			t.Fatalf("save recipe %s: %v", id, err)
+		}
+	}
+	now := time.Now()
+	history := cookedMeals{
+		{RecipeID: "recent", CookedAt: now.AddDate(0, 0, -10)},
+		{RecipeID: "old", CookedAt: now.AddDate(0, 0, -40)},
+	}
+	service := NewSearchService(repo, nil, nil, nil, history).WithRanker(nil)
+
+	recipes, err := service.RandomRecipes(ctx, 10, shared.RecipeFilter{NotCookedWeeks: 4, UserID: "user1"})
+	if err != nil {
+		t.Fatalf("RandomRecipes() error = %v", err)
+	}
+	var got []string
+	for _, rec := range recipes {
+		got = append(got, rec.ID)
+	}
+	slices.Sort(got)
+	if want := []string{"never", "old"}; !slices.Equal(got, want) {
+		t.Errorf("RandomRecipes() = %v, want %v", got, want)
+	}
+}
@@ -3,16 +3,33 @@ package recipe
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"time"
 
 	"ai-meal-planner/internal/llm"
+	"ai-meal-planner/internal/rating"
+	"ai-meal-planner/internal/shared"
 	"ai-meal-planner/internal/value"
 )
 
+const (
+	// semanticSearchLimit is the number of recipes a semantic search returns.
+	semanticSearchLimit = 10
+	// rerankPoolSize is the number of nearest recipes the ranker chooses from for a known user.
+	rerankPoolSize = 20
+	// historyLookback is how far back the ranker sees the user's confirmed plans.
+	historyLookback = 26 * 7 * 24 * time.Hour
+)
+
 // SearchService handles operations related to recipes, including searching and retrieving.
 type SearchService struct {
 	recipeRepo *Repository
 	vectorRepo *llm.VectorRepository
 	embedGen   llm.EmbeddingGenerator
+	ratingRepo *rating.Repository
+	history    shared.CookingHistory
+	ranker     Ranker
 }
 
 // NewSearchService creates a new RecipeService instance.
@@ -20,32 +37,56 @@ func NewSearchService(
 	recipeRepo *Repository,
 	vectorRepo *llm.VectorRepository,
 	embedGen llm.EmbeddingGenerator,
+	ratingRepo *rating.Repository,
+	history shared.CookingHistory,
 ) *SearchService {
 	return &SearchService{
 		recipeRepo: recipeRepo,
 		vectorRepo: vectorRepo,
 		embedGen:   embedGen,
+		ratingRepo: ratingRepo,
+		history:    history,
+		ranker:     NewDefaultRanker(),
 	}
 }
 
+// WithRanker returns a copy of the service that orders results with ranker.
+func (s *SearchService) WithRanker(ranker Ranker) *SearchService {
+	clone := *s
+	clone.ranker = ranker
+	return &clone
+}
+
 // RecipeSemanticSearch retrieves recipe candidates based on a query string using semantic search.
 func (s *SearchService) RecipeSemanticSearch(
 	ctx context.Context,
 	query string,
-	excludeIDs []string,
-	excludeTags []string,
+	filter shared.RecipeFilter,
 ) ([]value.Recipe, error) {
 	queryEmbedding, err := s.embedGen.GenerateEmbedding(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate embedding for request: %w", err)
 	}
 
-	excludeIDs, err = s.combineTagAndIDsExclusion(ctx, excludeIDs, excludeTags)
+	prefs, err := s.preferences(ctx, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeIDs, err := s.excludedIDs(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
+	excludeIDs = append(excludeIDs, prefs.Excluded...)
 
-	recipeIds, err := s.vectorRepo.FindSimilar(ctx, queryEmbedding, 10, excludeIDs)
+	// Searches for a known user pull a larger pool so the ranker can promote recipes
+	// slightly further from the query
+	limit := semanticSearchLimit
+	if filter.UserID != "" {
+		limit = rerankPoolSize
+	}
+
+	recipeIds, err := s.vectorRepo.FindSimilar(ctx, queryEmbedding, limit, excludeIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve similar recipes: %w", err)
 	}
@@ -55,21 +96,49 @@ func (s *SearchService) RecipeSemanticSearch(
 		return nil, fmt.Errorf("failed to retrieve recipes: %w", err)
 	}
 
-	return recipes, nil
+	ranked, err := s.rank(ctx, recipes, filter.UserID, prefs)
+	if err != nil {
+		return nil, err
+	}
+	return ranked[:min(len(ranked), semanticSearchLimit)], nil
 }
 
 func (s *SearchService) RandomRecipes(
 	ctx context.Context,
 	limit int64,
-	excludeIDs []string,
-	excludeTags []string,
+	filter shared.RecipeFilter,
 ) ([]value.Recipe, error) {
-	excludeIDs, err := s.combineTagAndIDsExclusion(ctx, excludeIDs, excludeTags)
+	prefs, err := s.preferences(ctx, filter.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.recipeRepo.GetRandomReipes(ctx, limit, excludeIDs)
+	excludeIDs, err := s.excludedIDs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	excludeIDs = append(excludeIDs, prefs.Excluded...)
+
+	recipes, err := s.recipeRepo.GetRandomReipes(ctx, limit, excludeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.rank(ctx, recipes, filter.UserID, prefs)
+}
+
+// CountRecipes returns the size of the recipe library.
+func (s *SearchService) CountRecipes(ctx context.Context) (int, error) {
+	return s.recipeRepo.Count(ctx)
+}
+
+// FavoriteRecipeIDs returns the recipes the user rated as favorites.
+func (s *SearchService) FavoriteRecipeIDs(ctx context.Context, userID string) ([]string, error) {
+	prefs, err := s.preferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(prefs.Favorites)), nil
 }
 
 func (s *SearchService) GetByIds(
@@ -79,19 +148,124 @@ func (s *SearchService) GetByIds(
 	return s.recipeRepo.GetByIds(ctx, IDs)
 }
 
-func (s *SearchService) combineTagAndIDsExclusion(
+// excludedIDs turns a filter into the list of recipe IDs the search must skip.
+func (s *SearchService) excludedIDs(
 	ctx context.Context,
-	excludeIDs []string,
-	excludeTags []string,
+	filter shared.RecipeFilter,
 ) ([]string, error) {
-	if len(excludeTags) == 0 {
-		return excludeIDs, nil
+	excludeIDs := slices.Clone(filter.ExcludeIDs)
+
+	if len(filter.ExcludeTags) > 0 {
+		tagIds, err := s.recipeRepo.RecipeIDsByTags(ctx, filter.ExcludeTags)
+		if err != nil {
+			return nil, err
+		}
+		excludeIDs = append(excludeIDs, tagIds...)
+	}
+
+	if len(filter.ExcludeIngredients) > 0 {
+		ingredientIDs, err := s.recipeRepo.RecipeIDsByIngredients(ctx, filter.ExcludeIngredients)
+		if err != nil {
+			return nil, err
+		}
+		excludeIDs = append(excludeIDs, ingredientIDs...)
 	}
 
-	tagIds, err := s.recipeRepo.RecipeIDsByTags(ctx, excludeTags)
+	filteredIDs, err := s.recipeRepo.RecipeIDsOutsideFilter(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return append(excludeIDs, tagIds...), nil
+	if filter.NotCookedWeeks > 0 && filter.UserID != "" && s.history != nil {
+		cooked, err := s.history.CookedSince(ctx, filter.UserID, time.Now().AddDate(0, 0, -7*filter.NotCookedWeeks))
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve recently cooked recipes: %w", err)
+		}
+		for _, meal := range cooked {
+			excludeIDs = append(excludeIDs, meal.RecipeID)
+		}
+	}
+
+	return append(excludeIDs, filteredIDs...), nil
+}
+
+// preferences loads the ratings of the user the search runs for. Searches without a user
+// or without a rating repository get no preferences.
+func (s *SearchService) preferences(ctx context.Context, userID string) (rating.Preferences, error) {
+	if userID == "" || s.ratingRepo == nil {
+		return rating.Preferences{}, nil
+	}
+
+	prefs, err := s.ratingRepo.Preferences(ctx, userID, time.Now())
+	if err != nil {
+		return rating.Preferences{}, fmt.Errorf("failed to load recipe ratings: %w", err)
+	}
+	return prefs, nil
+}
+
+// rank orders recipes for the user with the service's ranker. Searches without a user keep their order.
+func (s *SearchService) rank(
+	ctx context.Context,
+	recipes []value.Recipe,
+	userID string,
+	prefs rating.Preferences,
+) ([]value.Recipe, error) {
+	if userID == "" || s.ranker == nil {
+		return recipes, nil
+	}
+
+	now := time.Now()
+	history, err := s.cookedHistory(ctx, userID, now.Add(-historyLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ranker.Rank(recipes, RankingContext{
+		Now:         now,
+		History:     history,
+		Preferences: prefs,
+	}), nil
+}
+
+// cookedHistory returns what the user cooked since the given time, most recent first, with
+// each recipe's cuisine and main protein. Recipes deleted since they were cooked are left out.
+func (s *SearchService) cookedHistory(ctx context.Context, userID string, since time.Time) ([]CookedRecipe, error) {
+	if s.history == nil {
+		return nil, nil
+	}
+	meals, err := s.history.CookedSince(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cooking history: %w", err)
+	}
+	if len(meals) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(meals))
+	for _, meal := range meals {
+		ids = append(ids, meal.RecipeID)
+	}
+	recipes, err := s.recipeRepo.GetByIds(ctx, slices.Compact(slices.Sorted(slices.Values(ids))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cooked recipes: %w", err)
+	}
+	byID := make(map[string]value.Recipe, len(recipes))
+	for _, rec := range recipes {
+		byID[rec.ID] = rec
+	}
+
+	history := make([]CookedRecipe, 0, len(meals))
+	for _, meal := range meals {
+		rec, ok := byID[meal.RecipeID]
+		if !ok {
+			continue
+		}
+		history = append(history, CookedRecipe{
+			RecipeID:    meal.RecipeID,
+			CookedAt:    meal.CookedAt,
+			Cuisine:     rec.Cuisine,
+			MainProtein: rec.MainProtein,
+		})
+	}
+	return history, nil
 }
@@ -0,0 +1,380 @@
+package recipe
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"ai-meal-planner/internal/llm"
+	db "ai-meal-planner/internal/recipe/db"
+	"ai-meal-planner/internal/value"
+)
+
+// TagCount is a tag and the number of recipes carrying it.
+type TagCount struct {
+	Tag     string
+	Recipes int
+}
+
+// TaxonomyEntry is a canonical tag and its synonyms as written by Export.
+type TaxonomyEntry struct {
+	Tag      string   `json:"tag"`
+	LabelPt  string   `json:"label_pt"`
+	Category string   `json:"category"`
+	Parent   string   `json:"parent,omitempty"`
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+// TagExport is a snapshot of the taxonomy and of every recipe's tags.
+type TagExport struct {
+	Taxonomy []TaxonomyEntry     `json:"taxonomy"`
+	Recipes  map[string][]string `json:"recipes"`
+}
+
+// TagAdmin curates recipe tags. Every change rewrites recipe_tags, the tags in the
+// recipe JSON and the recipe embeddings in a single transaction.
+type TagAdmin struct {
+	recipeRepo *Repository
+	vectorRepo *llm.VectorRepository
+	embedGen   llm.EmbeddingGenerator
+}
+
+// NewTagAdmin creates a new TagAdmin.
+func NewTagAdmin(recipeRepo *Repository, vectorRepo *llm.VectorRepository, embedGen llm.EmbeddingGenerator) *TagAdmin {
+	return &TagAdmin{
+		recipeRepo: recipeRepo,
+		vectorRepo: vectorRepo,
+		embedGen:   embedGen,
+	}
+}
+
+// List returns every tag in use with its recipe count, most used first.
+func (a *TagAdmin) List(ctx context.Context) ([]TagCount, error) {
+	rows, err := a.recipeRepo.queries.ListTagCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+
+	counts := make([]TagCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, TagCount{Tag: row.Tag, Recipes: int(row.RecipeCount)})
+	}
+	return counts, nil
+}
+
+// Merge replaces from with to on every recipe. In the taxonomy, from becomes a synonym of to,
+// and a canonical from hands its synonyms and children over to to. Returns the recipes changed.
+func (a *TagAdmin) Merge(ctx context.Context, from, to string) (int, error) {
+	from, to = normalizeTag(from), normalizeTag(to)
+	if from == "" || to == "" || from == to {
+		return 0, fmt.Errorf("merge needs two different tags")
+	}
+	// Merging a canonical tag into one of its own synonyms would delete it from the taxonomy
+	known, err := a.recipeRepo.queries.GetTagsBySynonyms(ctx, []string{to})
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up tag '%s': %w", to, err)
+	}
+	if len(known) > 0 && known[0].Tag == from {
+		return 0, fmt.Errorf("tag '%s' is already a synonym of '%s'", to, from)
+	}
+
+	recipes, err := a.recipesWithTags(ctx, []string{from})
+	if err != nil {
+		return 0, err
+	}
+	for i := range recipes {
+		recipes[i].Tags = replaceTags(recipes[i].Tags, map[string]string{from: to})
+	}
+
+	err = a.apply(ctx, recipes, func(q *db.Queries) error {
+		return moveTaxonomyTag(ctx, q, from, to)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(recipes), nil
+}
+
+// Rename is Merge for a target tag that does not exist yet, on recipes or in the taxonomy.
+func (a *TagAdmin) Rename(ctx context.Context, from, to string) (int, error) {
+	used, err := a.recipesWithTags(ctx, []string{normalizeTag(to)})
+	if err != nil {
+		return 0, err
+	}
+	known, err := a.recipeRepo.queries.GetTagsBySynonyms(ctx, []string{normalizeTag(to)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up tag '%s': %w", to, err)
+	}
+	if len(used) > 0 || len(known) > 0 {
+		return 0, fmt.Errorf("tag '%s' already exists, use merge instead", to)
+	}
+	return a.Merge(ctx, from, to)
+}
+
+// Prune removes tags carried by fewer than minCount recipes. The taxonomy is left untouched.
+// Returns the removed tags.
+func (a *TagAdmin) Prune(ctx context.Context, minCount int) ([]string, error) {
+	counts, err := a.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	removals := make(map[string]string)
+	var pruned []string
+	for _, c := range counts {
+		if c.Recipes < minCount {
+			removals[c.Tag] = ""
+			pruned = append(pruned, c.Tag)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	recipes, err := a.recipesWithTags(ctx, pruned)
+	if err != nil {
+		return nil, err
+	}
+	for i := range recipes {
+		recipes[i].Tags = replaceTags(recipes[i].Tags, removals)
+	}
+
+	if err := a.apply(ctx, recipes, nil); err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// Export snapshots the taxonomy, with synonyms, and the tags of every recipe.
+func (a *TagAdmin) Export(ctx context.Context) (TagExport, error) {
+	queries := a.recipeRepo.queries
+	tags, err := queries.ListTaxonomyTags(ctx)
+	if err != nil {
+		return TagExport{}, fmt.Errorf("failed to list taxonomy tags: %w", err)
+	}
+	synonyms, err := queries.ListTagSynonyms(ctx)
+	if err != nil {
+		return TagExport{}, fmt.Errorf("failed to list tag synonyms: %w", err)
+	}
+	recipeTags, err := queries.ListRecipeTags(ctx)
+	if err != nil {
+		return TagExport{}, fmt.Errorf("failed to list recipe tags: %w", err)
+	}
+
+	synonymsByTag := make(map[string][]string)
+	for _, s := range synonyms {
+		if s.Synonym != s.Tag {
+			synonymsByTag[s.Tag] = append(synonymsByTag[s.Tag], s.Synonym)
+		}
+	}
+
+	export := TagExport{Recipes: make(map[string][]string)}
+	for _, t := range tags {
+		export.Taxonomy = append(export.Taxonomy, TaxonomyEntry{
+			Tag:      t.Tag,
+			LabelPt:  t.LabelPt,
+			Category: t.Category,
+			Parent:   t.Parent.String,
+			Synonyms: synonymsByTag[t.Tag],
+		})
+	}
+	for _, rt := range recipeTags {
+		export.Recipes[rt.RecipeID] = append(export.Recipes[rt.RecipeID], rt.Tag)
+	}
+	return export, nil
+}
+
+// Import applies an export: taxonomy entries are upserted and recipes that still exist take the
+// exported tags. Recipes missing from the export keep their tags. Returns the recipes changed.
+func (a *TagAdmin) Import(ctx context.Context, export TagExport) (int, error) {
+	ids := make([]string, 0, len(export.Recipes))
+	for id := range export.Recipes {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	stored, err := a.recipeRepo.GetByIds(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	var changed []value.Recipe
+	for _, rec := range stored {
+		tags := replaceTags(export.Recipes[rec.ID], nil)
+		if slices.Equal(slices.Sorted(slices.Values(tags)), slices.Sorted(slices.Values(rec.Tags))) {
+			continue
+		}
+		rec.Tags = tags
+		changed = append(changed, rec)
+	}
+
+	err = a.apply(ctx, changed, func(q *db.Queries) error {
+		for _, entry := range export.Taxonomy {
+			tag := normalizeTag(entry.Tag)
+			if err := q.UpsertTaxonomyTag(ctx, db.UpsertTaxonomyTagParams{
+				Tag:      tag,
+				LabelPt:  normalizeTag(entry.LabelPt),
+				Category: entry.Category,
+				Parent:   sql.NullString{String: normalizeTag(entry.Parent), Valid: entry.Parent != ""},
+			}); err != nil {
+				return fmt.Errorf("failed to import taxonomy tag '%s': %w", tag, err)
+			}
+			for _, synonym := range append([]string{tag, entry.LabelPt}, entry.Synonyms...) {
+				if err := q.InsertTagSynonym(ctx, db.InsertTagSynonymParams{Synonym: normalizeTag(synonym), Tag: tag}); err != nil {
+					return fmt.Errorf("failed to import tag synonym '%s': %w", synonym, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
+
+// apply re-embeds the recipes, then saves them, their embeddings and the taxonomy change
+// in one transaction. Embeddings are generated first so the transaction never waits on the API.
+func (a *TagAdmin) apply(ctx context.Context, recipes []value.Recipe, taxonomyChange func(q *db.Queries) error) error {
+	metadata := a.embedGen.EmbeddingMetadata()
+	embeddings := make([][]float32, len(recipes))
+	for i, rec := range recipes {
+		embedding, err := a.embedGen.GenerateEmbedding(ctx, rec.ToEmbeddingText())
+		if err != nil {
+			return fmt.Errorf("failed to generate embedding for %q: %w", rec.Title, err)
+		}
+		if len(embedding) != metadata.Dimensions {
+			return fmt.Errorf(
+				"embedding dimensions mismatch: generator returned %d, metadata declares %d",
+				len(embedding),
+				metadata.Dimensions,
+			)
+		}
+		embeddings[i] = embedding
+	}
+
+	tx, err := a.recipeRepo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tag transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := a.recipeRepo.queries.WithTx(tx)
+	vectors := a.vectorRepo.WithTx(tx)
+	for i, rec := range recipes {
+		recipeJSON, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("failed to marshal recipe to JSON: %w", err)
+		}
+		if err := updateRecipe(ctx, queries, rec.ID, string(recipeJSON), rec.Tags); err != nil {
+			return err
+		}
+		if err := vectors.Save(ctx, rec.ID, embeddings[i], embeddingTextHash(rec.ToEmbeddingText()), metadata); err != nil {
+			return fmt.Errorf("failed to save embedding for %q: %w", rec.Title, err)
+		}
+	}
+
+	if taxonomyChange != nil {
+		if err := taxonomyChange(queries); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tag transaction: %w", err)
+	}
+	return nil
+}
+
+// recipesWithTags loads the recipes carrying any of the exact tags, without taxonomy expansion.
+func (a *TagAdmin) recipesWithTags(ctx context.Context, tags []string) ([]value.Recipe, error) {
+	ids, err := a.recipeRepo.queries.GetRecipeIDsByTags(ctx, tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recipes by tag: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return a.recipeRepo.GetByIds(ctx, ids)
+}
+
+// moveTaxonomyTag makes from an alias of to. A canonical from is replaced by to, which
+// inherits its label, category and parent when to is not in the taxonomy yet.
+func moveTaxonomyTag(ctx context.Context, q *db.Queries, from, to string) error {
+	target := ""
+	known, err := q.GetTagsBySynonyms(ctx, []string{to})
+	if err != nil {
+		return fmt.Errorf("failed to look up tag '%s': %w", to, err)
+	}
+	if len(known) > 0 {
+		target = known[0].Tag
+	}
+
+	canonical, err := q.GetTaxonomyTag(ctx, from)
+	if errors.Is(err, sql.ErrNoRows) {
+		if target == "" {
+			return nil
+		}
+		if err := q.InsertTagSynonym(ctx, db.InsertTagSynonymParams{Synonym: from, Tag: target}); err != nil {
+			return fmt.Errorf("failed to insert tag synonym '%s': %w", from, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get taxonomy tag '%s': %w", from, err)
+	}
+
+	if target == "" {
+		target = to
+		if err := q.UpsertTaxonomyTag(ctx, db.UpsertTaxonomyTagParams{
+			Tag:      to,
+			LabelPt:  canonical.LabelPt,
+			Category: canonical.Category,
+			Parent:   canonical.Parent,
+		}); err != nil {
+			return fmt.Errorf("failed to insert taxonomy tag '%s': %w", to, err)
+		}
+		if err := q.InsertTagSynonym(ctx, db.InsertTagSynonymParams{Synonym: to, Tag: to}); err != nil {
+			return fmt.Errorf("failed to insert tag synonym '%s': %w", to, err)
+		}
+	}
+
+	if err := q.RepointTagSynonyms(ctx, db.RepointTagSynonymsParams{ToTag: target, FromTag: from}); err != nil {
+		return fmt.Errorf("failed to move synonyms of '%s': %w", from, err)
+	}
+	if err := q.RepointTagChildren(ctx, db.RepointTagChildrenParams{
+		ToTag:   sql.NullString{String: target, Valid: true},
+		FromTag: sql.NullString{String: from, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("failed to move children of '%s': %w", from, err)
+	}
+	if err := q.DeleteTaxonomyTag(ctx, from); err != nil {
+		return fmt.Errorf("failed to delete taxonomy tag '%s': %w", from, err)
+	}
+	return nil
+}
+
+// replaceTags maps each tag through replacements, where an empty replacement drops the tag,
+// and removes duplicates while keeping the original order.
+func replaceTags(tags []string, replacements map[string]string) []string {
+	seen := make(map[string]struct{}, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if replacement, ok := replacements[tag]; ok {
+			tag = replacement
+		}
+		if tag == "" {
+			continue
+		}
+		if _, exists := seen[tag]; exists {
+			continue
+		}
+		seen[tag] = struct{}{}
+		result = append(result, tag)
+	}
+	return result
+}
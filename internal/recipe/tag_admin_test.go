@@ -0,0 +1,160 @@
+package recipe
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"ai-meal-planner/internal/llm"
+	"ai-meal-planner/internal/llm/llmtest"
+	"ai-meal-planner/internal/value"
+)
+
+func newTagAdminFixture(t *testing.T) (*TagAdmin, *Repository, *llm.VectorRepository, *llmtest.MockEmbeddingGenerator) {
+	t.Helper()
+	db := newTaxonomyTestDB(t)
+	repo := NewRepository(db.SQL)
+	vectorRepo := llm.NewVectorRepository(db.SQL)
+	embedGen := &llmtest.MockEmbeddingGenerator{}
+
+	for _, rec := range []value.Recipe{
+		{ID: "r1", Title: "Frango assado", Tags: []string{"frango", "chicken", "poultry meat"}},
+		{ID: "r2", Title: "Peito grelhado", Tags: []string{"poultry meat", "grelhado"}},
+		{ID: "r3", Title: "Bolo", Tags: []string{"bolo", "cake", "sobremesa"}},
+	} {
+		if err := repo.Save(context.Background(), rec); err != nil {
+			t.Fatalf("save recipe %s: %v", rec.ID, err)
+		}
+	}
+	return NewTagAdmin(repo, vectorRepo, embedGen), repo, vectorRepo, embedGen
+}
+
+func TestTagAdminMerge(t *testing.T) {
+	ctx := context.Background()
+	admin, repo, vectorRepo, embedGen := newTagAdminFixture(t)
+
+	changed, err := admin.Merge(ctx, "Poultry Meat", "chicken")
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if changed != 2 || embedGen.Calls != 2 {
+		t.Errorf("changed = %d, embeddings = %d, want 2 and 2", changed, embedGen.Calls)
+	}
+
+	r1, _ := repo.Get(ctx, "r1")
+	if !slices.Equal(r1.Tags, []string{"frango", "chicken"}) {
+		t.Errorf("r1 tags = %v", r1.Tags)
+	}
+	r2, _ := repo.Get(ctx, "r2")
+	if !slices.Equal(r2.Tags, []string{"chicken", "grelhado"}) {
+		t.Errorf("r2 tags = %v", r2.Tags)
+	}
+	if record, err := vectorRepo.Get(ctx, "r2"); err != nil || record.TextHash != embeddingTextHash(r2.ToEmbeddingText()) {
+		t.Errorf("embedding for r2 not refreshed: %+v, %v", record, err)
+	}
+
+	counts, err := admin.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if counts[0] != (TagCount{Tag: "chicken", Recipes: 2}) {
+		t.Errorf("top tag = %+v", counts[0])
+	}
+
+	lookup, err := NewTaxonomy(repo.db).Lookup(ctx, []string{"poultry meat"})
+	if err != nil || lookup["poultry meat"].Name != "chicken" {
+		t.Errorf("merged tag should become a synonym: %+v, %v", lookup, err)
+	}
+
+	if _, err := admin.Merge(ctx, "chicken", "frango"); err == nil {
+		t.Error("Merge() into the tag's own synonym should fail")
+	}
+	if lookup, err := NewTaxonomy(repo.db).Lookup(ctx, []string{"frango"}); err != nil || lookup["frango"].Name != "chicken" {
+		t.Errorf("canonical tag should survive a rejected merge: %+v, %v", lookup, err)
+	}
+}
+
+func TestTagAdminRenameMovesTaxonomyTag(t *testing.T) {
+	ctx := context.Background()
+	admin, repo, _, _ := newTagAdminFixture(t)
+
+	if _, err := admin.Rename(ctx, "grelhado", "frango"); err == nil {
+		t.Error("expected rename onto an existing tag to fail")
+	}
+
+	if _, err := admin.Rename(ctx, "sobremesa", "doces"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	r3, _ := repo.Get(ctx, "r3")
+	if !slices.Equal(r3.Tags, []string{"bolo", "cake", "doces"}) {
+		t.Errorf("r3 tags = %v", r3.Tags)
+	}
+
+	if _, err := admin.Rename(ctx, "dessert", "sweets"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	lookup, err := NewTaxonomy(repo.db).Lookup(ctx, []string{"dessert", "sobremesa", "sweets"})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	for _, term := range []string{"dessert", "sobremesa", "sweets"} {
+		if tag := lookup[term]; tag.Name != "sweets" || tag.Category != "meal-type" {
+			t.Errorf("lookup[%q] = %+v, want the renamed canonical tag", term, tag)
+		}
+	}
+}
+
+func TestTagAdminPrune(t *testing.T) {
+	ctx := context.Background()
+	admin, repo, _, _ := newTagAdminFixture(t)
+
+	pruned, err := admin.Prune(ctx, 2)
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	slices.Sort(pruned)
+	if !slices.Equal(pruned, []string{"bolo", "cake", "chicken", "frango", "grelhado", "sobremesa"}) {
+		t.Errorf("pruned = %v", pruned)
+	}
+	r3, _ := repo.Get(ctx, "r3")
+	if len(r3.Tags) != 0 {
+		t.Errorf("r3 tags = %v, want none", r3.Tags)
+	}
+}
+
+func TestTagAdminExportImport(t *testing.T) {
+	ctx := context.Background()
+	admin, repo, _, embedGen := newTagAdminFixture(t)
+
+	export, err := admin.Export(ctx)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !slices.Equal(export.Recipes["r3"], []string{"bolo", "cake", "sobremesa"}) {
+		t.Errorf("exported r3 tags = %v", export.Recipes["r3"])
+	}
+	if i := slices.IndexFunc(export.Taxonomy, func(e TaxonomyEntry) bool { return e.Tag == "chicken" }); i < 0 || !slices.Contains(export.Taxonomy[i].Synonyms, "frango") {
+		t.Errorf("exported taxonomy is missing chicken synonyms")
+	}
+
+	export.Recipes["r3"] = []string{"Cake", "dessert"}
+	export.Recipes["missing"] = []string{"ghost"}
+	export.Taxonomy = append(export.Taxonomy, TaxonomyEntry{Tag: "cake", LabelPt: "bolo", Category: "meal-type", Parent: "dessert"})
+
+	changed, err := admin.Import(ctx, export)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if changed != 1 || embedGen.Calls != 1 {
+		t.Errorf("changed = %d, embeddings = %d, want 1 and 1", changed, embedGen.Calls)
+	}
+	r3, _ := repo.Get(ctx, "r3")
+	if !slices.Equal(r3.Tags, []string{"cake", "dessert"}) {
+		t.Errorf("r3 tags = %v", r3.Tags)
+	}
+
+	ids, err := repo.RecipeIDsByTags(ctx, []string{"sobremesa"})
+	if err != nil || !slices.Equal(ids, []string{"r3"}) {
+		t.Errorf("imported child tag should match its parent: %v, %v", ids, err)
+	}
+}
@@ -35,12 +35,15 @@ type TaggerResult struct {
 }
 
 // Tagger enriches an already-normalized recipe with bilingual tags.
+// With a taxonomy, tags are mapped onto canonical tags and anything else
+// goes to the review queue instead of onto the recipe.
 type Tagger struct {
-	textGen llm.TextGenerator
+	textGen  llm.TextGenerator
+	taxonomy *Taxonomy
 }
 
-func NewTagger(textGen llm.TextGenerator) *Tagger {
-	return &Tagger{textGen: textGen}
+func NewTagger(textGen llm.TextGenerator, taxonomy *Taxonomy) *Tagger {
+	return &Tagger{textGen: textGen, taxonomy: taxonomy}
 }
 
 func (t *Tagger) Run(ctx context.Context, rec value.Recipe, sourceTags []string) (TaggerResult, error) {
@@ -48,7 +51,15 @@ func (t *Tagger) Run(ctx context.Context, rec value.Recipe, sourceTags []string)
 		return TaggerResult{}, fmt.Errorf("tagger text generator is not configured")
 	}
 
-	prompt, err := buildTaggerPrompt(rec, sourceTags)
+	var vocabulary []Tag
+	if t.taxonomy != nil {
+		var err error
+		if vocabulary, err = t.taxonomy.List(ctx); err != nil {
+			return TaggerResult{}, err
+		}
+	}
+
+	prompt, err := buildTaggerPrompt(rec, sourceTags, vocabulary)
 	if err != nil {
 		return TaggerResult{}, err
 	}
@@ -65,8 +76,12 @@ func (t *Tagger) Run(ctx context.Context, rec value.Recipe, sourceTags []string)
 		}
 		usage = addTokenUsage(usage, resp.Usage)
 
-		tags, err := parseTaggerResponse(resp.Message.Content)
+		pairs, err := parseTaggerResponse(resp.Message.Content)
 		if err == nil {
+			tags, err := t.constrain(ctx, rec.ID, pairs)
+			if err != nil {
+				return TaggerResult{}, err
+			}
 			return TaggerResult{
 				Tags: tags,
 				Meta: shared.AgentMeta{AgentName: "Tagger", Usage: usage, Latency: time.Since(start)},
@@ -85,7 +100,25 @@ func (t *Tagger) Run(ctx context.Context, rec value.Recipe, sourceTags []string)
 	return TaggerResult{}, fmt.Errorf("invalid tagger response after %d attempts: %w", maxTaggerAttempts, lastErr)
 }
 
-func buildTaggerPrompt(rec value.Recipe, sourceTags []string) (string, error) {
+// constrain keeps the pairs found in the taxonomy, in their canonical form, and queues the rest for review.
+func (t *Tagger) constrain(ctx context.Context, recipeID string, pairs []TagPair) ([]string, error) {
+	if t.taxonomy == nil {
+		return flattenTagPairs(pairs), nil
+	}
+
+	known, unknown, err := t.taxonomy.Canonicalize(ctx, pairs)
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range unknown {
+		if err := t.taxonomy.Suggest(ctx, pair, recipeID); err != nil {
+			return nil, err
+		}
+	}
+	return flattenTagPairs(known), nil
+}
+
+func buildTaggerPrompt(rec value.Recipe, sourceTags []string, vocabulary []Tag) (string, error) {
 	ingredientsJSON, err := json.Marshal(rec.Ingredients)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal tagger ingredients: %w", err)
@@ -100,11 +133,17 @@ func buildTaggerPrompt(rec value.Recipe, sourceTags []string) (string, error) {
 		return "", fmt.Errorf("failed to parse tagger prompt: %w", err)
 	}
 
+	known := make([]string, 0, len(vocabulary))
+	for _, tag := range vocabulary {
+		known = append(known, fmt.Sprintf("%s (%s)", tag.Name, tag.LabelPt))
+	}
+
 	data := struct {
 		Title           string
 		IngredientsJSON string
 		SourceTagsJSON  string
-	}{rec.Title, string(ingredientsJSON), string(sourceTagsJSON)}
+		KnownTags       string
+	}{rec.Title, string(ingredientsJSON), string(sourceTagsJSON), strings.Join(known, ", ")}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
@@ -113,7 +152,7 @@ func buildTaggerPrompt(rec value.Recipe, sourceTags []string) (string, error) {
 	return buf.String(), nil
 }
 
-func parseTaggerResponse(content string) ([]string, error) {
+func parseTaggerResponse(content string) ([]TagPair, error) {
 	var response taggerResponse
 	decoder := json.NewDecoder(strings.NewReader(llm.CleanJSON(content)))
 	decoder.DisallowUnknownFields()
@@ -124,15 +163,24 @@ func parseTaggerResponse(content string) ([]string, error) {
 		return nil, fmt.Errorf("tags must contain at least one translation pair")
 	}
 
-	seen := make(map[string]struct{}, len(response.Tags)*2)
-	tags := make([]string, 0, len(response.Tags)*2)
+	pairs := make([]TagPair, 0, len(response.Tags))
 	for i, pair := range response.Tags {
-		pt := strings.ToLower(strings.TrimSpace(pair.Portuguese))
-		en := strings.ToLower(strings.TrimSpace(pair.English))
+		pt := normalizeTag(pair.Portuguese)
+		en := normalizeTag(pair.English)
 		if pt == "" || en == "" {
 			return nil, fmt.Errorf("tag pair %d must contain both pt-BR and en", i)
 		}
-		for _, tag := range []string{pt, en} {
+		pairs = append(pairs, TagPair{Portuguese: pt, English: en})
+	}
+	return pairs, nil
+}
+
+// flattenTagPairs lists both values of every pair, without duplicates.
+func flattenTagPairs(pairs []TagPair) []string {
+	seen := make(map[string]struct{}, len(pairs)*2)
+	tags := make([]string, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		for _, tag := range []string{pair.Portuguese, pair.English} {
 			if _, exists := seen[tag]; exists {
 				continue
 			}
@@ -140,7 +188,7 @@ func parseTaggerResponse(content string) ([]string, error) {
 			tags = append(tags, tag)
 		}
 	}
-	return tags, nil
+	return tags
 }
 
 func addTokenUsage(total, current shared.TokenUsage) shared.TokenUsage {
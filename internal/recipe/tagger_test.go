@@ -21,7 +21,7 @@ func TestTaggerRunReturnsCompleteBilingualPairs(t *testing.T) {
 			{"pt-BR": "peixe", "en": "fish"}
 		]
 	}`}
-	tagger := NewTagger(textGen)
+	tagger := NewTagger(textGen, nil)
 
 	result, err := tagger.Run(context.Background(), salmonRecipe(), []string{"Air Fryer"})
 	if err != nil {
@@ -43,7 +43,7 @@ func TestTaggerRunRepairsIncompletePair(t *testing.T) {
 		{Message: llm.Message{Role: "assistant", Content: `{"tags":[{"pt-BR":"salmão","en":"salmon"}]}`}},
 	}}
 
-	result, err := NewTagger(textGen).Run(context.Background(), salmonRecipe(), nil)
+	result, err := NewTagger(textGen, nil).Run(context.Background(), salmonRecipe(), nil)
 	if err != nil {
 		t.Fatalf("Tagger.Run() error = %v", err)
 	}
@@ -75,7 +75,7 @@ func TestTagger_LiveEval(t *testing.T) {
 		model = llm.ModelTagger
 	}
 	client := llm.NewGroqClient(cfg, model, 0.0)
-	result, err := NewTagger(client).Run(ctx, salmonRecipe(), []string{"air fryer", "jantar"})
+	result, err := NewTagger(client, nil).Run(ctx, salmonRecipe(), []string{"air fryer", "jantar"})
 	if err != nil {
 		t.Fatalf("live tagger failed: %v", err)
 	}
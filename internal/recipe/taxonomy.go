@@ -0,0 +1,223 @@
+package recipe
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	db "ai-meal-planner/internal/recipe/db"
+)
+
+// Tag is a canonical entry of the tag taxonomy. Name is the English tag stored on recipes.
+type Tag struct {
+	Name     string
+	LabelPt  string
+	Category string // protein, cuisine, diet, meal-type, ingredient or technique
+	Parent   string
+}
+
+// TagSuggestion is a tag the tagger proposed that is not in the taxonomy yet.
+type TagSuggestion struct {
+	Tag         string
+	LabelPt     string
+	RecipeID    string // First recipe that proposed the tag
+	Occurrences int
+	CreatedAt   time.Time
+}
+
+// Taxonomy is the managed tag vocabulary: canonical tags, their synonyms in both
+// languages, parent tags and the review queue for new tags.
+type Taxonomy struct {
+	queries *db.Queries
+	db      *sql.DB
+}
+
+// NewTaxonomy creates a new Taxonomy.
+func NewTaxonomy(d *sql.DB) *Taxonomy {
+	return &Taxonomy{
+		queries: db.New(d),
+		db:      d,
+	}
+}
+
+// List returns every canonical tag, ordered by category.
+func (t *Taxonomy) List(ctx context.Context) ([]Tag, error) {
+	rows, err := t.queries.ListTaxonomyTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list taxonomy tags: %w", err)
+	}
+
+	tags := make([]Tag, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, Tag{Name: row.Tag, LabelPt: row.LabelPt, Category: row.Category, Parent: row.Parent.String})
+	}
+	return tags, nil
+}
+
+// Lookup resolves terms in either language to their canonical tags, keyed by the lowercase term.
+// Terms outside the taxonomy are absent from the result.
+func (t *Taxonomy) Lookup(ctx context.Context, terms []string) (map[string]Tag, error) {
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		normalized = append(normalized, normalizeTag(term))
+	}
+
+	rows, err := t.queries.GetTagsBySynonyms(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up tags: %w", err)
+	}
+
+	result := make(map[string]Tag, len(rows))
+	for _, row := range rows {
+		result[row.Synonym] = Tag{Name: row.Tag, LabelPt: row.LabelPt, Category: row.Category, Parent: row.Parent.String}
+	}
+	return result, nil
+}
+
+// Canonicalize maps tagger pairs onto the taxonomy. Pairs whose English or Portuguese value is
+// a known synonym come back as the canonical pair; the rest are returned as unknown.
+func (t *Taxonomy) Canonicalize(ctx context.Context, pairs []TagPair) (known, unknown []TagPair, err error) {
+	terms := make([]string, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		terms = append(terms, pair.English, pair.Portuguese)
+	}
+
+	lookup, err := t.Lookup(ctx, terms)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]struct{}, len(pairs))
+	for _, pair := range pairs {
+		tag, ok := lookup[normalizeTag(pair.English)]
+		if !ok {
+			tag, ok = lookup[normalizeTag(pair.Portuguese)]
+		}
+		if !ok {
+			unknown = append(unknown, pair)
+			continue
+		}
+		if _, exists := seen[tag.Name]; exists {
+			continue
+		}
+		seen[tag.Name] = struct{}{}
+		known = append(known, TagPair{Portuguese: tag.LabelPt, English: tag.Name})
+	}
+	return known, unknown, nil
+}
+
+// Suggest adds a tag outside the taxonomy to the review queue, or counts another occurrence of it.
+func (t *Taxonomy) Suggest(ctx context.Context, pair TagPair, recipeID string) error {
+	if err := t.queries.UpsertTagSuggestion(ctx, db.UpsertTagSuggestionParams{
+		Tag:       normalizeTag(pair.English),
+		LabelPt:   normalizeTag(pair.Portuguese),
+		RecipeID:  recipeID,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to queue tag suggestion '%s': %w", pair.English, err)
+	}
+	return nil
+}
+
+// Suggestions returns the review queue, most frequent first.
+func (t *Taxonomy) Suggestions(ctx context.Context) ([]TagSuggestion, error) {
+	rows, err := t.queries.ListTagSuggestions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tag suggestions: %w", err)
+	}
+
+	suggestions := make([]TagSuggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, TagSuggestion{
+			Tag:         row.Tag,
+			LabelPt:     row.LabelPt,
+			RecipeID:    row.RecipeID,
+			Occurrences: int(row.Occurrences),
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+	return suggestions, nil
+}
+
+// Approve promotes a suggestion to a canonical tag in the given category, optionally under a parent.
+func (t *Taxonomy) Approve(ctx context.Context, tag, category, parent string) error {
+	return t.resolveSuggestion(ctx, tag, func(q *db.Queries, s db.TagSuggestion) (string, error) {
+		if err := q.UpsertTaxonomyTag(ctx, db.UpsertTaxonomyTagParams{
+			Tag:      s.Tag,
+			LabelPt:  s.LabelPt,
+			Category: category,
+			Parent:   sql.NullString{String: normalizeTag(parent), Valid: parent != ""},
+		}); err != nil {
+			return "", fmt.Errorf("failed to insert taxonomy tag '%s': %w", s.Tag, err)
+		}
+		return s.Tag, nil
+	})
+}
+
+// MapSuggestion records a suggestion as a synonym of an existing canonical tag.
+func (t *Taxonomy) MapSuggestion(ctx context.Context, tag, canonical string) error {
+	return t.resolveSuggestion(ctx, tag, func(q *db.Queries, s db.TagSuggestion) (string, error) {
+		rows, err := q.GetTagsBySynonyms(ctx, []string{normalizeTag(canonical)})
+		if err != nil {
+			return "", fmt.Errorf("failed to look up tag '%s': %w", canonical, err)
+		}
+		if len(rows) == 0 {
+			return "", fmt.Errorf("tag '%s' is not in the taxonomy", canonical)
+		}
+		return rows[0].Tag, nil
+	})
+}
+
+// Reject drops a suggestion from the review queue.
+func (t *Taxonomy) Reject(ctx context.Context, tag string) error {
+	if err := t.queries.DeleteTagSuggestion(ctx, normalizeTag(tag)); err != nil {
+		return fmt.Errorf("failed to delete tag suggestion '%s': %w", tag, err)
+	}
+	return nil
+}
+
+// resolveSuggestion runs resolve in a transaction, registers both spellings of the
+// suggestion as synonyms of the tag it returns and removes the suggestion from the queue.
+func (t *Taxonomy) resolveSuggestion(
+	ctx context.Context,
+	tag string,
+	resolve func(q *db.Queries, s db.TagSuggestion) (string, error),
+) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	q := db.New(tx)
+
+	suggestion, err := q.GetTagSuggestion(ctx, normalizeTag(tag))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no pending suggestion for tag '%s'", tag)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get tag suggestion '%s': %w", tag, err)
+	}
+
+	canonical, err := resolve(q, suggestion)
+	if err != nil {
+		return err
+	}
+
+	for _, synonym := range []string{suggestion.Tag, suggestion.LabelPt} {
+		if err := q.InsertTagSynonym(ctx, db.InsertTagSynonymParams{Synonym: synonym, Tag: canonical}); err != nil {
+			return fmt.Errorf("failed to insert tag synonym '%s': %w", synonym, err)
+		}
+	}
+	if err := q.DeleteTagSuggestion(ctx, suggestion.Tag); err != nil {
+		return fmt.Errorf("failed to delete tag suggestion '%s': %w", suggestion.Tag, err)
+	}
+
+	return tx.Commit()
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
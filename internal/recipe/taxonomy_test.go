@@ -0,0 +1,231 @@
+package recipe
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"ai-meal-planner/internal/database"
+	"ai-meal-planner/internal/llm/llmtest"
+	"ai-meal-planner/internal/value"
+)
+
+func newTaxonomyTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "recipes.db")
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("initialize database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return db
+}
+
+func TestTaxonomyCanonicalize(t *testing.T) {
+	taxonomy := NewTaxonomy(newTaxonomyTestDB(t).SQL)
+
+	known, unknown, err := taxonomy.Canonicalize(context.Background(), []TagPair{
+		{Portuguese: "peito de frango", English: "chicken breast"},
+		{Portuguese: "frango", English: "poultry meat"},
+		{Portuguese: "salmão", English: "Salmon"},
+		{Portuguese: "maracujá", English: "passion fruit"},
+	})
+	if err != nil {
+		t.Fatalf("Canonicalize() error = %v", err)
+	}
+
+	wantKnown := []TagPair{{Portuguese: "frango", English: "chicken"}, {Portuguese: "salmão", English: "salmon"}}
+	if !slices.Equal(known, wantKnown) {
+		t.Errorf("known = %#v, want %#v", known, wantKnown)
+	}
+	if len(unknown) != 1 || unknown[0].English != "passion fruit" {
+		t.Errorf("unknown = %#v", unknown)
+	}
+}
+
+func TestTaxonomyReviewQueue(t *testing.T) {
+	ctx := context.Background()
+	taxonomy := NewTaxonomy(newTaxonomyTestDB(t).SQL)
+
+	for _, pair := range []TagPair{
+		{Portuguese: "maracujá", English: "passion fruit"},
+		{Portuguese: "maracujá", English: "passion fruit"},
+		{Portuguese: "coxa de frango", English: "chicken drumstick"},
+		{Portuguese: "doce", English: "sweet"},
+	} {
+		if err := taxonomy.Suggest(ctx, pair, "r1"); err != nil {
+			t.Fatalf("Suggest() error = %v", err)
+		}
+	}
+
+	suggestions, err := taxonomy.Suggestions(ctx)
+	if err != nil {
+		t.Fatalf("Suggestions() error = %v", err)
+	}
+	if len(suggestions) != 3 || suggestions[0].Tag != "passion fruit" || suggestions[0].Occurrences != 2 {
+		t.Fatalf("suggestions = %+v", suggestions)
+	}
+
+	if err := taxonomy.Approve(ctx, "passion fruit", "ingredient", ""); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if err := taxonomy.MapSuggestion(ctx, "chicken drumstick", "frango"); err != nil {
+		t.Fatalf("MapSuggestion() error = %v", err)
+	}
+	if err := taxonomy.Reject(ctx, "sweet"); err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if err := taxonomy.Approve(ctx, "sweet", "diet", ""); err == nil {
+		t.Error("expected approving a rejected suggestion to fail")
+	}
+
+	lookup, err := taxonomy.Lookup(ctx, []string{"maracujá", "coxa de frango", "doce"})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if lookup["maracujá"].Name != "passion fruit" || lookup["coxa de frango"].Name != "chicken" {
+		t.Errorf("lookup = %+v", lookup)
+	}
+	if _, ok := lookup["doce"]; ok {
+		t.Error("rejected tag must not be in the taxonomy")
+	}
+
+	if suggestions, _ := taxonomy.Suggestions(ctx); len(suggestions) != 0 {
+		t.Errorf("expected an empty review queue, got %+v", suggestions)
+	}
+}
+
+func TestTaggerRunConstrainsToTaxonomy(t *testing.T) {
+	ctx := context.Background()
+	taxonomy := NewTaxonomy(newTaxonomyTestDB(t).SQL)
+	textGen := &llmtest.MockTextGenerator{Response: `{
+		"tags": [
+			{"pt-BR": "salmão", "en": "salmon"},
+			{"pt-BR": "peixe", "en": "fish"},
+			{"pt-BR": "peixes", "en": "fishes"},
+			{"pt-BR": "gergelim", "en": "sesame"}
+		]
+	}`}
+
+	result, err := NewTagger(textGen, taxonomy).Run(ctx, value.Recipe{ID: "r1", Title: "Salmão com gergelim"}, nil)
+	if err != nil {
+		t.Fatalf("Tagger.Run() error = %v", err)
+	}
+
+	want := []string{"salmão", "salmon", "peixe", "fish"}
+	if !slices.Equal(result.Tags, want) {
+		t.Errorf("tags = %#v, want %#v", result.Tags, want)
+	}
+
+	suggestions, err := taxonomy.Suggestions(ctx)
+	if err != nil {
+		t.Fatalf("Suggestions() error = %v", err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Tag != "sesame" || suggestions[0].RecipeID != "r1" {
+		t.Errorf("suggestions = %+v", suggestions)
+	}
+}
+
+func TestRepositoryRecipeIDsByTagsExpandsTaxonomy(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(newTaxonomyTestDB(t).SQL)
+
+	for _, rec := range []value.Recipe{
+		{ID: "frango", Title: "Frango assado", Tags: []string{"frango"}},
+		{ID: "breast", Title: "Grilled chicken breast", Tags: []string{"chicken breast"}},
+		{ID: "shrimp", Title: "Shrimp pasta", Tags: []string{"camarão", "pasta"}},
+		{ID: "beef", Title: "Beef stew", Tags: []string{"beef"}},
+		{ID: "custom", Title: "Passion fruit mousse", Tags: []string{"passion fruit"}},
+	} {
+		if err := repo.Save(ctx, rec); err != nil {
+			t.Fatalf("save recipe %s: %v", rec.ID, err)
+		}
+	}
+
+	tests := []struct {
+		tags []string
+		want []string
+	}{
+		{[]string{"chicken"}, []string{"breast", "frango"}},
+		{[]string{"poultry"}, []string{"breast", "frango"}},
+		{[]string{"frutos do mar"}, []string{"shrimp"}},
+		{[]string{"meat", "seafood"}, []string{"beef", "shrimp"}},
+		{[]string{"passion fruit"}, []string{"custom"}},
+	}
+
+	for _, tt := range tests {
+		got, err := repo.RecipeIDsByTags(ctx, tt.tags)
+		if err != nil {
+			t.Fatalf("RecipeIDsByTags(%v) error = %v", tt.tags, err)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("RecipeIDsByTags(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestRepositoryRecipeIDsByIngredients(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository(newTaxonomyTestDB(t).SQL)
+
+	for _, rec := range []value.Recipe{
+		{ID: "moqueca", Title: "Moqueca de peixe", Tags: []string{"fish"}, IngredientNames: []string{"peixe", "fish", "camarão", "shrimp"}},
+		{ID: "risotto", Title: "Risoto de cogumelos", Tags: []string{"vegetarian"}, IngredientNames: []string{"arroz", "rice", "cogumelo", "mushroom"}},
+		{ID: "satay", Title: "Frango satay", Tags: []string{"chicken"}, IngredientNames: []string{"frango", "chicken", "amendoim", "peanut"}},
+	} {
+		if err := repo.Save(ctx, rec); err != nil {
+			t.Fatalf("save recipe %s: %v", rec.ID, err)
+		}
+	}
+
+	tests := []struct {
+		ingredients []string
+		want        []string
+	}{
+		{[]string{"Shrimp"}, []string{"moqueca"}},
+		{[]string{"shellfish"}, []string{"moqueca"}},
+		{[]string{"amendoim", "mushroom"}, []string{"risotto", "satay"}},
+		{[]string{"tofu"}, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := repo.RecipeIDsByIngredients(ctx, tt.ingredients)
+		if err != nil {
+			t.Fatalf("RecipeIDsByIngredients(%v) error = %v", tt.ingredients, err)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("RecipeIDsByIngredients(%v) = %v, want %v", tt.ingredients, got, tt.want)
+		}
+	}
+
+	// Re-saving replaces the indexed names instead of accumulating them
+	if err := repo.Update(ctx, value.Recipe{ID: "satay", Title: "Frango satay", IngredientNames: []string{"frango", "chicken"}}); err != nil {
+		t.Fatalf("update recipe: %v", err)
+	}
+	got, err := repo.RecipeIDsByIngredients(ctx, []string{"peanut"})
+	if err != nil {
+		t.Fatalf("RecipeIDsByIngredients after update error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("RecipeIDsByIngredients after update = %v, want none", got)
+	}
+
+	// A recipe extracted before ingredients were indexed cannot be shown to lack any of them
+	if err := repo.Save(ctx, value.Recipe{ID: "legacy", Title: "Bobó de camarão"}); err != nil {
+		t.Fatalf("save legacy recipe: %v", err)
+	}
+	got, err = repo.RecipeIDsByIngredients(ctx, []string{"peanut"})
+	if err != nil {
+		t.Fatalf("RecipeIDsByIngredients with a legacy recipe error = %v", err)
+	}
+	if !slices.Equal(got, []string{"legacy"}) {
+		t.Errorf("RecipeIDsByIngredients with a legacy recipe = %v, want [legacy]", got)
+	}
+}
@@ -0,0 +1,16 @@
+package reminder
+
+// Kinds of reminders, each sent at most once per user and local day.
+const (
+	KindDailyMeal = "daily_meal" // Today's meal and what to prepare for tomorrow
+	KindPlanNudge = "plan_nudge" // Next week has no plan yet
+)
+
+// Settings are a user's reminder preferences.
+type Settings struct {
+	Timezone string // IANA name such as "America/Sao_Paulo"; empty means the bot's default
+	Enabled  bool
+}
+
+// DefaultSettings apply to users who never changed their reminders.
+var DefaultSettings = Settings{Enabled: true}
@@ -0,0 +1,53 @@
+package reminder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ai-meal-planner/internal/value"
+)
+
+// freezerProteins are main proteins usually bought or kept frozen.
+var freezerProteins = map[string]bool{
+	"beef": true, "pork": true, "chicken": true, "lamb": true, "fish": true,
+	"seafood": true, "shrimp": true, "turkey": true, "duck": true, "sausage": true,
+}
+
+// Step keywords, in English and Portuguese, for work that has to start the day before.
+// Each matches at the start of a word, so stems like "marinat" cover "marinating" while
+// "marinara" sauce stays out.
+var (
+	thawRe      = regexp.MustCompile(`\b(thaw|defrost|descongel)`)
+	marinateRe  = regexp.MustCompile(`\b(marinat|marinad|marinand|marinar\b)`)
+	soakRe      = regexp.MustCompile(`\b(soak|de molho\b)`)
+	overnightRe = regexp.MustCompile(`\b(overnight|night before|de véspera|na véspera|da noite para o dia)\b`)
+)
+
+// PrepAhead lists what to do the evening before cooking rec: thawing its protein,
+// marinating, soaking or any other overnight step. It returns nil when the recipe
+// needs nothing ahead.
+func PrepAhead(rec value.Recipe) []string {
+	steps := strings.ToLower(strings.Join(rec.Steps, "\n"))
+	protein := strings.ToLower(strings.TrimSpace(rec.MainProtein))
+
+	var tasks []string
+	switch {
+	case thawRe.MatchString(steps):
+		tasks = append(tasks, fmt.Sprintf("🧊 Thaw what %s needs: move it to the fridge tonight.", rec.Title))
+	case freezerProteins[protein]:
+		tasks = append(tasks, fmt.Sprintf("🧊 If the %s for %s is frozen, move it to the fridge tonight.", protein, rec.Title))
+	}
+	marinate, soak := marinateRe.MatchString(steps), soakRe.MatchString(steps)
+	if marinate {
+		tasks = append(tasks, fmt.Sprintf("🥣 %s needs marinating; start it tonight.", rec.Title))
+	}
+	if soak {
+		tasks = append(tasks, fmt.Sprintf("🫘 %s has something to soak; leave it in water tonight.", rec.Title))
+	}
+	// Overnight marinades and soaks are already covered above
+	if !marinate && !soak && overnightRe.MatchString(steps) {
+		tasks = append(tasks, fmt.Sprintf("🌙 %s has a step that starts the night before.", rec.Title))
+	}
+	return tasks
+}
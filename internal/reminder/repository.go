@@ -0,0 +1,92 @@
+package reminder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	reminderdb "ai-meal-planner/internal/reminder/db"
+)
+
+// Repository handles persistence of reminder settings and of the reminders already sent.
+type Repository struct {
+	queries *reminderdb.Queries
+	db      *sql.DB
+}
+
+// NewRepository creates a new reminder repository.
+func NewRepository(d *sql.DB) *Repository {
+	return &Repository{
+		queries: reminderdb.New(d),
+		db:      d,
+	}
+}
+
+// GetSettings returns the user's reminder settings, or DefaultSettings if they never changed them.
+func (r *Repository) GetSettings(ctx context.Context, userID string) (Settings, error) {
+	row, err := r.queries.GetReminderSettings(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultSettings, nil
+		}
+		return Settings{}, fmt.Errorf("failed to get reminder settings: %w", err)
+	}
+	return Settings{Timezone: row.Timezone, Enabled: row.Enabled}, nil
+}
+
+// SaveSettings stores the user's reminder settings after checking the timezone exists.
+func (r *Repository) SaveSettings(ctx context.Context, userID string, s Settings) error {
+	if _, err := LoadLocation(s.Timezone, time.UTC); err != nil {
+		return err
+	}
+	if err := r.queries.UpsertReminderSettings(ctx, reminderdb.UpsertReminderSettingsParams{
+		UserID:    userID,
+		Timezone:  s.Timezone,
+		Enabled:   s.Enabled,
+		UpdatedAt: time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("failed to save reminder settings: %w", err)
+	}
+	return nil
+}
+
+// Claim records that a reminder of the given kind is being sent for the user's local
+// date. It returns false when it was already sent, so restarts never repeat one.
+func (r *Repository) Claim(ctx context.Context, userID, kind string, localDate time.Time) (bool, error) {
+	claimed, err := r.queries.InsertReminderDelivery(ctx, reminderdb.InsertReminderDeliveryParams{
+		UserID:    userID,
+		Kind:      kind,
+		LocalDate: localDate.Format(time.DateOnly),
+		SentAt:    time.Now().UTC(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record %s reminder: %w", kind, err)
+	}
+	return claimed > 0, nil
+}
+
+// Release forgets a claimed reminder that could not be delivered, so the next run
+// sends it again.
+func (r *Repository) Release(ctx context.Context, userID, kind string, localDate time.Time) error {
+	if err := r.queries.DeleteReminderDelivery(ctx, reminderdb.DeleteReminderDeliveryParams{
+		UserID:    userID,
+		Kind:      kind,
+		LocalDate: localDate.Format(time.DateOnly),
+	}); err != nil {
+		return fmt.Errorf("failed to release %s reminder: %w", kind, err)
+	}
+	return nil
+}
+
+// LoadLocation resolves a timezone name, using fallback for the empty name.
+func LoadLocation(name string, fallback *time.Location) (*time.Location, error) {
+	if name == "" {
+		return fallback, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,146 @@
+package reminder
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ai-meal-planner/internal/database"
+	"ai-meal-planner/internal/value"
+)
+
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "reminders.db")
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	return NewRepository(db.SQL)
+}
+
+func TestRepositorySettings(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	got, err := repo.GetSettings(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetSettings() error = %v", err)
+	}
+	if got != DefaultSettings {
+		t.Errorf("GetSettings() = %+v, want defaults", got)
+	}
+
+	want := Settings{Timezone: "America/Sao_Paulo", Enabled: false}
+	if err := repo.SaveSettings(ctx, "u1", want); err != nil {
+		t.Fatalf("SaveSettings() error = %v", err)
+	}
+	if got, _ := repo.GetSettings(ctx, "u1"); got != want {
+		t.Errorf("GetSettings() = %+v, want %+v", got, want)
+	}
+
+	if err := repo.SaveSettings(ctx, "u1", Settings{Timezone: "Mars/Olympus", Enabled: true}); err == nil {
+		t.Error("SaveSettings() accepted an unknown timezone")
+	}
+}
+
+func TestRepositoryClaimOncePerDay(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	for i, tt := range []struct {
+		userID string
+		kind   string
+		day    time.Time
+		want   bool
+	}{
+		{"u1", KindDailyMeal, day, true},
+		{"u1", KindDailyMeal, day, false},
+		{"u1", KindPlanNudge, day, true},
+		{"u2", KindDailyMeal, day, true},
+		{"u1", KindDailyMeal, day.AddDate(0, 0, 1), true},
+	} {
+		got, err := repo.Claim(ctx, tt.userID, tt.kind, tt.day)
+		if err != nil {
+			t.Fatalf("Claim() #%d error = %v", i, err)
+		}
+		if got != tt.want {
+			t.Errorf("Claim() #%d = %v, want %v", i, got, tt.want)
+		}
+	}
+
+	if err := repo.Release(ctx, "u1", KindDailyMeal, day); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if got, _ := repo.Claim(ctx, "u1", KindDailyMeal, day); !got {
+		t.Error("Claim() after Release() = false, want true")
+	}
+	if got, _ := repo.Claim(ctx, "u1", KindPlanNudge, day); got {
+		t.Error("Release() of the daily meal also released the plan nudge")
+	}
+}
+
+func TestPrepAhead(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  value.Recipe
+		want []string
+	}{
+		{
+			name: "nothing ahead",
+			rec:  value.Recipe{Title: "Salad", MainProtein: "none", Steps: []string{"Toss everything."}},
+		},
+		{
+			name: "frozen protein",
+			rec:  value.Recipe{Title: "Roast", MainProtein: "Chicken", Steps: []string{"Roast for an hour."}},
+			want: []string{"🧊 If the chicken for Roast is frozen, move it to the fridge tonight."},
+		},
+		{
+			name: "thaw step wins over the protein",
+			rec:  value.Recipe{Title: "Ribs", MainProtein: "pork", Steps: []string{"Descongele as costelas.", "Deixe marinar por 12 horas."}},
+			want: []string{
+				"🧊 Thaw what Ribs needs: move it to the fridge tonight.",
+				"🥣 Ribs needs marinating; start it tonight.",
+			},
+		},
+		{
+			name: "marinara is not a marinade",
+			rec:  value.Recipe{Title: "Spaghetti", MainProtein: "none", Steps: []string{"Warm the marinara sauce.", "Toss with the pasta."}},
+		},
+		{
+			name: "marinade stems",
+			rec:  value.Recipe{Title: "Frango", MainProtein: "none", Steps: []string{"Deixe o frango marinando na geladeira.", "Asse."}},
+			want: []string{"🥣 Frango needs marinating; start it tonight."},
+		},
+		{
+			name: "overnight soak",
+			rec:  value.Recipe{Title: "Feijoada", Steps: []string{"Soak the beans overnight."}},
+			want: []string{"🫘 Feijoada has something to soak; leave it in water tonight."},
+		},
+		{
+			name: "other overnight step",
+			rec:  value.Recipe{Title: "Overnight oats", Steps: []string{"Mix and chill overnight."}},
+			want: []string{"🌙 Overnight oats has a step that starts the night before."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PrepAhead(tt.rec)
+			if len(got) != len(tt.want) {
+				t.Fatalf("PrepAhead() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("PrepAhead()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -3,11 +3,40 @@ package shared
 import (
 	"ai-meal-planner/internal/value"
 	"context"
+	"time"
 )
 
+// RecipeFilter narrows a recipe search. Zero values mean "no constraint".
+type RecipeFilter struct {
+	ExcludeIDs         []string
+	ExcludeTags        []string
+	IncludeTags        []string // Recipes must carry every one of these tags
+	ExcludeIngredients []string // Ingredients, in either language, no recipe may contain; recipes with unknown ingredients are left out
+	MaxPrepMinutes     int      // Recipes whose total time is unknown are left out when set
+	MinServings        int      // Recipes whose servings are unknown are left out when set
+	MealType           string
+	Cuisine            string
+	MainProtein        string
+	// NotCookedWeeks skips recipes from UserID's confirmed plans of the last N weeks.
+	NotCookedWeeks int
+	UserID         string
+}
+
 // RecipeSearcher defines the interface for searching recipes.
 type RecipeSearcher interface {
-	RecipeSemanticSearch(ctx context.Context, query string, excludeIDs []string, excludeTags []string) ([]value.Recipe, error)
-	RandomRecipes(ctx context.Context, limit int64, excludeIDs []string, excludeTags []string) ([]value.Recipe, error)
+	RecipeSemanticSearch(ctx context.Context, query string, filter RecipeFilter) ([]value.Recipe, error)
+	RandomRecipes(ctx context.Context, limit int64, filter RecipeFilter) ([]value.Recipe, error)
 	GetByIds(ctx context.Context, recipeIDs []string) ([]value.Recipe, error)
 }
+
+// CookedMeal is a Cook day of one of a user's confirmed plans.
+type CookedMeal struct {
+	RecipeID string
+	CookedAt time.Time
+}
+
+// CookingHistory reports what a user cooked. The planner implements it from the meal
+// plans it keeps, so recipe searches never read plans themselves.
+type CookingHistory interface {
+	CookedSince(ctx context.Context, userID string, since time.Time) ([]CookedMeal, error)
+}
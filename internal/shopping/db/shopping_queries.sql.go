@@ -44,6 +44,7 @@ SELECT sl.id, sl.user_id, sl.meal_plan_id, sl.items, sl.created_at
 FROM shopping_lists sl
 INNER JOIN user_meal_plans ump ON sl.meal_plan_id = ump.id
 WHERE sl.user_id = ? AND ump.week_start_date = ?
+ORDER BY sl.id DESC
 LIMIT 1
 `
 
@@ -89,3 +90,19 @@ func (q *Queries) InsertShoppingList(ctx context.Context, arg InsertShoppingList
 	err := row.Scan(&id)
 	return id, err
 }
+
+const updateShoppingListItems = `-- name: UpdateShoppingListItems :exec
+UPDATE shopping_lists
+SET items = ?
+WHERE id = ?
+`
+
+type UpdateShoppingListItemsParams struct {
+	Items string
+	ID    int64
+}
+
+func (q *Queries) UpdateShoppingListItems(ctx context.Context, arg UpdateShoppingListItemsParams) error {
+	_, err := q.db.ExecContext(ctx, updateShoppingListItems, arg.Items, arg.ID)
+	return err
+}
@@ -95,6 +95,22 @@ func (r *Repository) GetByUserAndWeek(ctx context.Context, userID string, weekSt
 	}, nil
 }
 
+// UpdateItems replaces the items of a shopping list.
+func (r *Repository) UpdateItems(ctx context.Context, id int64, items []string) error {
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal shopping list items: %w", err)
+	}
+
+	if err := r.queries.UpdateShoppingListItems(ctx, shoppingdb.UpdateShoppingListItemsParams{
+		Items: string(itemsJSON),
+		ID:    id,
+	}); err != nil {
+		return fmt.Errorf("failed to update shopping list %d: %w", id, err)
+	}
+	return nil
+}
+
 // DeleteByMealPlanID deletes a shopping list by meal plan ID.
 func (r *Repository) DeleteByMealPlanID(ctx context.Context, mealPlanID int64) error {
 	return r.queries.DeleteShoppingListByMealPlanID(ctx, mealPlanID)
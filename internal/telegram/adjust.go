@@ -0,0 +1,140 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"ai-meal-planner/internal/audit"
+	"ai-meal-planner/internal/planner"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// adjustSessionTTL is how long an adjustment conversation waits for the next message.
+// Every message of feedback extends it.
+const adjustSessionTTL = 900
+
+// adjustFeedbackLimit caps the earlier feedback handed to the reviewer, keeping the newest.
+const adjustFeedbackLimit = 10
+
+// adjustSessionKeyboard ends an adjustment conversation: Done keeps the revised draft,
+// Cancel goes back to the draft as it was before the conversation.
+func adjustSessionKeyboard(sessionID int64) *tgbotapi.InlineKeyboardMarkup {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Done", fmt.Sprintf("adjustdone|%d", sessionID)),
+		tgbotapi.NewInlineKeyboardButtonData("✖️ Cancel", fmt.Sprintf("adjustcancel|%d", sessionID)),
+	))
+	return &keyboard
+}
+
+// openAdjustSession starts an adjustment conversation on a draft plan. Messages go to
+// the reviewer until the user presses Done or Cancel, or the session expires.
+func (b *Bot) openAdjustSession(ctx context.Context, userID string, plan *planner.MealPlan) (int64, error) {
+	return b.sessionRepo.Create(ctx, userID, SessionTypeAdjustPlan, StateAwaitingFeedback, SessionContextData{
+		PlanID:          plan.ID,
+		OriginalRequest: plan.OriginalRequest,
+		StartVersion:    plan.Version,
+	}, adjustSessionTTL)
+}
+
+// sendAdjustingPlan shows a draft revised during an adjustment conversation, inviting
+// more feedback.
+func (b *Bot) sendAdjustingPlan(chatID int64, messageID int, sessionID int64, plan *planner.MealPlan) {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, formatAdjustingPlan(plan))
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = adjustSessionKeyboard(sessionID)
+	b.api.Send(edit)
+}
+
+func formatAdjustingPlan(plan *planner.MealPlan) string {
+	return formatDraftPlanMarkdown(plan) + "\n_Send more feedback to keep adjusting, or finish below._"
+}
+
+// appendFeedback adds feedback to the conversation history, dropping the oldest
+// entries beyond adjustFeedbackLimit.
+func appendFeedback(history []string, feedback string) []string {
+	history = append(history, feedback)
+	if len(history) > adjustFeedbackLimit {
+		history = history[len(history)-adjustFeedbackLimit:]
+	}
+	return history
+}
+
+// handleAdjustDone ends an adjustment conversation and shows the draft with its usual
+// buttons. Format: "adjustdone|sessionID".
+func (b *Bot) handleAdjustDone(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	session, contextData, ok := b.endAdjustSession(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	plan, err := b.planRepo.GetByIDForUser(ctx, contextData.PlanID, userID)
+	if err != nil || plan == nil {
+		log.Printf("Error retrieving plan %d after adjustment session %d: %v", contextData.PlanID, session.ID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not retrieve plan.")
+		return
+	}
+	b.sendDraftPlan(query.Message.Chat.ID, query.Message.MessageID, plan)
+}
+
+// handleAdjustCancel ends an adjustment conversation and restores the draft it started
+// from, as a new version so the revisions stay in the history. Format: "adjustcancel|sessionID".
+func (b *Bot) handleAdjustCancel(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	session, contextData, ok := b.endAdjustSession(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+
+	plan, err := b.planRepo.GetByIDForUser(ctx, contextData.PlanID, userID)
+	if err != nil || plan == nil {
+		log.Printf("Error retrieving plan %d after adjustment session %d: %v", contextData.PlanID, session.ID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not retrieve plan.")
+		return
+	}
+	if contextData.StartVersion == 0 || plan.Version == contextData.StartVersion {
+		b.sendDraftPlan(chatID, messageID, plan)
+		return
+	}
+
+	restored, err := b.planRepo.RestoreVersion(ctx, userID, plan.ID, contextData.StartVersion)
+	if err != nil {
+		log.Printf("Error restoring plan %d to version %d: %v", plan.ID, contextData.StartVersion, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not restore the plan.")
+		return
+	}
+	_ = b.auditRepo.LogInteraction(ctx, userID, plan.ID, audit.ActionRestorePlan, plan.OriginalRequest, "Cancelled adjustments", plan, restored)
+	b.sendDraftPlan(chatID, messageID, restored)
+}
+
+// endAdjustSession deletes the adjustment session named by the callback, answering the
+// callback when it has already ended.
+func (b *Bot) endAdjustSession(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) (*Session, SessionContextData, bool) {
+	if len(parts) < 2 {
+		return nil, SessionContextData{}, false
+	}
+	sessionID, _ := strconv.ParseInt(parts[1], 10, 64)
+
+	session, err := b.sessionRepo.GetByID(ctx, sessionID, userID, time.Now())
+	if err != nil || session == nil || session.SessionType != SessionTypeAdjustPlan {
+		if err != nil {
+			log.Printf("Error retrieving adjustment session %d: %v", sessionID, err)
+		}
+		b.answerCallback(query, "This adjustment has already ended")
+		return nil, SessionContextData{}, false
+	}
+
+	contextData, err := session.GetContextData()
+	if err != nil {
+		log.Printf("Error parsing session context: %v", err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Invalid session data.")
+		return nil, SessionContextData{}, false
+	}
+	if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+		log.Printf("Error cleaning up session %d: %v", session.ID, err)
+	}
+	return session, contextData, true
+}
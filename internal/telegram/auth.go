@@ -0,0 +1,148 @@
+package telegram
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"ai-meal-planner/internal/config"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// callbackSignatureLen is the length of the signature appended to callback data.
+const callbackSignatureLen = 8
+
+// maxCallbackData is how long a button's callback data may be before signing; Telegram
+// rejects keyboards whose callback data exceeds 64 bytes, so signKeyboard cuts longer data.
+const maxCallbackData = 64 - callbackSignatureLen - 1
+
+// authorize returns the user behind an update when they are on the allowlist. Every
+// update type goes through it before any handler runs.
+func (b *Bot) authorize(update *tgbotapi.Update) (*tgbotapi.User, bool) {
+	from := update.SentFrom()
+	if from == nil {
+		return nil, false
+	}
+	return from, slices.Contains(b.cfg.TelegramAllowedUserIDs, from.ID)
+}
+
+// webhookSecretHeader carries the secret token given to setWebhook on every webhook request.
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// webhookSecretFor returns the configured webhook secret, or one derived from the bot
+// token so the webhook is protected without extra configuration.
+func webhookSecretFor(cfg *config.Config) string {
+	if cfg.TelegramWebhookSecret != "" {
+		return cfg.TelegramWebhookSecret
+	}
+	mac := hmac.New(sha256.New, []byte(cfg.TelegramBotToken))
+	mac.Write([]byte("webhook-secret"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validWebhookSecret reports whether a webhook request carries the secret token, so
+// only Telegram can deliver updates.
+func validWebhookSecret(r *http.Request, secret string) bool {
+	got := r.Header.Get(webhookSecretHeader)
+	return secret != "" && subtle.ConstantTimeCompare([]byte(got), []byte(secret)) == 1
+}
+
+// callbackSigner signs the callback data of the bot's buttons with the chat they were
+// sent to, so a callback with crafted data, or data copied from another chat, is rejected.
+type callbackSigner struct {
+	key []byte
+}
+
+// newCallbackSigner derives the signing key from the bot token, so buttons keep working
+// across restarts without another secret to configure.
+func newCallbackSigner(botToken string) callbackSigner {
+	mac := hmac.New(sha256.New, []byte(botToken))
+	mac.Write([]byte("callback-data"))
+	return callbackSigner{key: mac.Sum(nil)}
+}
+
+func (s callbackSigner) signature(chatID int64, data string) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%d|%s", chatID, data)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:callbackSignatureLen]
+}
+
+// Sign appends the signature for chatID to the callback data: "action|args|signature".
+func (s callbackSigner) Sign(chatID int64, data string) string {
+	return data + "|" + s.signature(chatID, data)
+}
+
+// Verify strips the signature from callback data received from chatID, reporting false
+// when it is missing or does not match.
+func (s callbackSigner) Verify(chatID int64, signed string) (string, bool) {
+	i := strings.LastIndex(signed, "|")
+	if i < 0 {
+		return "", false
+	}
+	data, sig := signed[:i], signed[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.signature(chatID, data))) {
+		return "", false
+	}
+	return data, true
+}
+
+// signMarkup returns the outgoing message or edit with its inline keyboard signed for
+// the chat it goes to. Other requests are returned unchanged.
+func (s callbackSigner) signMarkup(c tgbotapi.Chattable) tgbotapi.Chattable {
+	switch m := c.(type) {
+	case tgbotapi.MessageConfig:
+		switch markup := m.ReplyMarkup.(type) {
+		case tgbotapi.InlineKeyboardMarkup:
+			m.ReplyMarkup = s.signKeyboard(m.ChatID, &markup)
+		case *tgbotapi.InlineKeyboardMarkup:
+			m.ReplyMarkup = s.signKeyboard(m.ChatID, markup)
+		}
+		return m
+	case tgbotapi.EditMessageTextConfig:
+		m.ReplyMarkup = s.signKeyboard(m.ChatID, m.ReplyMarkup)
+		return m
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		m.ReplyMarkup = s.signKeyboard(m.ChatID, m.ReplyMarkup)
+		return m
+	}
+	return c
+}
+
+// signKeyboard signs a copy of the keyboard, leaving the caller's buttons untouched.
+func (s callbackSigner) signKeyboard(chatID int64, keyboard *tgbotapi.InlineKeyboardMarkup) *tgbotapi.InlineKeyboardMarkup {
+	if keyboard == nil {
+		return nil
+	}
+	signed := tgbotapi.InlineKeyboardMarkup{InlineKeyboard: make([][]tgbotapi.InlineKeyboardButton, len(keyboard.InlineKeyboard))}
+	for i, row := range keyboard.InlineKeyboard {
+		signed.InlineKeyboard[i] = slices.Clone(row)
+		for j, button := range row {
+			if button.CallbackData != nil {
+				data := s.Sign(chatID, fitCallbackData(*button.CallbackData))
+				signed.InlineKeyboard[i][j].CallbackData = &data
+			}
+		}
+	}
+	return &signed
+}
+
+// fitCallbackData cuts data to maxCallbackData bytes without splitting a character.
+// Buttons that carry free text, like the user's request, lose its end.
+func fitCallbackData(data string) string {
+	if len(data) <= maxCallbackData {
+		return data
+	}
+	cut := maxCallbackData
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return data[:cut]
+}
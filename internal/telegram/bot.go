@@ -3,10 +3,12 @@ package telegram
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"ai-meal-planner/internal/app"
@@ -17,7 +19,9 @@ import (
 	"ai-meal-planner/internal/llm"
 	"ai-meal-planner/internal/metrics"
 	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/rating"
 	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/reminder"
 	"ai-meal-planner/internal/shopping"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -28,9 +32,10 @@ var adjustmentPrompt string
 
 // Bot wraps the Telegram API, Meal Planner, and Clipper.
 type Bot struct {
-	api          *tgbotapi.BotAPI
+	api          Messenger
 	planner      *planner.Planner
 	clipper      *clipper.Clipper
+	ghostClient  ghost.Client
 	metricsStore *metrics.Store
 	textGen      llm.TextGenerator
 	embedGen     llm.EmbeddingGenerator
@@ -43,8 +48,22 @@ type Bot struct {
 	shoppingRepo *shopping.Repository
 	sessionRepo  *SessionRepository
 	auditRepo    *audit.AuditRepository
+	ratingRepo   *rating.Repository
+	reminderRepo *reminder.Repository
 	extractor    *recipe.Extractor // Added extractor
 	tagger       *recipe.Tagger
+	duplicates   *recipe.DuplicateDetector
+	intents      *IntentClassifier
+	// signer checks the callback data of the buttons api signed
+	signer callbackSigner
+	// webhookSecret is the token Telegram sends with every webhook request
+	webhookSecret string
+	// inflight tracks messages and clipped posts still being handled in the background
+	inflight sync.WaitGroup
+
+	// answeredCallbacks holds the button presses already answered while they are handled
+	callbackMu        sync.Mutex
+	answeredCallbacks map[string]bool
 }
 
 // NewBot initializes the Telegram Bot and sets the Webhook.
@@ -52,9 +71,11 @@ func NewBot(
 	cfg *config.Config,
 	planner *planner.Planner,
 	clipper *clipper.Clipper,
+	ghostClient ghost.Client,
 	metricsStore *metrics.Store,
 	textGen llm.TextGenerator,
 	tagGen llm.TextGenerator,
+	intentGen llm.TextGenerator,
 	embedGen llm.EmbeddingGenerator,
 	planRepo *planner.PlanRepository, // New parameter
 	recipeRepo *recipe.Repository, // New parameter
@@ -62,48 +83,102 @@ func NewBot(
 	shoppingRepo *shopping.Repository, // New parameter
 	sessionRepo *SessionRepository, // New parameter
 	auditRepo *audit.AuditRepository, // New parameter
+	taxonomy *recipe.Taxonomy,
+	ratingRepo *rating.Repository,
+	reminderRepo *reminder.Repository,
 ) (*Bot, error) {
-	bot, err := tgbotapi.NewBotAPI(cfg.TelegramBotToken)
+	endpoint := cfg.TelegramAPIEndpoint
+	if endpoint == "" {
+		endpoint = tgbotapi.APIEndpoint
+	}
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.TelegramBotToken, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init telegram api: %w", err)
 	}
 
 	log.Printf("Authorized on account %s", bot.Self.UserName)
 
-	webhookURL := cfg.TelegramWebhookURL
-	wh, _ := tgbotapi.NewWebhook(webhookURL)
-	resp, err := bot.Request(wh)
-	if err != nil {
-		return nil, fmt.Errorf("failed to set webhook to %s: %w", webhookURL, err)
+	webhookSecret := webhookSecretFor(cfg)
+	if cfg.TelegramMode == config.TelegramModePolling {
+		// Telegram refuses getUpdates while a webhook is set
+		if _, err := bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+			return nil, fmt.Errorf("failed to remove webhook for long polling: %w", err)
+		}
+		log.Printf("Receiving updates by long polling")
+	} else {
+		// The Bot API library predates secret tokens, so setWebhook is called directly
+		webhookURL := cfg.TelegramWebhookURL
+		resp, err := bot.MakeRequest("setWebhook", tgbotapi.Params{"url": webhookURL, "secret_token": webhookSecret})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set webhook to %s: %w", webhookURL, err)
+		}
+		log.Printf("Webhook set response: %s", resp.Description)
 	}
-	log.Printf("Webhook set response: %s", resp.Description)
 
+	return newBot(bot, cfg, planner, clipper, ghostClient, metricsStore, textGen, tagGen, intentGen, embedGen,
+		planRepo, recipeRepo, vectorRepo, shoppingRepo, sessionRepo, auditRepo, taxonomy, ratingRepo, reminderRepo), nil
+}
+
+// newBot builds the bot around a Messenger that is already connected, signing the
+// keyboards it sends.
+func newBot(
+	api Messenger,
+	cfg *config.Config,
+	planner *planner.Planner,
+	clipper *clipper.Clipper,
+	ghostClient ghost.Client,
+	metricsStore *metrics.Store,
+	textGen llm.TextGenerator,
+	tagGen llm.TextGenerator,
+	intentGen llm.TextGenerator,
+	embedGen llm.EmbeddingGenerator,
+	planRepo *planner.PlanRepository,
+	recipeRepo *recipe.Repository,
+	vectorRepo *llm.VectorRepository,
+	shoppingRepo *shopping.Repository,
+	sessionRepo *SessionRepository,
+	auditRepo *audit.AuditRepository,
+	taxonomy *recipe.Taxonomy,
+	ratingRepo *rating.Repository,
+	reminderRepo *reminder.Repository,
+) *Bot {
 	extractor := recipe.NewExtractor(textGen, embedGen, vectorRepo)
-	tagger := recipe.NewTagger(tagGen)
+	tagger := recipe.NewTagger(tagGen, taxonomy)
+	duplicates := recipe.NewDuplicateDetector(recipeRepo, vectorRepo, embedGen)
+	signer := newCallbackSigner(cfg.TelegramBotToken)
 
 	return &Bot{
-		api:          bot,
-		planner:      planner,
-		clipper:      clipper,
-		metricsStore: metricsStore,
-		textGen:      textGen,
-		embedGen:     embedGen,
-		cfg:          cfg,
-		planRepo:     planRepo,
-		recipeRepo:   recipeRepo,
-		vectorRepo:   vectorRepo,
-		shoppingRepo: shoppingRepo,
-		sessionRepo:  sessionRepo,
-		auditRepo:    auditRepo,
-		extractor:    extractor,
-		tagger:       tagger,
-	}, nil
-}
-
-// RegisterHandlers registers the webhook handler with the default HTTP mux.
+		api:           signingMessenger{Messenger: api, signer: signer},
+		signer:        signer,
+		webhookSecret: webhookSecretFor(cfg),
+		planner:       planner,
+		clipper:       clipper,
+		ghostClient:   ghostClient,
+		metricsStore:  metricsStore,
+		textGen:       textGen,
+		embedGen:      embedGen,
+		cfg:           cfg,
+		planRepo:      planRepo,
+		recipeRepo:    recipeRepo,
+		vectorRepo:    vectorRepo,
+		shoppingRepo:  shoppingRepo,
+		sessionRepo:   sessionRepo,
+		auditRepo:     auditRepo,
+		ratingRepo:    ratingRepo,
+		reminderRepo:  reminderRepo,
+		extractor:     extractor,
+		tagger:        tagger,
+		duplicates:    duplicates,
+		intents:       NewIntentClassifier(intentGen),
+	}
+}
 
+// RegisterHandlers registers the health check and, in webhook mode, the webhook handler
+// with the default HTTP mux.
 func (b *Bot) RegisterHandlers() {
-	http.HandleFunc("/webhook", b.handleWebhook)
+	if b.cfg.TelegramMode != config.TelegramModePolling {
+		http.HandleFunc("/webhook", b.handleWebhook)
+	}
 	http.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
@@ -111,11 +186,59 @@ func (b *Bot) RegisterHandlers() {
 }
 
 func (b *Bot) handleWebhook(w http.ResponseWriter, r *http.Request) {
-	update, err := b.api.HandleUpdate(r)
+	if !validWebhookSecret(r, b.webhookSecret) {
+		log.Printf("⚠️ Rejected webhook request without a valid secret token from %s", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	update, err := parseUpdate(r)
 	if err != nil {
 		log.Printf("Error parsing update: %v", err)
 		return
 	}
+	b.handleUpdate(update)
+}
+
+// parseUpdate decodes the update in a webhook request.
+func parseUpdate(r *http.Request) (*tgbotapi.Update, error) {
+	if r.Method != http.MethodPost {
+		return nil, fmt.Errorf("webhook requires POST, got %s", r.Method)
+	}
+	var update tgbotapi.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// pollingTimeout is how many seconds each long poll waits for updates.
+const pollingTimeout = 60
+
+// StartPolling receives updates by long polling until ctx is cancelled, for running the
+// bot without a public webhook URL.
+func (b *Bot) StartPolling(ctx context.Context) {
+	updates := b.api.GetUpdatesChan(tgbotapi.UpdateConfig{Timeout: pollingTimeout})
+	for {
+		select {
+		case <-ctx.Done():
+			b.api.StopReceivingUpdates()
+			return
+		case update := <-updates:
+			// A slow callback must not hold up the next poll, so every update is handled in the background
+			b.spawn(func() { b.handleUpdate(&update) })
+		}
+	}
+}
+
+// handleUpdate authorizes an update from the webhook or long polling and dispatches it.
+func (b *Bot) handleUpdate(update *tgbotapi.Update) {
+	if from, ok := b.authorize(update); !ok {
+		if from != nil {
+			log.Printf("⚠️ Unauthorized access attempt from UserID: %d (@%s)", from.ID, from.UserName)
+		}
+		return
+	}
 
 	if update.CallbackQuery != nil {
 		b.handleCallbackQuery(update.CallbackQuery)
@@ -126,33 +249,52 @@ func (b *Bot) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	isAllowed := false
-	for _, id := range b.cfg.TelegramAllowedUserIDs {
-		if update.Message.From.ID == id {
-			isAllowed = true
-			break
-		}
-	}
+	b.spawn(func() { b.processMessage(update.Message) })
+}
 
-	if !isAllowed {
-		log.Printf("⚠️ Unauthorized access attempt from UserID: %d (@%s)", update.Message.From.ID, update.Message.From.UserName)
-		return
-	}
+// spawn runs work in the background, tracked so Wait can block until it finishes.
+func (b *Bot) spawn(work func()) {
+	b.inflight.Add(1)
+	go func() {
+		defer b.inflight.Done()
+		work()
+	}()
+}
 
-	go b.processMessage(update.Message)
+// Wait blocks until the messages and clipped posts being handled in the background are
+// done, so shutdown does not cut them off.
+func (b *Bot) Wait() {
+	b.inflight.Wait()
 }
 
 func (b *Bot) processMessage(msg *tgbotapi.Message) {
 	ctx := context.Background()
 	userID := fmt.Sprintf("%d", msg.From.ID)
 
-	// 0. Check for active session (e.g., awaiting adjustment feedback)
-	session, err := b.sessionRepo.GetActive(ctx, userID, time.Now())
+	// Photos of recipes (compressed or sent as files) go straight to the image clipper
+	if len(msg.Photo) > 0 || isImageDocument(msg.Document) {
+		b.handleImageClipRequest(msg)
+		return
+	}
+
+	// 0. Check for a session waiting for a text reply (e.g., adjustment feedback)
+	session, err := b.sessionRepo.GetActive(ctx, userID, time.Now(), SessionTypeAdjustPlan, SessionTypeEditClip, SessionTypeRateRecipe)
 	if err != nil {
 		log.Printf("Error checking session: %v", err)
 	}
-	if session != nil && session.SessionType == "adjust_plan" && session.State == "awaiting_feedback" {
-		b.handleAdjustmentFeedback(ctx, msg, session)
+	// Commands still work while adjusting; everything else is more feedback
+	if session != nil && session.SessionType == SessionTypeAdjustPlan && session.State == StateAwaitingFeedback && !strings.HasPrefix(msg.Text, "/") {
+		if b.handleAdjustmentFeedback(ctx, msg, session) {
+			return
+		}
+		session = nil
+	}
+	if session != nil && session.SessionType == SessionTypeEditClip {
+		b.handleClipEditReply(ctx, msg, session)
+		return
+	}
+	if session != nil && session.SessionType == SessionTypeRateRecipe {
+		b.handleRatingComment(ctx, msg, session)
 		return
 	}
 
@@ -161,15 +303,21 @@ func (b *Bot) processMessage(msg *tgbotapi.Message) {
 		b.handleMetricsRequest(msg)
 		return
 	}
-
-	// 2. Detect if it's a URL (Clipper mode) or a request (Planner mode)
-	if strings.HasPrefix(msg.Text, "http://") || strings.HasPrefix(msg.Text, "https://") {
-		b.handleClipperRequest(msg)
+	if msg.Text == repeatCommand || strings.HasPrefix(msg.Text, repeatCommand+" ") {
+		b.handleRepeatCommand(ctx, msg)
+		return
+	}
+	if msg.Text == historyCommand {
+		b.handleHistoryCommand(ctx, msg)
+		return
+	}
+	if msg.Text == remindersCommand || strings.HasPrefix(msg.Text, remindersCommand+" ") {
+		b.handleRemindersCommand(ctx, msg)
 		return
 	}
 
-	// 3. Default to Planner mode
-	b.handlePlannerRequest(msg)
+	// 2. Route links, commands and free text to the matching handler
+	b.routeMessage(ctx, msg)
 }
 
 func (b *Bot) handleMetricsRequest(msg *tgbotapi.Message) {
@@ -181,7 +329,7 @@ func (b *Bot) handleMetricsRequest(msg *tgbotapi.Message) {
 }
 
 func (b *Bot) handleClipperRequest(msg *tgbotapi.Message) {
-	statusText := "✂️ *Clipping recipe...* \n(Extracting and saving to your blog)"
+	statusText := "✂️ *Clipping recipe...* \n(Extracting it for review)"
 	replyMsg := tgbotapi.NewMessage(msg.Chat.ID, statusText)
 	replyMsg.ParseMode = "Markdown"
 	sentMsg, err := b.api.Send(replyMsg)
@@ -191,11 +339,31 @@ func (b *Bot) handleClipperRequest(msg *tgbotapi.Message) {
 	}
 
 	ctx := context.Background()
+	userID := fmt.Sprintf("%d", msg.From.ID)
 
 	// Parse URL and optional tags
 	// Format: http://url tag: t1, t2
-	parts := strings.Split(msg.Text, " ")
-	url := parts[0]
+	url := strings.Split(msg.Text, " ")[0]
+	manualTags := parseManualTags(msg.Text)
+
+	// --- Clipper Flow ---
+	extracted, err := b.clipper.ExtractURL(ctx, url)
+	if err != nil {
+		log.Printf("Error clipping recipe: %v", err)
+		safeErr := strings.ReplaceAll(err.Error(), "`", "'")
+		finalText := fmt.Sprintf("❌ *Error clipping recipe:*\n```\n%v\n```", safeErr)
+		edit := tgbotapi.NewEditMessageText(msg.Chat.ID, sentMsg.MessageID, finalText)
+		edit.ParseMode = "Markdown"
+		b.api.Send(edit)
+		return
+	}
+
+	b.sendClipPreview(ctx, userID, msg.Chat.ID, sentMsg.MessageID, extracted, url, manualTags)
+}
+
+// parseManualTags reads the optional "tag: t1, t2" suffix of a clip message or photo caption.
+func parseManualTags(text string) []string {
+	parts := strings.Split(text, " ")
 	var manualTags []string
 
 	for i, p := range parts {
@@ -214,30 +382,7 @@ func (b *Bot) handleClipperRequest(msg *tgbotapi.Message) {
 			break
 		}
 	}
-
-	// --- Clipper Flow ---
-	post, err := b.clipper.ClipURL(ctx, url, manualTags)
-	var finalText string
-	if err != nil {
-		log.Printf("Error clipping recipe: %v", err)
-		safeErr := strings.ReplaceAll(err.Error(), "`", "'")
-		finalText = fmt.Sprintf("❌ *Error clipping recipe:*\n```\n%v\n```", safeErr)
-	} else {
-		tagDisplay := ""
-		if len(post.Tags) > 0 {
-			var tagNames []string
-			for _, t := range post.Tags {
-				tagNames = append(tagNames, t.Name)
-			}
-			tagDisplay = "\n*Tags:* " + strings.Join(tagNames, ", ")
-		}
-		finalText = fmt.Sprintf("✅ *Recipe Saved!*\n\n*Title:* %s\n*URL:* %s/%s%s", post.Title, b.cfg.GhostURL, post.ID, tagDisplay)
-		// Trigger background ingestion so it becomes searchable for future plans
-		go b.ingestClippedPost(*post)
-	}
-	edit := tgbotapi.NewEditMessageText(msg.Chat.ID, sentMsg.MessageID, finalText)
-	edit.ParseMode = "Markdown"
-	b.api.Send(edit)
+	return manualTags
 }
 
 func (b *Bot) handlePlannerRequest(msg *tgbotapi.Message) {
@@ -264,16 +409,11 @@ func (b *Bot) handlePlannerRequest(msg *tgbotapi.Message) {
 		// Ask user what to do
 		promptText := fmt.Sprintf("🗓️ A plan already exists for next week (starting *%s*).\nWhat would you like to do?", nextMonday.Format("2006-01-02"))
 
-		// We need to keep the user request. Callback data is limited to 64 bytes.
-		shortReq := msg.Text
-		if len(shortReq) > 32 {
-			shortReq = shortReq[:32]
-		}
-
+		// We need to keep the user request. Signing cuts it to fit the 64 bytes of callback data.
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🔄 Redo Next Week", "redo|"+shortReq),
-				tgbotapi.NewInlineKeyboardButtonData("⏭️ Plan Following Week", "next|"+shortReq),
+				tgbotapi.NewInlineKeyboardButtonData("🔄 Redo Next Week", "redo|"+msg.Text),
+				tgbotapi.NewInlineKeyboardButtonData("⏭️ Plan Following Week", "next|"+msg.Text),
 			),
 		)
 
@@ -290,7 +430,20 @@ func (b *Bot) handlePlannerRequest(msg *tgbotapi.Message) {
 func (b *Bot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 	ctx := context.Background()
 	userID := fmt.Sprintf("%d", query.From.ID)
-	data := query.Data
+
+	// Remove the spinner if no handler answered with a toast
+	defer b.finishCallback(query)
+
+	// Only buttons the bot made for this chat are acted on
+	if query.Message == nil {
+		return
+	}
+	data, ok := b.signer.Verify(query.Message.Chat.ID, query.Data)
+	if !ok {
+		log.Printf("⚠️ Rejected callback with an invalid signature from UserID: %d", query.From.ID)
+		b.answerCallback(query, "This button is no longer valid. Please ask again.")
+		return
+	}
 
 	parts := strings.Split(data, "|")
 	if len(parts) < 2 {
@@ -299,16 +452,77 @@ func (b *Bot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 
 	action := parts[0]
 
-	// Answer callback to remove spinner
-	b.api.Request(tgbotapi.NewCallback(query.ID, ""))
+	// Buttons that wait on an agent or Ghost stop the spinner before the work starts
+	switch action {
+	case "intent", "startover", "clipunpub", "clipdelok", "clippub", "clipdraft", "redo", "next":
+		b.answerCallback(query, "")
+	}
 
 	switch action {
 	case "confirm":
 		b.handleConfirmDraft(ctx, query, userID, parts)
 	case "adjust":
 		b.handleAdjustDraft(ctx, query, userID, parts)
+	case "adjustdone":
+		b.handleAdjustDone(ctx, query, userID, parts)
+	case "adjustcancel":
+		b.handleAdjustCancel(ctx, query, userID, parts)
+	case "intent":
+		b.handleIntentConfirm(ctx, query, userID, parts)
 	case "startover":
 		b.handleStartOver(ctx, query, userID, parts)
+	case "cliptitle", "cliptags":
+		b.handleClipEditPrompt(ctx, query, userID, action, parts[1])
+	case "clipunpub":
+		b.handleClipUnpublish(ctx, query, parts[1])
+	case "clipshow":
+		b.handleClipShow(query, parts[1])
+	case "clipdel":
+		b.handleClipDeletePrompt(query, parts)
+	case "clipdelok":
+		b.handleClipDelete(ctx, query, parts[1])
+	case "clipkeep":
+		b.handleClipKeep(query, parts)
+	case "clippub", "clipdraft":
+		b.handleClipPublish(ctx, query, userID, action == "clippub", parts)
+	case "clipcancel":
+		b.handleClipCancel(ctx, query, userID, parts)
+	case "cook":
+		b.handleStartCooking(ctx, query, userID, parts)
+	case "cooknext", "cookprev":
+		delta := 1
+		if action == "cookprev" {
+			delta = -1
+		}
+		b.handleCookingNav(ctx, query, userID, parts, delta)
+	case "cooktimer":
+		b.handleCookingTimer(ctx, query, userID, parts)
+	case "cookdone":
+		b.handleCookingDone(ctx, query, userID, parts)
+	case "rate":
+		b.handleRate(ctx, query, userID, parts)
+	case "ratenever":
+		b.handleRateNever(ctx, query, userID, parts)
+	case "ratecomment":
+		b.handleRateCommentPrompt(ctx, query, userID, parts)
+	case "swap":
+		b.handleSwapOptions(ctx, query, userID, parts)
+	case "swapto":
+		b.handleSwapChoose(ctx, query, userID, parts)
+	case "lock":
+		b.handleToggleLock(ctx, query, userID, parts)
+	case "swapback":
+		b.handleSwapBack(ctx, query, userID, parts)
+	case "search":
+		b.handleSearchPage(ctx, query, userID, parts)
+	case "searchadd":
+		b.handleSearchAdd(ctx, query, userID, parts)
+	case "searchto":
+		b.handleSearchChoose(ctx, query, userID, parts)
+	case "undo":
+		b.handleUndo(ctx, query, userID, parts)
+	case "restore":
+		b.handleHistoryRestore(ctx, query, userID, parts)
 	case "redo", "next":
 		// Legacy handlers for existing week conflict resolution
 		request := parts[1]
@@ -325,6 +539,36 @@ func (b *Bot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 	}
 }
 
+// answerCallback answers a button press, showing text as a toast when it is not empty.
+// Telegram accepts one answer per press, so any later answer is dropped.
+func (b *Bot) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	b.callbackMu.Lock()
+	if b.answeredCallbacks == nil {
+		b.answeredCallbacks = make(map[string]bool)
+	}
+	answered := b.answeredCallbacks[query.ID]
+	b.answeredCallbacks[query.ID] = true
+	b.callbackMu.Unlock()
+
+	if answered {
+		log.Printf("⚠️ Callback %s was already answered, dropping %q", query.ID, text)
+		return
+	}
+	b.api.Request(tgbotapi.NewCallback(query.ID, text))
+}
+
+// finishCallback answers a button press no handler answered and forgets it.
+func (b *Bot) finishCallback(query *tgbotapi.CallbackQuery) {
+	b.callbackMu.Lock()
+	answered := b.answeredCallbacks[query.ID]
+	delete(b.answeredCallbacks, query.ID)
+	b.callbackMu.Unlock()
+
+	if !answered {
+		b.api.Request(tgbotapi.NewCallback(query.ID, ""))
+	}
+}
+
 func (b *Bot) generateAndSendPlan(ctx context.Context, userID string, chatID int64, messageID int, request string, targetWeek time.Time) {
 	// Simple heuristic to extract context from natural language
 	pCtx := planner.PlanningContext{
@@ -362,7 +606,7 @@ func (b *Bot) generateAndSendPlan(ctx context.Context, userID string, chatID int
 		return
 	}
 
-	b.saveAndSendDraftPlan(ctx, chatID, messageID, userID, plan)
+	b.saveAndSendDraftPlan(ctx, chatID, messageID, userID, plan, planner.VersionAuthorAnalyst, request)
 }
 
 func formatPlanMarkdownParts(plan *planner.MealPlan) (string, string) {
@@ -385,13 +629,16 @@ func formatPlanMarkdownParts(plan *planner.MealPlan) (string, string) {
 		pb.WriteString("\n")
 	}
 
+	return pb.String(), formatShoppingList(plan.ShoppingList)
+}
+
+func formatShoppingList(items []string) string {
 	var sb strings.Builder
 	sb.WriteString("🛒 *Shopping List*\n\n")
-	for _, item := range plan.ShoppingList {
+	for _, item := range items {
 		sb.WriteString(fmt.Sprintf("• %s\n", item))
 	}
-
-	return pb.String(), sb.String()
+	return sb.String()
 }
 
 // ingestClippedPost performs normalization and storage in the background.
@@ -458,7 +705,11 @@ func (b *Bot) sendAdminAlert(text string) {
 // formatDraftPlanMarkdown formats a draft plan in a concise, grouped format.
 func formatDraftPlanMarkdown(plan *planner.MealPlan) string {
 	var sb strings.Builder
-	sb.WriteString("📋 *DRAFT Meal Plan*\n\n")
+	sb.WriteString("📋 *DRAFT Meal Plan*")
+	if plan.Version > 1 {
+		sb.WriteString(fmt.Sprintf(" (v%d)", plan.Version))
+	}
+	sb.WriteString("\n\n")
 
 	// Helper to normalize titles for comparison
 	normalize := func(s string) string {
@@ -482,7 +733,7 @@ func formatDraftPlanMarkdown(plan *planner.MealPlan) string {
 				// Grouped format: Monday/Tuesday: Recipe Title (Prep Time)
 				// We use the prep time from the first day (the "Cook" day)
 				dayRange := fmt.Sprintf("%s/%s", currentDay.Day, nextDay.Day)
-				sb.WriteString(fmt.Sprintf("*%s*: %s", dayRange, currentTitle))
+				sb.WriteString(fmt.Sprintf("*%s*%s: %s", dayRange, lockMark(currentDay), currentTitle))
 				if currentDay.PrepTime != "" {
 					sb.WriteString(fmt.Sprintf(" (%s)", currentDay.PrepTime))
 				}
@@ -497,7 +748,7 @@ func formatDraftPlanMarkdown(plan *planner.MealPlan) string {
 
 		if !isGrouped {
 			// Single day format: Friday: Recipe Title (Prep Time)
-			sb.WriteString(fmt.Sprintf("*%s*: %s", currentDay.Day, currentTitle))
+			sb.WriteString(fmt.Sprintf("*%s*%s: %s", currentDay.Day, lockMark(currentDay), currentTitle))
 			if currentDay.PrepTime != "" {
 				sb.WriteString(fmt.Sprintf(" (%s)", currentDay.PrepTime))
 			}
@@ -525,7 +776,7 @@ func (b *Bot) handleConfirmDraft(ctx context.Context, query *tgbotapi.CallbackQu
 	fmt.Sscanf(parts[1], "%d", &planID)
 
 	// Get the draft plan from database
-	plan, err := b.planRepo.GetByID(ctx, planID)
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
 	if err != nil || plan == nil {
 		log.Printf("Error retrieving plan: %v", err)
 		edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not retrieve plan.")
@@ -533,6 +784,12 @@ func (b *Bot) handleConfirmDraft(ctx context.Context, query *tgbotapi.CallbackQu
 		b.api.Send(edit)
 		return
 	}
+	if plan.Status != planner.StatusDraft {
+		b.answerCallback(query, "This plan is no longer a draft")
+		return
+	}
+	// Building the shopping list takes a while
+	b.answerCallback(query, "")
 
 	// Generate shopping list for the confirmed plan
 	pCtx := planner.PlanningContext{
@@ -552,11 +809,18 @@ func (b *Bot) handleConfirmDraft(ctx context.Context, query *tgbotapi.CallbackQu
 	}
 
 	// Update plan's shopping list
+	plan.ID = planID
 	plan.ShoppingList = shoppingListItems
 
-	// Update status to FINAL
-	if err := b.planRepo.UpdateStatus(ctx, planID, planner.StatusFinal); err != nil {
+	// Update status to FINAL, retiring the plan it replaces for the week
+	if err := b.planRepo.Confirm(ctx, userID, planID); err != nil {
 		log.Printf("Error updating plan status: %v", err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not confirm the plan.")
+		return
+	}
+	// A confirmed plan takes no more feedback
+	if err := b.sessionRepo.DeleteByType(ctx, userID, SessionTypeAdjustPlan); err != nil {
+		log.Printf("Error ending adjustment sessions for user %s: %v", userID, err)
 	}
 
 	// Save shopping list
@@ -574,9 +838,10 @@ func (b *Bot) handleConfirmDraft(ctx context.Context, query *tgbotapi.CallbackQu
 	// Format and send finalized plan
 	planText, shoppingListText := formatPlanMarkdownParts(plan)
 
-	// Edit message to show finalized plan (remove buttons)
+	// Edit message to show finalized plan, swapping the draft buttons for cooking mode
 	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "✅ *Plan Confirmed!*\n\n"+planText)
 	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = planCookingKeyboard(plan)
 	b.api.Send(edit)
 
 	// Send shopping list as second message
@@ -593,7 +858,7 @@ func (b *Bot) handleAdjustDraft(ctx context.Context, query *tgbotapi.CallbackQue
 	fmt.Sscanf(parts[1], "%d", &planID)
 
 	// Get the plan to extract original request
-	plan, err := b.planRepo.GetByID(ctx, planID)
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
 	if err != nil || plan == nil {
 		log.Printf("Error retrieving plan for adjustment: %v", err)
 		edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not retrieve plan.")
@@ -602,20 +867,7 @@ func (b *Bot) handleAdjustDraft(ctx context.Context, query *tgbotapi.CallbackQue
 		return
 	}
 
-	// Create a session to track that we're awaiting adjustment feedback
-	sessionCtx := SessionContextData{
-		PlanID:          planID,
-		OriginalRequest: plan.OriginalRequest,
-	}
-
-	sessionID, err := b.sessionRepo.Create(
-		ctx,
-		userID,
-		"adjust_plan",
-		"awaiting_feedback",
-		sessionCtx,
-		900, // 15 minute TTL for feedback
-	)
+	sessionID, err := b.openAdjustSession(ctx, userID, plan)
 	if err != nil {
 		log.Printf("Error creating session: %v", err)
 		edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not start adjustment mode.")
@@ -633,9 +885,10 @@ func (b *Bot) handleAdjustDraft(ctx context.Context, query *tgbotapi.CallbackQue
 	})
 	b.api.Send(editMarkup)
 
-	// 2. Send the prompt as a NEW message
+	// 2. Send the prompt as a NEW message, with a way out of adjustment mode
 	promptMsg := tgbotapi.NewMessage(query.Message.Chat.ID, adjustmentPrompt)
 	promptMsg.ParseMode = "Markdown"
+	promptMsg.ReplyMarkup = adjustSessionKeyboard(sessionID)
 	b.api.Send(promptMsg)
 }
 
@@ -649,7 +902,7 @@ func (b *Bot) handleStartOver(ctx context.Context, query *tgbotapi.CallbackQuery
 	}
 
 	// Get the plan to find its week and original request
-	plan, err := b.planRepo.GetByID(ctx, planID)
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
 	if err != nil || plan == nil {
 		log.Printf("Error retrieving plan for start over: %v", err)
 		edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not retrieve plan.")
@@ -658,6 +911,11 @@ func (b *Bot) handleStartOver(ctx context.Context, query *tgbotapi.CallbackQuery
 		return
 	}
 
+	// The draft being adjusted is about to be replaced
+	if err := b.sessionRepo.DeleteByType(ctx, userID, SessionTypeAdjustPlan); err != nil {
+		log.Printf("Error ending adjustment sessions for user %s: %v", userID, err)
+	}
+
 	targetWeek := plan.WeekStart
 	request := plan.OriginalRequest
 
@@ -675,48 +933,56 @@ func (b *Bot) handleStartOver(ctx context.Context, query *tgbotapi.CallbackQuery
 	b.generateAndSendPlan(ctx, userID, query.Message.Chat.ID, query.Message.MessageID, request, targetWeek)
 }
 
-// handleAdjustmentFeedback processes user feedback to revise a meal plan
-func (b *Bot) handleAdjustmentFeedback(ctx context.Context, msg *tgbotapi.Message, session *Session) {
-	defer func() {
-		// Clean up session at the end
-		if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
-			log.Printf("Error cleaning up session %d: %v", session.ID, err)
-		}
-	}()
-
+// handleAdjustmentFeedback revises a meal plan with one more message of feedback. The
+// session stays open, remembering the feedback so far, until Done, Cancel or its TTL.
+// It reports false, ending the session, when the plan is no longer a draft, so the
+// message is handled like any other.
+func (b *Bot) handleAdjustmentFeedback(ctx context.Context, msg *tgbotapi.Message, session *Session) bool {
 	userID := fmt.Sprintf("%d", msg.From.ID)
 	adjustmentFeedback := msg.Text
 
-	// Show "thinking" message
-	statusText := "✏️ *Revising plan...* \n(Analyzing your feedback)"
-	replyMsg := tgbotapi.NewMessage(msg.Chat.ID, statusText)
-	replyMsg.ParseMode = "Markdown"
-	sentMsg, err := b.api.Send(replyMsg)
-	if err != nil {
-		log.Printf("Failed to send initial reply: %v", err)
-		return
-	}
-
 	// Parse session context to get planID
 	contextData, err := session.GetContextData()
 	if err != nil {
 		log.Printf("Error parsing session context: %v", err)
-		edit := tgbotapi.NewEditMessageText(msg.Chat.ID, sentMsg.MessageID, "❌ *Error:* Invalid session data.")
-		edit.ParseMode = "Markdown"
-		b.api.Send(edit)
-		return
+		reply := tgbotapi.NewMessage(msg.Chat.ID, "❌ *Error:* Invalid session data.")
+		reply.ParseMode = "Markdown"
+		b.api.Send(reply)
+		return true
 	}
 
 	planID := contextData.PlanID
 
 	// Retrieve the current plan
-	currentPlan, err := b.planRepo.GetByID(ctx, planID)
+	currentPlan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
 	if err != nil || currentPlan == nil {
 		log.Printf("Error retrieving plan for adjustment: %v", err)
-		edit := tgbotapi.NewEditMessageText(msg.Chat.ID, sentMsg.MessageID, "❌ *Error:* Could not retrieve plan.")
-		edit.ParseMode = "Markdown"
-		b.api.Send(edit)
-		return
+		reply := tgbotapi.NewMessage(msg.Chat.ID, "❌ *Error:* Could not retrieve plan.")
+		reply.ParseMode = "Markdown"
+		b.api.Send(reply)
+		return true
+	}
+	// Confirmed or replaced since the conversation started
+	if currentPlan.Status != planner.StatusDraft {
+		if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+			log.Printf("Error cleaning up session %d: %v", session.ID, err)
+		}
+		return false
+	}
+
+	// Keep the conversation open while the user is active
+	if err := b.sessionRepo.Extend(ctx, session.ID, adjustSessionTTL); err != nil {
+		log.Printf("Error extending session %d: %v", session.ID, err)
+	}
+
+	// Show "thinking" message
+	statusText := "✏️ *Revising plan...* \n(Analyzing your feedback)"
+	replyMsg := tgbotapi.NewMessage(msg.Chat.ID, statusText)
+	replyMsg.ParseMode = "Markdown"
+	sentMsg, err := b.api.Send(replyMsg)
+	if err != nil {
+		log.Printf("Failed to send initial reply: %v", err)
+		return true
 	}
 
 	// Extract user request from the session or original plan data
@@ -736,15 +1002,16 @@ func (b *Bot) handleAdjustmentFeedback(ctx context.Context, msg *tgbotapi.Messag
 		CookingFrequency: b.cfg.DefaultCookingFrequency,
 	}
 
-	reviewerResult, err := b.planner.RevisePlan(ctx, userID, currentPlan, userRequest, adjustmentFeedback, pCtx)
+	reviewerResult, err := b.planner.RevisePlan(ctx, userID, currentPlan, userRequest, adjustmentFeedback, contextData.Feedback, pCtx)
 	if err != nil {
 		log.Printf("Error revising plan: %v", err)
 		safeErr := strings.ReplaceAll(err.Error(), "`", "'")
-		finalText := fmt.Sprintf("❌ *Error revising plan:*\n```\n%v\n```", safeErr)
+		finalText := fmt.Sprintf("❌ *Error revising plan:*\n```\n%v\n```\n_Send your feedback again, or finish below._", safeErr)
 		edit := tgbotapi.NewEditMessageText(msg.Chat.ID, sentMsg.MessageID, finalText)
 		edit.ParseMode = "Markdown"
+		edit.ReplyMarkup = adjustSessionKeyboard(session.ID)
 		b.api.Send(edit)
-		return
+		return true
 	}
 
 	// Record metrics
@@ -756,37 +1023,53 @@ func (b *Bot) handleAdjustmentFeedback(ctx context.Context, msg *tgbotapi.Messag
 		LatencyMS:        reviewerResult.Meta.Latency.Milliseconds(),
 	})
 
-	// Log the interaction for auditing
+	// Check for context bloat
+	if reviewerResult.Meta.Usage.PromptTokens > 8000 {
+		alert := fmt.Sprintf("⚠️ *Context Bloat Alert*\nAgent: PlanReviewer\nModel: %s\nPrompt Tokens: %d", reviewerResult.Meta.Usage.Model, reviewerResult.Meta.Usage.PromptTokens)
+		b.sendAdminAlert(alert)
+	}
+
+	// Save the revised plan and show it with the buttons that end the conversation
+	revised := reviewerResult.RevisedPlan
+	b.saveDraftPlan(ctx, userID, revised, planner.VersionAuthorPlanReviewer, adjustmentFeedback)
+	b.sendAdjustingPlan(msg.Chat.ID, sentMsg.MessageID, session.ID, revised)
+
+	// Remember the feedback so the next message builds on it
+	contextData.Feedback = appendFeedback(contextData.Feedback, adjustmentFeedback)
+	if err := b.sessionRepo.Update(ctx, session.ID, StateAwaitingFeedback, contextData); err != nil {
+		log.Printf("Error updating session %d: %v", session.ID, err)
+	}
+
+	// Log the interaction for auditing, after saving so both states carry their version for undo
 	_ = b.auditRepo.LogInteraction(
 		ctx,
 		userID,
 		planID,
-		"RevisePlan",
+		audit.ActionRevisePlan,
 		userRequest,
 		adjustmentFeedback,
-		currentPlan,                // Previous State
-		reviewerResult.RevisedPlan, // New State
+		currentPlan, // Previous State
+		revised,     // New State
 	)
+	return true
+}
 
-	// Check for context bloat
-	if reviewerResult.Meta.Usage.PromptTokens > 8000 {
-		alert := fmt.Sprintf("⚠️ *Context Bloat Alert*\nAgent: PlanReviewer\nModel: %s\nPrompt Tokens: %d", reviewerResult.Meta.Usage.Model, reviewerResult.Meta.Usage.PromptTokens)
-		b.sendAdminAlert(alert)
-	}
-
-	// Save and send the revised plan
-	b.saveAndSendDraftPlan(ctx, msg.Chat.ID, sentMsg.MessageID, userID, reviewerResult.RevisedPlan)
+// saveAndSendDraftPlan saves the plan as a new draft version of its week's plan and updates the
+// user's message with the plan content and action buttons. author and feedback record what produced it.
+func (b *Bot) saveAndSendDraftPlan(ctx context.Context, chatID int64, messageID int, userID string, plan *planner.MealPlan, author, feedback string) {
+	b.saveDraftPlan(ctx, userID, plan, author, feedback)
+	b.sendDraftPlan(chatID, messageID, plan)
 }
 
-// saveAndSendDraftPlan saves the plan as a draft and updates the user's message with the plan content and action buttons.
-func (b *Bot) saveAndSendDraftPlan(ctx context.Context, chatID int64, messageID int, userID string, plan *planner.MealPlan) {
+// saveDraftPlan saves the plan as a new draft version of its week's plan, setting its ID.
+func (b *Bot) saveDraftPlan(ctx context.Context, userID string, plan *planner.MealPlan, author, feedback string) {
 	// Set plan as DRAFT and clear shopping list (will be generated on confirm)
 	plan.Status = planner.StatusDraft
 	shoppingList := plan.ShoppingList // Save for later
 	plan.ShoppingList = nil           // Clear from draft
 
-	// Save the draft plan to database
-	planID, err := b.planRepo.Save(ctx, userID, plan)
+	// Save the draft as the week's newest plan version
+	planID, err := b.planRepo.SaveVersion(ctx, userID, plan, author, feedback)
 	if err != nil {
 		log.Printf("Warning: failed to save meal plan to user memory for user %s: %v", userID, err)
 	}
@@ -794,14 +1077,26 @@ func (b *Bot) saveAndSendDraftPlan(ctx context.Context, chatID int64, messageID
 	// Restore shopping list (in memory) if needed for immediate display or logic,
 	// though the draft view doesn't usually show it.
 	plan.ShoppingList = shoppingList
+}
 
-	// Use just the PlanID for callback data. We can fetch the request from DB if needed.
-	callbackData := fmt.Sprintf("%d", planID)
-
-	// Format plan text in concise format
+// sendDraftPlan edits the user's message to show a saved draft plan with its action buttons.
+func (b *Bot) sendDraftPlan(chatID int64, messageID int, plan *planner.MealPlan) {
 	planText := formatDraftPlanMarkdown(plan)
+	keyboard := draftPlanKeyboard(plan)
+
+	// Edit message with the draft plan and buttons
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, planText)
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// draftPlanKeyboard offers the draft actions, swap and lock buttons per Cook day, and Undo once
+// the plan has been changed.
+func draftPlanKeyboard(plan *planner.MealPlan) tgbotapi.InlineKeyboardMarkup {
+	// Use just the PlanID for callback data. We can fetch the request from DB if needed.
+	callbackData := fmt.Sprintf("%d", plan.ID)
 
-	// Add feedback buttons
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("✅ Confirm", "confirm|"+callbackData),
@@ -809,10 +1104,12 @@ func (b *Bot) saveAndSendDraftPlan(ctx context.Context, chatID int64, messageID
 			tgbotapi.NewInlineKeyboardButtonData("🔄 Start Over", "startover|"+callbackData),
 		),
 	)
-
-	// Edit message with the draft plan and buttons
-	edit := tgbotapi.NewEditMessageText(chatID, messageID, planText)
-	edit.ParseMode = "Markdown"
-	edit.ReplyMarkup = &keyboard
-	b.api.Send(edit)
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, swapKeyboardRows(plan)...)
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, lockKeyboardRows(plan)...)
+	if plan.Version > 1 {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("↩️ Undo last change", "undo|"+callbackData),
+		))
+	}
+	return keyboard
 }
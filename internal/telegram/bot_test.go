@@ -1,10 +1,28 @@
 package telegram
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"ai-meal-planner/internal/audit"
+	"ai-meal-planner/internal/clipper"
+	"ai-meal-planner/internal/config"
+	"ai-meal-planner/internal/llm/llmtest"
 	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/reminder"
+	"ai-meal-planner/internal/value"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 func TestFormatPlanMarkdownParts(t *testing.T) {
@@ -39,3 +57,733 @@ func TestFormatPlanMarkdownParts(t *testing.T) {
 		t.Error("Missing shopping item")
 	}
 }
+
+func TestParseTagList(t *testing.T) {
+	got := parseTagList(" Quick, Comfort Food ,, Vegetarian")
+	want := []string{"quick", "comfort food", "vegetarian"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("parseTagList() = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseManualTags(t *testing.T) {
+	got := parseManualTags("https://example.com/bolo tag: dessert, quick")
+	if strings.Join(got, "|") != "dessert|quick" {
+		t.Errorf("parseManualTags() = %#v", got)
+	}
+	if got := parseManualTags("grandma's recipe"); len(got) != 0 {
+		t.Errorf("parseManualTags() without tags = %#v", got)
+	}
+}
+
+func TestFormatRecipePreview(t *testing.T) {
+	r := &clipper.ExtractedRecipe{
+		Title:       "Bolo_de_Cenoura",
+		Ingredients: []string{"3 cenouras"},
+		Steps:       []string{"Bata tudo", "Asse por *40* minutos"},
+		PrepTime:    "50 mins",
+	}
+
+	out := formatRecipePreview(r, []string{"sobremesa"})
+	for _, want := range []string{"*Bolo\\_de\\_Cenoura*", "• 3 cenouras", "2. Asse por \\*40\\* minutos", "🏷 sobremesa"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("preview missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatDuplicateWarning(t *testing.T) {
+	if got := formatDuplicateWarning(nil); got != "" {
+		t.Errorf("expected no warning for a new recipe, got %q", got)
+	}
+
+	dup := &recipe.Duplicate{Recipe: value.Recipe{Title: "Beef Stew"}, Reason: recipe.DuplicateSimilar, Score: 0.956}
+	if got := formatDuplicateWarning(dup); !strings.Contains(got, "_Beef Stew_ looks very similar (96% match)") {
+		t.Errorf("unexpected warning: %q", got)
+	}
+}
+
+func TestClipActionsKeyboardDraft(t *testing.T) {
+	published := clipActionsKeyboard("abc", false).InlineKeyboard[1]
+	if *published[0].CallbackData != "clipunpub|abc" || *published[1].CallbackData != "clipdel|abc" {
+		t.Errorf("unexpected published buttons: %s, %s", *published[0].CallbackData, *published[1].CallbackData)
+	}
+
+	draft := clipActionsKeyboard("abc", true).InlineKeyboard[1]
+	if *draft[0].CallbackData != "clipshow|abc" || *draft[1].CallbackData != "clipdel|abc|draft" {
+		t.Errorf("unexpected draft buttons: %s, %s", *draft[0].CallbackData, *draft[1].CallbackData)
+	}
+}
+
+func TestPlanCookingKeyboardSkipsReuseDays(t *testing.T) {
+	plan := &planner.MealPlan{
+		ID: 7,
+		Plan: []planner.DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Tacos"},
+			{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Tacos"},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Soup"},
+		},
+	}
+
+	keyboard := planCookingKeyboard(plan)
+	if keyboard == nil || len(keyboard.InlineKeyboard) != 2 {
+		t.Fatalf("expected two cook buttons, got %+v", keyboard)
+	}
+	if got := *keyboard.InlineKeyboard[1][0].CallbackData; got != "cook|7|2" {
+		t.Errorf("callback = %q, want cook|7|2", got)
+	}
+}
+
+func TestCookingPages(t *testing.T) {
+	b := &Bot{cfg: &config.Config{DefaultAdults: 2, DefaultChildren: 2}}
+	rec := value.Recipe{
+		Title:       "Feijoada",
+		Ingredients: []string{"1 kg de feijão preto", "Sal a gosto"},
+		Steps:       []string{"Deixe o feijão de molho.", "Cozinhe por 20 minutos na pressão."},
+		Yield:       6,
+	}
+
+	intro := b.formatCookingPage(rec, 0)
+	if !strings.Contains(intro, "• 1/2 kg de feijão preto") || !strings.Contains(intro, "Scaled for 3 servings") {
+		t.Errorf("ingredients were not scaled for the household:\n%s", intro)
+	}
+
+	keyboard := cookingKeyboard(42, rec, 2)
+	if got := *keyboard.InlineKeyboard[0][1].CallbackData; got != "cookdone|42" {
+		t.Errorf("last step should offer Done, got %q", got)
+	}
+	if len(keyboard.InlineKeyboard) != 2 || *keyboard.InlineKeyboard[1][0].CallbackData != "cooktimer|42|20" {
+		t.Errorf("expected a 20 minute timer button, got %+v", keyboard.InlineKeyboard)
+	}
+}
+
+func TestRatingDueDays(t *testing.T) {
+	plan := &planner.MealPlan{
+		WeekStart: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+		Plan: []planner.DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Feijoada"},
+			{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Feijoada"},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Moqueca"},
+			{Day: "Thursday", RecipeTitle: "Eat out"},
+		},
+	}
+
+	tests := []struct {
+		now  time.Time
+		want []int
+	}{
+		{time.Date(2026, 10, 13, 11, 0, 0, 0, time.UTC), []int{0}},
+		{time.Date(2026, 10, 14, 11, 0, 0, 0, time.UTC), nil}, // Tuesday was leftovers
+		{time.Date(2026, 10, 15, 11, 0, 0, 0, time.UTC), []int{2}},
+		{time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC), nil}, // Nothing cooked on Thursday
+	}
+	for _, tt := range tests {
+		if got := ratingDueDays(plan, tt.now); !slices.Equal(got, tt.want) {
+			t.Errorf("ratingDueDays(%s) = %v, want %v", tt.now.Format("Mon"), got, tt.want)
+		}
+	}
+}
+
+func TestRatingKeyboardFitsCallbackLimit(t *testing.T) {
+	keyboard := ratingKeyboard(1234567890, 6)
+	if len(keyboard.InlineKeyboard) != 2 || len(keyboard.InlineKeyboard[0]) != 5 {
+		t.Fatalf("unexpected keyboard layout: %+v", keyboard.InlineKeyboard)
+	}
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			if len(*button.CallbackData) > maxCallbackData {
+				t.Errorf("callback data %q leaves no room for its signature", *button.CallbackData)
+			}
+		}
+	}
+}
+
+func TestParseRepeatArgs(t *testing.T) {
+	current := planner.RepetitionSettings{RepeatWindowWeeks: 3, FavoriteRepeatWeeks: 2}
+	tests := []struct {
+		args    []string
+		want    planner.RepetitionSettings
+		wantErr bool
+	}{
+		{args: []string{"6", "4"}, want: planner.RepetitionSettings{RepeatWindowWeeks: 6, FavoriteRepeatWeeks: 4}},
+		{args: []string{"5"}, want: planner.RepetitionSettings{RepeatWindowWeeks: 5, FavoriteRepeatWeeks: 2}},
+		{args: []string{"1"}, want: planner.RepetitionSettings{RepeatWindowWeeks: 1, FavoriteRepeatWeeks: 1}},
+		{args: []string{"2", "4"}, wantErr: true},
+		{args: []string{"many"}, wantErr: true},
+		{args: []string{"99"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRepeatArgs(tt.args, current)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRepeatArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseRepeatArgs(%v) = %+v, want %+v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestUndoEntry(t *testing.T) {
+	state := func(version int) json.RawMessage {
+		return json.RawMessage(fmt.Sprintf(`{"version":%d,"plan":[{"day":"Monday"}]}`, version))
+	}
+	change := func(id int64, action string, newVersion int) audit.Entry {
+		return audit.Entry{ID: id, ActionType: action, NewState: state(newVersion)}
+	}
+
+	// v1 -> revise v2 -> revise v3 -> undo v4 (copy of v2), listed newest first
+	entries := []audit.Entry{
+		change(3, audit.ActionUndoChange, 4),
+		change(2, audit.ActionRevisePlan, 3),
+		change(1, audit.ActionRevisePlan, 2),
+	}
+	if got := undoEntry(entries, 4); got == nil || got.ID != 1 {
+		t.Errorf("undoEntry() after one undo = %+v, want entry 1", got)
+	}
+	if got := undoEntry(entries[1:], 3); got == nil || got.ID != 2 {
+		t.Errorf("undoEntry() = %+v, want entry 2", got)
+	}
+	if got := undoEntry(entries, 5); got != nil {
+		t.Errorf("undoEntry() for a plan regenerated since = %+v, want nil", got)
+	}
+
+	allUndone := append([]audit.Entry{change(4, audit.ActionUndoChange, 5)}, entries...)
+	if got := undoEntry(allUndone, 5); got != nil {
+		t.Errorf("undoEntry() with every change undone = %+v, want nil", got)
+	}
+}
+
+func TestHistoryKeyboardFitsCallbackLimit(t *testing.T) {
+	var entries []audit.Entry
+	for id := int64(historyLimit); id > 0; id-- {
+		entries = append(entries, audit.Entry{ID: 1234567890 + id, ActionType: audit.ActionRevisePlan})
+	}
+
+	keyboard := historyKeyboard(entries)
+	var labels []string
+	for _, row := range keyboard.InlineKeyboard {
+		if len(row) > 5 {
+			t.Errorf("row has %d buttons, want at most 5", len(row))
+		}
+		for _, button := range row {
+			labels = append(labels, button.Text)
+			if len(*button.CallbackData) > maxCallbackData {
+				t.Errorf("callback data %q leaves no room for its signature", *button.CallbackData)
+			}
+		}
+	}
+	if len(labels) != historyLimit+1 || labels[0] != "0" || labels[historyLimit] != fmt.Sprint(historyLimit) {
+		t.Errorf("button labels = %v", labels)
+	}
+	if got := *keyboard.InlineKeyboard[0][0].CallbackData; got != "restore|1234567891|before" {
+		t.Errorf("first button restores %q, want the state before the oldest change", got)
+	}
+}
+
+func TestSwapKeyboardsFitCallbackLimit(t *testing.T) {
+	plan := &planner.MealPlan{
+		ID:      1234567890,
+		Version: 123,
+		Plan: []planner.DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Tacos"},
+			{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Tacos"},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Soup"},
+		},
+	}
+
+	rows := swapKeyboardRows(plan)
+	if len(rows) != 1 || len(rows[0]) != 2 {
+		t.Fatalf("swap rows = %+v, want one button per Cook day", rows)
+	}
+	if got := *rows[0][1].CallbackData; got != "swap|1234567890|2|similar" {
+		t.Errorf("callback = %q, want swap|1234567890|2|similar", got)
+	}
+
+	alternatives := []value.Recipe{
+		{ID: strings.Repeat("a", 24), Title: strings.Repeat("Very long recipe title ", 4)},
+	}
+	_, keyboard := formatSwapOptions(plan, 2, planner.SwapDifferent, alternatives)
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			if len(*button.CallbackData) > maxCallbackData {
+				t.Errorf("callback data %q leaves no room for its signature", *button.CallbackData)
+			}
+			if len([]rune(button.Text)) > maxButtonTitle+3 {
+				t.Errorf("button label %q was not truncated", button.Text)
+			}
+		}
+	}
+	if got := *keyboard.InlineKeyboard[1][0].CallbackData; got != "swap|1234567890|2|similar" {
+		t.Errorf("toggle callback = %q, want to switch back to similar recipes", got)
+	}
+}
+
+func TestShortDay(t *testing.T) {
+	for day, want := range map[string]string{
+		"Monday":            "Mon",
+		"Saturday (Lunch)":  "Sat L",
+		"Saturday (Dinner)": "Sat D",
+		"Sunday (Dinner)":   "Sun D",
+		"Day 9":             "Day",
+	} {
+		if got := shortDay(day); got != want {
+			t.Errorf("shortDay(%q) = %q, want %q", day, got, want)
+		}
+	}
+}
+
+func TestLockedDaysInDraftKeyboard(t *testing.T) {
+	plan := &planner.MealPlan{
+		ID: 7,
+		Plan: []planner.DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Tacos", Locked: true},
+			{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Tacos", Locked: true},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Soup"},
+		},
+	}
+
+	swaps := swapKeyboardRows(plan)
+	if len(swaps) != 1 || len(swaps[0]) != 1 || *swaps[0][0].CallbackData != "swap|7|2|similar" {
+		t.Errorf("swap rows = %+v, want only the unlocked Wednesday", swaps)
+	}
+
+	locks := lockKeyboardRows(plan)
+	if len(locks) != 1 || len(locks[0]) != 2 {
+		t.Fatalf("lock rows = %+v, want one toggle per Cook day", locks)
+	}
+	if got := locks[0][0].Text; got != "🔒 Mon" {
+		t.Errorf("locked day label = %q, want 🔒 Mon", got)
+	}
+	if got := locks[0][1].Text; got != "🔓 Wed" {
+		t.Errorf("unlocked day label = %q, want 🔓 Wed", got)
+	}
+	if got := *locks[0][0].CallbackData; got != "lock|7|0" {
+		t.Errorf("lock callback = %q, want lock|7|0", got)
+	}
+
+	if text := formatDraftPlanMarkdown(plan); !strings.Contains(text, "*Monday/Tuesday* 🔒: Tacos") {
+		t.Errorf("draft does not mark the locked days:\n%s", text)
+	}
+}
+
+func TestAppendFeedbackKeepsNewest(t *testing.T) {
+	var history []string
+	for i := 1; i <= adjustFeedbackLimit+2; i++ {
+		history = appendFeedback(history, fmt.Sprintf("change %d", i))
+	}
+	if len(history) != adjustFeedbackLimit {
+		t.Fatalf("history has %d entries, want %d", len(history), adjustFeedbackLimit)
+	}
+	if history[0] != "change 3" || history[len(history)-1] != fmt.Sprintf("change %d", adjustFeedbackLimit+2) {
+		t.Errorf("history = %v, want the newest feedback in order", history)
+	}
+
+	keyboard := adjustSessionKeyboard(1234567890)
+	if got := *keyboard.InlineKeyboard[0][1].CallbackData; got != "adjustcancel|1234567890" {
+		t.Errorf("cancel callback = %q", got)
+	}
+}
+
+func TestParseCommandIntent(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   Intent
+		routed bool
+	}{
+		{"https://example.com/feijoada", Intent{Kind: IntentClip, Request: "https://example.com/feijoada"}, true},
+		{"/plan no fish this week", Intent{Kind: IntentPlan, Request: "no fish this week"}, true},
+		{"/Plan@MealBot", Intent{Kind: IntentPlan}, true},
+		{"/adjust vegetarian friday", Intent{Kind: IntentAdjust, Request: "vegetarian friday"}, true},
+		{"/tomorrow", Intent{Kind: IntentQueryPlan, Day: "tomorrow"}, true},
+		{"/week", Intent{Kind: IntentQueryPlan}, true},
+		{"/add milk, eggs,  ", Intent{Kind: IntentShoppingAdd, Items: []string{"milk", "eggs"}}, true},
+		{"/remove milk", Intent{Kind: IntentShoppingRemove, Items: []string{"milk"}}, true},
+		{"/search pumpkin soup", Intent{Kind: IntentSearch, Request: "pumpkin soup"}, true},
+		{"/random", Intent{Kind: IntentRandom}, true},
+		{"/unknown", Intent{Kind: IntentHelp}, true},
+		{"what's for dinner tomorrow?", Intent{}, false},
+	}
+	for _, tt := range tests {
+		got, routed := parseCommandIntent(tt.text)
+		if routed != tt.routed || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCommandIntent(%q) = %+v, %v, want %+v, %v", tt.text, got, routed, tt.want, tt.routed)
+		}
+	}
+}
+
+func TestIntentClassifier(t *testing.T) {
+	ctx := context.Background()
+
+	gen := &llmtest.MockTextGenerator{Response: "```json\n{\"intent\": \"shopping_add\", \"items\": [\"leite\", \"ovos\"]}\n```"}
+	result, err := NewIntentClassifier(gen).Classify(ctx, "coloca leite e ovos na lista")
+	if err != nil {
+		t.Fatalf("Classify() error = %v", err)
+	}
+	want := Intent{Kind: IntentShoppingAdd, Items: []string{"leite", "ovos"}}
+	if !reflect.DeepEqual(result.Intent, want) {
+		t.Errorf("Classify() = %+v, want %+v", result.Intent, want)
+	}
+	if result.Intent.Expensive() {
+		t.Error("shopping edits should not ask for confirmation")
+	}
+
+	for _, response := range []string{`{"intent": "order_pizza"}`, `{"intent": "shopping_remove", "items": []}`, "not json"} {
+		gen := &llmtest.MockTextGenerator{Response: response}
+		if _, err := NewIntentClassifier(gen).Classify(ctx, "hi"); err == nil {
+			t.Errorf("Classify() accepted %q", response)
+		}
+	}
+}
+
+func TestPlanDayQueries(t *testing.T) {
+	now := time.Date(2026, 3, 21, 9, 0, 0, 0, time.UTC) // Saturday
+	for day, want := range map[string]string{"today": "2026-03-21", "tomorrow": "2026-03-22", "saturday": "2026-03-21", "monday": "2026-03-23"} {
+		got, ok := resolvePlanDay(day, now)
+		if !ok || got.Format("2006-01-02") != want {
+			t.Errorf("resolvePlanDay(%q) = %s, %v, want %s", day, got.Format("2006-01-02"), ok, want)
+		}
+	}
+	if _, ok := resolvePlanDay("someday", now); ok {
+		t.Error("resolvePlanDay() accepted an unknown day")
+	}
+
+	plan := &planner.MealPlan{
+		WeekStart: time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC),
+		Status:    planner.StatusFinal,
+		Plan: []planner.DayPlan{
+			{Day: "Monday", RecipeTitle: "Cook: Feijoada", PrepTime: "3 hours"},
+			{Day: "Tuesday", RecipeTitle: "Leftovers: Feijoada"},
+			{Day: "Wednesday", RecipeTitle: "Cook: Curry"},
+			{Day: "Thursday", RecipeTitle: "Leftovers: Curry"},
+			{Day: "Friday", RecipeTitle: "Cook: Lasagna"},
+			{Day: "Saturday (Lunch)", RecipeTitle: "Leftovers: Lasagna"},
+			{Day: "Saturday (Dinner)", RecipeTitle: "Cook: Moqueca", PrepTime: "40 mins", SideDishes: []string{"Rice"}},
+			{Day: "Sunday (Lunch)", RecipeTitle: "Leftovers: Moqueca"},
+			{Day: "Sunday (Dinner)", RecipeTitle: "Cook: Salad"},
+		},
+	}
+	want := "🍽️ *Today (Saturday)*\n*Lunch*: Leftovers: Lasagna\n*Dinner*: Cook: Moqueca (40 mins)\n   — Rice"
+	if got := formatDayQuery(plan, "today", now); got != want {
+		t.Errorf("formatDayQuery(today) = %q, want %q", got, want)
+	}
+	if got := formatDayQuery(plan, "tomorrow", now.AddDate(0, 0, 1)); !strings.Contains(got, "*Lunch*: Leftovers: Moqueca") || !strings.Contains(got, "*Dinner*: Cook: Salad") {
+		t.Errorf("formatDayQuery(tomorrow) = %q, want both Sunday meals", got)
+	}
+	if got := formatDayQuery(plan, "friday", now.AddDate(0, 0, -1)); got != "🍽️ *Friday*: Cook: Lasagna" {
+		t.Errorf("formatDayQuery(friday) = %q", got)
+	}
+	if got := formatDayQuery(nil, "monday", now.AddDate(0, 0, 2)); got != "🗓️ Nothing is planned for Monday yet." {
+		t.Errorf("formatDayQuery(no plan) = %q", got)
+	}
+}
+
+func TestShoppingItemEdits(t *testing.T) {
+	list := []string{"1 L whole milk", "6 eggs"}
+
+	list, added := addShoppingItems(list, []string{"Eggs", "6 EGGS", "bread"})
+	if !reflect.DeepEqual(added, []string{"Eggs", "bread"}) || len(list) != 4 {
+		t.Errorf("addShoppingItems() added %v, list %v", added, list)
+	}
+
+	list, removed := removeShoppingItems(list, []string{"milk", "bread"})
+	if !reflect.DeepEqual(removed, []string{"1 L whole milk", "bread"}) {
+		t.Errorf("removeShoppingItems() removed %v", removed)
+	}
+	if !reflect.DeepEqual(list, []string{"6 eggs", "Eggs"}) {
+		t.Errorf("removeShoppingItems() kept %v", list)
+	}
+
+	list = append(list, "2 eggplants", "1 pão", "500 g pãozinho")
+	list, removed = removeShoppingItems(list, []string{"egg", "pão"})
+	if !reflect.DeepEqual(removed, []string{"6 eggs", "Eggs", "1 pão"}) {
+		t.Errorf("removeShoppingItems() removed %v, want whole words only", removed)
+	}
+	if !reflect.DeepEqual(list, []string{"2 eggplants", "500 g pãozinho"}) {
+		t.Errorf("removeShoppingItems() kept %v", list)
+	}
+}
+
+func TestDailyReminderMessage(t *testing.T) {
+	today := time.Date(2026, 3, 4, 0, 0, 0, 0, time.Local)
+	meals := []planner.DayPlan{{Day: "Wednesday", RecipeTitle: "Cook: Chicken_Curry", PrepTime: "40 mins", SideDishes: []string{"Rice"}}}
+	prep := []string{"🧊 If the pork for Ribs is frozen, move it to the fridge tonight."}
+
+	text := formatDailyReminder(today, meals, prep)
+	for _, want := range []string{"*Today (Wednesday)*: Cook: Chicken\\_Curry (40 mins)", "— Rice", "*Tonight, for tomorrow:*", prep[0]} {
+		if !strings.Contains(text, want) {
+			t.Errorf("reminder does not contain %q:\n%s", want, text)
+		}
+	}
+	if text := formatDailyReminder(today, nil, prep); strings.Contains(text, "Today") || !strings.Contains(text, prep[0]) {
+		t.Errorf("reminder without a meal today = %q, want only the prep tasks", text)
+	}
+	if text := formatDailyReminder(today, nil, nil); text != "" {
+		t.Errorf("reminder with nothing to say = %q, want none", text)
+	}
+
+	saturday := time.Date(2026, 3, 7, 0, 0, 0, 0, time.Local)
+	weekend := []planner.DayPlan{
+		{Day: "Saturday (Lunch)", RecipeTitle: "Leftovers: Lasagna"},
+		{Day: "Saturday (Dinner)", RecipeTitle: "Cook: Moqueca"},
+	}
+	if text := formatDailyReminder(saturday, weekend, nil); text != "☀️ *Today (Saturday)*\n*Lunch*: Leftovers: Lasagna\n*Dinner*: Cook: Moqueca" {
+		t.Errorf("weekend reminder = %q, want both meals", text)
+	}
+}
+
+func TestReminderWindow(t *testing.T) {
+	for hour, want := range map[int]bool{7: false, 8: true, 10: true, 11: false} {
+		local := time.Date(2026, 3, 4, hour, 30, 0, 0, time.UTC)
+		if got := inReminderWindow(local, dailyReminderHour); got != want {
+			t.Errorf("inReminderWindow(%02d:30) = %v, want %v", hour, got, want)
+		}
+	}
+}
+
+func TestParseRemindersArgs(t *testing.T) {
+	current := reminder.Settings{Timezone: "Europe/Berlin", Enabled: true}
+
+	if got, err := parseRemindersArgs([]string{"OFF"}, current); err != nil || got.Enabled || got.Timezone != "Europe/Berlin" {
+		t.Errorf("parseRemindersArgs(off) = %+v, %v", got, err)
+	}
+	if got, err := parseRemindersArgs([]string{"America/Sao_Paulo"}, current); err != nil || !got.Enabled || got.Timezone != "America/Sao_Paulo" {
+		t.Errorf("parseRemindersArgs(timezone) = %+v, %v", got, err)
+	}
+	for _, args := range [][]string{{"Mars/Olympus"}, {"on", "off"}} {
+		if _, err := parseRemindersArgs(args, current); err == nil {
+			t.Errorf("parseRemindersArgs(%q) accepted invalid input", args)
+		}
+	}
+}
+
+func TestSearchPages(t *testing.T) {
+	data := SessionContextData{OriginalRequest: "pumpkin", RecipeIDs: []string{"r1", "r2", "r3", "r4", "r5"}}
+
+	first := []value.Recipe{
+		{ID: "r1", Title: "Pumpkin_Soup", PrepTime: "30 mins", PostURL: "https://blog.example.com/pumpkin-soup/", Tags: []string{"soup", "vegetarian", "autumn", "quick", "cheap"}},
+		{ID: "r2", Title: "Pumpkin Risotto", PostURL: "https://blog.example.com/risotto_(pumpkin)/"},
+		{ID: "r3", Title: "Pumpkin Pie"},
+	}
+	text, keyboard := formatSearchPage(42, data, first, 0)
+	for _, want := range []string{
+		"🔎 *Recipes for* _pumpkin_ — 1-3 of 5",
+		"*1. Pumpkin\\_Soup*\n⏱️ 30 mins · 🏷️ soup, vegetarian, autumn, quick\n[🔗 Open recipe](https://blog.example.com/pumpkin-soup/)",
+		"[🔗 Open recipe](https://blog.example.com/risotto_%28pumpkin%29/)",
+		"*3. Pumpkin Pie*",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("first page does not contain %q:\n%s", want, text)
+		}
+	}
+	if len(keyboard.InlineKeyboard) != 4 {
+		t.Fatalf("first page has %d rows, want an add button per recipe and the next page", len(keyboard.InlineKeyboard))
+	}
+	if got := *keyboard.InlineKeyboard[2][0].CallbackData; got != "searchadd|42|2" {
+		t.Errorf("add callback = %q, want searchadd|42|2", got)
+	}
+	if nav := keyboard.InlineKeyboard[3]; len(nav) != 1 || *nav[0].CallbackData != "search|42|1" {
+		t.Errorf("first page navigation = %+v, want only Next", nav)
+	}
+
+	// r4 was deleted since the search, so r5 keeps its number
+	text, keyboard = formatSearchPage(42, data, []value.Recipe{{ID: "r5", Title: "Pumpkin Bread"}}, 1)
+	if !strings.Contains(text, "4-5 of 5") || !strings.Contains(text, "*5. Pumpkin Bread*") || strings.Contains(text, "Open recipe") {
+		t.Errorf("second page:\n%s", text)
+	}
+	if got := *keyboard.InlineKeyboard[0][0].CallbackData; got != "searchadd|42|4" {
+		t.Errorf("add callback = %q, want searchadd|42|4", got)
+	}
+	if nav := keyboard.InlineKeyboard[1]; len(nav) != 1 || *nav[0].CallbackData != "search|42|0" {
+		t.Errorf("last page navigation = %+v, want only Previous", nav)
+	}
+
+	random, _ := formatSearchPage(42, SessionContextData{RecipeIDs: []string{"r1"}}, first[:1], 0)
+	if !strings.HasPrefix(random, "🎲 *Random recipes* — 1-1 of 1") {
+		t.Errorf("random results header:\n%s", random)
+	}
+}
+
+func TestSearchDayPicker(t *testing.T) {
+	plan := &planner.MealPlan{
+		ID:        1234567890,
+		Version:   123,
+		WeekStart: time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local),
+		Plan: []planner.DayPlan{
+			{Day: "Monday", RecipeID: "r1", RecipeTitle: "Cook: Tacos", Locked: true},
+			{Day: "Tuesday", RecipeID: "r1", RecipeTitle: "Leftovers: Tacos", Locked: true},
+			{Day: "Wednesday", RecipeID: "r2", RecipeTitle: "Cook: Soup"},
+			{Day: "Thursday", RecipeID: "r2", RecipeTitle: "Leftovers: Soup"},
+		},
+	}
+
+	monday := plan.WeekStart.Add(9 * time.Hour)
+	text, keyboard, ok := formatSearchDayPicker(9876543, 7, value.Recipe{Title: "Pumpkin Pie"}, plan, monday)
+	if !ok {
+		t.Fatal("formatSearchDayPicker() found no day")
+	}
+	if !strings.Contains(text, "*Pumpkin Pie*") || !strings.Contains(text, "2026-03-02") {
+		t.Errorf("day picker text:\n%s", text)
+	}
+	days := keyboard.InlineKeyboard[0]
+	if len(days) != 1 || days[0].Text != "Wed" {
+		t.Fatalf("day buttons = %+v, want only the unlocked Cook day", days)
+	}
+	if got := *days[0].CallbackData; got != "searchto|9876543|7|1234567890|123|2" || len(got) > maxCallbackData {
+		t.Errorf("day callback = %q", got)
+	}
+	if back := *keyboard.InlineKeyboard[1][0].CallbackData; back != "search|9876543|2" {
+		t.Errorf("back callback = %q, want the page of result 7", back)
+	}
+
+	if _, _, ok := formatSearchDayPicker(1, 0, value.Recipe{Title: "Pie"}, plan, monday.AddDate(0, 0, 3)); ok {
+		t.Error("formatSearchDayPicker() offered a day that has passed")
+	}
+	plan.Plan[2].Locked = true
+	if _, _, ok := formatSearchDayPicker(1, 0, value.Recipe{Title: "Pie"}, plan, monday); ok {
+		t.Error("formatSearchDayPicker() offered days of a fully locked plan")
+	}
+}
+
+func TestAuthorizeEveryUpdateType(t *testing.T) {
+	b := &Bot{cfg: &config.Config{TelegramAllowedUserIDs: []int64{42}}}
+	allowed, stranger := &tgbotapi.User{ID: 42}, &tgbotapi.User{ID: 7}
+
+	tests := []struct {
+		name   string
+		update tgbotapi.Update
+		want   bool
+	}{
+		{"message", tgbotapi.Update{Message: &tgbotapi.Message{From: allowed}}, true},
+		{"callback", tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{From: allowed}}, true},
+		{"stranger's message", tgbotapi.Update{Message: &tgbotapi.Message{From: stranger}}, false},
+		{"stranger's callback", tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{From: stranger}}, false},
+		{"stranger's edit", tgbotapi.Update{EditedMessage: &tgbotapi.Message{From: stranger}}, false},
+		{"no sender", tgbotapi.Update{ChannelPost: &tgbotapi.Message{}}, false},
+	}
+	for _, tt := range tests {
+		if _, got := b.authorize(&tt.update); got != tt.want {
+			t.Errorf("authorize(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCallbackSigner(t *testing.T) {
+	signer := newCallbackSigner("123:token")
+
+	signed := signer.Sign(42, "confirm|17")
+	if len(signed) != len("confirm|17")+1+callbackSignatureLen {
+		t.Fatalf("Sign() = %q, want the data and a %d character signature", signed, callbackSignatureLen)
+	}
+	if data, ok := signer.Verify(42, signed); !ok || data != "confirm|17" {
+		t.Errorf("Verify() = %q, %v; want confirm|17", data, ok)
+	}
+
+	sig := signed[strings.LastIndex(signed, "|"):]
+	for name, tt := range map[string]struct {
+		chatID int64
+		data   string
+	}{
+		"another chat":    {43, signed},
+		"another plan":    {42, "confirm|18" + sig},
+		"unsigned":        {42, "confirm|17"},
+		"another bot key": {42, newCallbackSigner("456:token").Sign(42, "confirm|17")},
+	} {
+		if _, ok := signer.Verify(tt.chatID, tt.data); ok {
+			t.Errorf("Verify() accepted a callback from %s", name)
+		}
+	}
+}
+
+func TestSignMarkupKeepsCallerKeyboard(t *testing.T) {
+	signer := newCallbackSigner("123:token")
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Confirm", "confirm|17"),
+		tgbotapi.NewInlineKeyboardButtonURL("Open", "https://example.com"),
+	))
+
+	msg := tgbotapi.NewMessage(42, "plan")
+	msg.ReplyMarkup = keyboard
+	sent := signer.signMarkup(msg).(tgbotapi.MessageConfig)
+	row := sent.ReplyMarkup.(*tgbotapi.InlineKeyboardMarkup).InlineKeyboard[0]
+	if data, ok := signer.Verify(42, *row[0].CallbackData); !ok || data != "confirm|17" {
+		t.Errorf("sent callback %q does not verify", *row[0].CallbackData)
+	}
+	if row[1].CallbackData != nil || row[1].URL == nil {
+		t.Errorf("URL button changed: %+v", row[1])
+	}
+
+	edit := tgbotapi.NewEditMessageText(42, 1, "plan")
+	edit.ReplyMarkup = &keyboard
+	signedEdit := signer.signMarkup(edit).(tgbotapi.EditMessageTextConfig)
+	if _, ok := signer.Verify(42, *signedEdit.ReplyMarkup.InlineKeyboard[0][0].CallbackData); !ok {
+		t.Error("edited keyboard was not signed")
+	}
+
+	if got := *keyboard.InlineKeyboard[0][0].CallbackData; got != "confirm|17" {
+		t.Errorf("caller's keyboard was modified: %q", got)
+	}
+}
+
+func TestSignMarkupFitsCallbackLimit(t *testing.T) {
+	signer := newCallbackSigner("123:token")
+	// The cut at 55 bytes falls inside "ó", which must not be split
+	request := "/plan jantares leves e sem glúten, e legumes e só feijão e pão para a semana"
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔄 Redo Next Week", "redo|"+request),
+	))
+
+	edit := tgbotapi.NewEditMessageText(42, 1, "plan exists")
+	edit.ReplyMarkup = &keyboard
+	signed := *signer.signMarkup(edit).(tgbotapi.EditMessageTextConfig).ReplyMarkup.InlineKeyboard[0][0].CallbackData
+	if len(signed) > 64 || !utf8.ValidString(signed) {
+		t.Fatalf("signed callback data is %d bytes, want at most 64 of valid UTF-8: %q", len(signed), signed)
+	}
+	data, ok := signer.Verify(42, signed)
+	if !ok || !strings.HasPrefix("redo|"+request, data) || len(data) < maxCallbackData-utf8.UTFMax {
+		t.Errorf("Verify() = %q, %v; want as much of the request as fits", data, ok)
+	}
+}
+
+func TestWebhookRequiresSecretToken(t *testing.T) {
+	b := &Bot{
+		cfg:           &config.Config{TelegramAllowedUserIDs: []int64{42}},
+		webhookSecret: "s3cret",
+	}
+	// A stranger's update passes the secret check and is then dropped by authorization
+	body := `{"update_id": 1, "message": {"message_id": 1, "from": {"id": 7}, "chat": {"id": 7}, "text": "hi"}}`
+
+	for _, tt := range []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong", "guess", http.StatusUnauthorized},
+		{"valid", "s3cret", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		if tt.header != "" {
+			req.Header.Set(webhookSecretHeader, tt.header)
+		}
+		rec := httptest.NewRecorder()
+		b.handleWebhook(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s secret: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestWebhookSecretFor(t *testing.T) {
+	if got := webhookSecretFor(&config.Config{TelegramWebhookSecret: "configured"}); got != "configured" {
+		t.Errorf("webhookSecretFor() = %q, want the configured secret", got)
+	}
+	derived := webhookSecretFor(&config.Config{TelegramBotToken: "123:token"})
+	if derived == "" || derived == webhookSecretFor(&config.Config{TelegramBotToken: "456:token"}) {
+		t.Errorf("derived secret %q does not depend on the bot token", derived)
+	}
+	// Telegram accepts 1-256 characters from A-Z, a-z, 0-9, _ and -
+	if len(derived) > 256 || strings.Trim(derived, "0123456789abcdef") != "" {
+		t.Errorf("derived secret %q is not a valid secret_token", derived)
+	}
+}
@@ -0,0 +1,237 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"ai-meal-planner/internal/ghost"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// clipEditTTL is how long the bot waits for a new title or tags after an edit button is pressed.
+const clipEditTTL = 600
+
+// clipActionsKeyboard builds the buttons shown under a clipped recipe.
+// Ghost post IDs are 24 characters, so "action|postID|draft" stays well under the 64 byte callback limit.
+// Drafts get a Publish button instead of Unpublish; the draft flag rides along the delete
+// callbacks so the right buttons come back when a delete is cancelled.
+func clipActionsKeyboard(postID string, draft bool) tgbotapi.InlineKeyboardMarkup {
+	visibility := tgbotapi.NewInlineKeyboardButtonData("🙈 Unpublish", "clipunpub|"+postID)
+	deleteData := "clipdel|" + postID
+	if draft {
+		visibility = tgbotapi.NewInlineKeyboardButtonData("📢 Publish", "clipshow|"+postID)
+		deleteData += "|draft"
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Edit title", "cliptitle|"+postID),
+			tgbotapi.NewInlineKeyboardButtonData("🏷️ Add tags", "cliptags|"+postID),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			visibility,
+			tgbotapi.NewInlineKeyboardButtonData("🗑️ Delete", deleteData),
+		),
+	)
+}
+
+// isDraftCallback reports whether a clip action callback was sent from a draft's buttons.
+func isDraftCallback(parts []string) bool {
+	return len(parts) > 2 && parts[2] == "draft"
+}
+
+// formatClippedPost renders the summary of a clipped post below the given header.
+func (b *Bot) formatClippedPost(header string, post *ghost.Post) string {
+	// Ghost serves posts by slug, not by ID
+	link := post.URL
+	if link == "" && post.Slug != "" {
+		link = fmt.Sprintf("%s/%s/", b.cfg.GhostURL, post.Slug)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header)
+	sb.WriteString(fmt.Sprintf("\n\n*Title:* %s\n*URL:* %s", post.Title, link))
+	if post.Status == ghost.StatusDraft {
+		sb.WriteString("\n*Status:* draft")
+	}
+	if len(post.Tags) > 0 {
+		var tagNames []string
+		for _, t := range post.Tags {
+			tagNames = append(tagNames, t.Name)
+		}
+		sb.WriteString("\n*Tags:* " + strings.Join(tagNames, ", "))
+	}
+	return sb.String()
+}
+
+// handleClipEditPrompt opens an edit_clip session and asks the user for the new title or tags.
+func (b *Bot) handleClipEditPrompt(ctx context.Context, query *tgbotapi.CallbackQuery, userID, action, postID string) {
+	state := StateAwaitingTitle
+	prompt := "✏️ Send me the new *title* for this recipe."
+	if action == "cliptags" {
+		state = StateAwaitingTags
+		prompt = "🏷️ Send me the *tags* to add, separated by commas."
+	}
+
+	_, err := b.sessionRepo.Create(ctx, userID, SessionTypeEditClip, state, SessionContextData{PostID: postID}, clipEditTTL)
+	if err != nil {
+		log.Printf("Error creating clip edit session: %v", err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not start editing. Please try again."))
+		return
+	}
+
+	promptMsg := tgbotapi.NewMessage(query.Message.Chat.ID, prompt)
+	promptMsg.ParseMode = "Markdown"
+	b.api.Send(promptMsg)
+}
+
+// handleClipEditReply applies the title or tags sent by the user to the Ghost post.
+func (b *Bot) handleClipEditReply(ctx context.Context, msg *tgbotapi.Message, session *Session) {
+	defer func() {
+		if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+			log.Printf("Error cleaning up session %d: %v", session.ID, err)
+		}
+	}()
+
+	contextData, err := session.GetContextData()
+	if err != nil || contextData.PostID == "" {
+		log.Printf("Error parsing clip edit session: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Invalid session data."))
+		return
+	}
+
+	var post *ghost.Post
+	switch session.State {
+	case StateAwaitingTitle:
+		title := strings.TrimSpace(msg.Text)
+		if title == "" {
+			b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ The title can't be empty."))
+			return
+		}
+		post, err = b.ghostClient.UpdatePost(contextData.PostID, ghost.PostUpdate{Title: &title})
+	case StateAwaitingTags:
+		tags := parseTagList(msg.Text)
+		if len(tags) == 0 {
+			b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ No tags found in your message."))
+			return
+		}
+		post, err = b.ghostClient.AddTags(contextData.PostID, tags)
+	default:
+		log.Printf("Unknown clip edit state: %s", session.State)
+		return
+	}
+
+	if err != nil {
+		log.Printf("Error updating post %s: %v", contextData.PostID, err)
+		safeErr := strings.ReplaceAll(err.Error(), "`", "'")
+		reply := tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("❌ *Error updating recipe:*\n```\n%v\n```", safeErr))
+		reply.ParseMode = "Markdown"
+		b.api.Send(reply)
+		return
+	}
+
+	// Keep the local copy in sync; drafts are not part of the searchable catalog
+	if post.Status != ghost.StatusDraft {
+		b.spawn(func() { b.ingestClippedPost(*post) })
+	}
+
+	keyboard := clipActionsKeyboard(post.ID, post.Status == ghost.StatusDraft)
+	reply := tgbotapi.NewMessage(msg.Chat.ID, b.formatClippedPost("✅ *Recipe Updated!*", post))
+	reply.ParseMode = "Markdown"
+	reply.ReplyMarkup = keyboard
+	b.api.Send(reply)
+}
+
+// handleClipUnpublish turns the post back into a draft and removes it from the local catalog.
+func (b *Bot) handleClipUnpublish(ctx context.Context, query *tgbotapi.CallbackQuery, postID string) {
+	status := ghost.StatusDraft
+	post, err := b.ghostClient.UpdatePost(postID, ghost.PostUpdate{Status: &status})
+	if err != nil {
+		log.Printf("Error unpublishing post %s: %v", postID, err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not unpublish the recipe."))
+		return
+	}
+
+	if err := b.recipeRepo.Delete(ctx, postID); err != nil {
+		log.Printf("Warning: failed to remove unpublished recipe %s: %v", postID, err)
+	}
+
+	keyboard := clipActionsKeyboard(post.ID, true)
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, b.formatClippedPost("🙈 *Recipe Unpublished*", post))
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// handleClipShow publishes a draft and adds it to the local catalog.
+func (b *Bot) handleClipShow(query *tgbotapi.CallbackQuery, postID string) {
+	status := ghost.StatusPublished
+	post, err := b.ghostClient.UpdatePost(postID, ghost.PostUpdate{Status: &status})
+	if err != nil {
+		log.Printf("Error publishing post %s: %v", postID, err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not publish the recipe."))
+		return
+	}
+
+	b.spawn(func() { b.ingestClippedPost(*post) })
+
+	keyboard := clipActionsKeyboard(post.ID, false)
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, b.formatClippedPost("📢 *Recipe Published!*", post))
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// handleClipDeletePrompt swaps the action buttons for a delete confirmation.
+func (b *Bot) handleClipDeletePrompt(query *tgbotapi.CallbackQuery, parts []string) {
+	keepData := "clipkeep|" + parts[1]
+	if isDraftCallback(parts) {
+		keepData += "|draft"
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚠️ Yes, delete", "clipdelok|"+parts[1]),
+			tgbotapi.NewInlineKeyboardButtonData("↩️ Keep it", keepData),
+		),
+	)
+	b.api.Send(tgbotapi.NewEditMessageReplyMarkup(query.Message.Chat.ID, query.Message.MessageID, keyboard))
+}
+
+// handleClipKeep restores the action buttons after a cancelled delete.
+func (b *Bot) handleClipKeep(query *tgbotapi.CallbackQuery, parts []string) {
+	keyboard := clipActionsKeyboard(parts[1], isDraftCallback(parts))
+	b.api.Send(tgbotapi.NewEditMessageReplyMarkup(query.Message.Chat.ID, query.Message.MessageID, keyboard))
+}
+
+// handleClipDelete permanently deletes the post from Ghost and from the local catalog.
+func (b *Bot) handleClipDelete(ctx context.Context, query *tgbotapi.CallbackQuery, postID string) {
+	if err := b.ghostClient.DeletePost(postID); err != nil {
+		log.Printf("Error deleting post %s: %v", postID, err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not delete the recipe."))
+		return
+	}
+
+	if err := b.recipeRepo.Delete(ctx, postID); err != nil {
+		log.Printf("Warning: failed to remove deleted recipe %s: %v", postID, err)
+	}
+
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "🗑️ *Recipe deleted.*")
+	edit.ParseMode = "Markdown"
+	b.api.Send(edit)
+}
+
+// parseTagList splits a comma separated list of tags, lowercasing them like the clipper does.
+func parseTagList(text string) []string {
+	var tags []string
+	for _, t := range strings.Split(text, ",") {
+		trimmed := strings.ToLower(strings.TrimSpace(t))
+		if trimmed != "" {
+			tags = append(tags, trimmed)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,285 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/clipper"
+	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/value"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// clipPreviewTTL is how long an extracted recipe waits for the user to confirm it.
+const clipPreviewTTL = 1800
+
+// maxPreviewLength keeps previews under Telegram's 4096 character message limit.
+const maxPreviewLength = 3500
+
+var markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+
+// escapeMarkdown protects text we did not write (OCR output, scraped titles) from breaking Markdown parsing.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownURLEscaper percent-encodes the characters that would end or break a Markdown link target.
+var markdownURLEscaper = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20", "\\", "%5C", "`", "%60")
+
+// escapeMarkdownURL protects a URL we did not write from breaking the link it is the target of.
+func escapeMarkdownURL(url string) string {
+	return markdownURLEscaper.Replace(url)
+}
+
+// isImageDocument reports whether a file sent as a document (uncompressed photo) is an image.
+func isImageDocument(doc *tgbotapi.Document) bool {
+	return doc != nil && strings.HasPrefix(doc.MimeType, "image/")
+}
+
+// handleImageClipRequest reads a recipe from a photo, an image file or an image URL and shows a preview.
+func (b *Bot) handleImageClipRequest(msg *tgbotapi.Message) {
+	statusText := "📷 *Reading recipe from image...* \n(This can take a few seconds)"
+	replyMsg := tgbotapi.NewMessage(msg.Chat.ID, statusText)
+	replyMsg.ParseMode = "Markdown"
+	sentMsg, err := b.api.Send(replyMsg)
+	if err != nil {
+		log.Printf("Failed to send initial reply: %v", err)
+		return
+	}
+
+	ctx := context.Background()
+	userID := fmt.Sprintf("%d", msg.From.ID)
+
+	// Telegram file URLs embed the bot token, so they are never shown or stored
+	var imageURL, sourceURL string
+	var manualTags []string
+	switch {
+	case len(msg.Photo) > 0:
+		// Photo sizes are ordered from smallest to largest
+		imageURL, err = b.api.GetFileDirectURL(msg.Photo[len(msg.Photo)-1].FileID)
+		manualTags = parseManualTags(msg.Caption)
+	case isImageDocument(msg.Document):
+		imageURL, err = b.api.GetFileDirectURL(msg.Document.FileID)
+		manualTags = parseManualTags(msg.Caption)
+	default:
+		imageURL = strings.Fields(msg.Text)[0]
+		sourceURL = imageURL
+		manualTags = parseManualTags(msg.Text)
+	}
+	if err != nil {
+		log.Printf("Error resolving Telegram file for user %s: %v", userID, err)
+		b.editMarkdown(msg.Chat.ID, sentMsg.MessageID, "❌ *Error:* Could not download the image.")
+		return
+	}
+
+	image, err := b.clipper.FetchImage(ctx, imageURL)
+	if err != nil {
+		if sourceURL == "" {
+			log.Printf("Error downloading Telegram image for user %s", userID)
+			b.editMarkdown(msg.Chat.ID, sentMsg.MessageID, "❌ *Error:* Could not download the image.")
+			return
+		}
+		log.Printf("Error downloading image %s: %v", sourceURL, err)
+		safeErr := strings.ReplaceAll(err.Error(), "`", "'")
+		b.editMarkdown(msg.Chat.ID, sentMsg.MessageID, fmt.Sprintf("❌ *Error downloading image:*\n```\n%v\n```", safeErr))
+		return
+	}
+
+	extracted, err := b.clipper.ExtractFromImage(ctx, image)
+	if err != nil {
+		log.Printf("Error extracting recipe from image: %v", err)
+		safeErr := strings.ReplaceAll(err.Error(), "`", "'")
+		b.editMarkdown(msg.Chat.ID, sentMsg.MessageID, fmt.Sprintf("❌ *Error reading recipe:*\n```\n%v\n```", safeErr))
+		return
+	}
+
+	b.sendClipPreview(ctx, userID, msg.Chat.ID, sentMsg.MessageID, extracted, sourceURL, manualTags)
+}
+
+// sendClipPreview stores the extracted recipe in a session and asks the user to confirm it before publishing.
+// A recipe that already exists in the catalog is flagged so the user can skip it.
+func (b *Bot) sendClipPreview(ctx context.Context, userID string, chatID int64, messageID int, extracted *clipper.ExtractedRecipe, sourceURL string, manualTags []string) {
+	sessionCtx := SessionContextData{
+		Recipe:    extracted,
+		SourceURL: sourceURL,
+		Tags:      manualTags,
+	}
+
+	sessionID, err := b.sessionRepo.Create(ctx, userID, SessionTypeClipPreview, StateAwaitingConfirm, sessionCtx, clipPreviewTTL)
+	if err != nil {
+		log.Printf("Error creating clip preview session: %v", err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not store the recipe preview.")
+		return
+	}
+
+	candidate := value.Recipe{
+		Title:       extracted.Title,
+		Ingredients: extracted.Ingredients,
+		PrepTime:    extracted.PrepTime,
+		SourceURL:   sourceURL,
+	}
+	dup, err := b.duplicates.FindDuplicate(ctx, candidate)
+	if err != nil {
+		// A failed check should not block clipping
+		log.Printf("Error checking for duplicate recipes: %v", err)
+	}
+
+	callbackData := fmt.Sprintf("%d", sessionID)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Publish", "clippub|"+callbackData),
+			tgbotapi.NewInlineKeyboardButtonData("📝 Save as draft", "clipdraft|"+callbackData),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Cancel", "clipcancel|"+callbackData),
+		),
+	)
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, formatDuplicateWarning(dup)+formatRecipePreview(extracted, manualTags))
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// handleClipPublish saves a previewed recipe to Ghost, either published or as a draft.
+func (b *Bot) handleClipPublish(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, publish bool, parts []string) {
+	session, data, ok := b.loadClipPreview(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	post, err := b.clipper.Publish(ctx, *data.Recipe, data.SourceURL, data.Tags, publish)
+	if err != nil {
+		log.Printf("Error publishing previewed recipe: %v", err)
+		safeErr := strings.ReplaceAll(err.Error(), "`", "'")
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, fmt.Sprintf("❌ *Error saving recipe:*\n```\n%v\n```", safeErr))
+		return
+	}
+
+	if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+		log.Printf("Error cleaning up session %d: %v", session.ID, err)
+	}
+
+	header := "📝 *Draft Saved!*"
+	if publish {
+		header = "✅ *Recipe Saved!*"
+		// Trigger background ingestion so it becomes searchable for future plans
+		b.spawn(func() { b.ingestClippedPost(*post) })
+	}
+
+	keyboard := clipActionsKeyboard(post.ID, !publish)
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, b.formatClippedPost(header, post))
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// handleClipCancel discards a previewed recipe.
+func (b *Bot) handleClipCancel(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	var sessionID int64
+	fmt.Sscanf(parts[1], "%d", &sessionID)
+
+	session, err := b.sessionRepo.GetByID(ctx, sessionID, userID, time.Now())
+	if err != nil {
+		log.Printf("Error retrieving clip preview session: %v", err)
+	}
+	if session != nil {
+		if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+			log.Printf("Error cleaning up session %d: %v", session.ID, err)
+		}
+	}
+
+	b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "🚫 *Clip discarded.*")
+}
+
+// loadClipPreview resolves the preview session referenced by a callback, telling the user when it is gone.
+func (b *Bot) loadClipPreview(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) (*Session, SessionContextData, bool) {
+	var sessionID int64
+	fmt.Sscanf(parts[1], "%d", &sessionID)
+
+	session, err := b.sessionRepo.GetByID(ctx, sessionID, userID, time.Now())
+	if err != nil {
+		log.Printf("Error retrieving clip preview session: %v", err)
+	}
+	if session == nil || session.SessionType != SessionTypeClipPreview {
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "⌛ *This preview has expired.* Send the recipe again.")
+		return nil, SessionContextData{}, false
+	}
+
+	data, err := session.GetContextData()
+	if err != nil || data.Recipe == nil {
+		log.Printf("Error parsing clip preview session %d: %v", session.ID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Invalid session data.")
+		return nil, SessionContextData{}, false
+	}
+
+	return session, data, true
+}
+
+// formatDuplicateWarning explains which existing recipe a clip matches, or returns "" for new recipes.
+func formatDuplicateWarning(dup *recipe.Duplicate) string {
+	if dup == nil {
+		return ""
+	}
+
+	var reason string
+	switch dup.Reason {
+	case recipe.DuplicateSourceURL:
+		reason = "was already clipped from this page"
+	case recipe.DuplicateTitle:
+		reason = "has almost the same title"
+	default:
+		reason = fmt.Sprintf("looks very similar (%.0f%% match)", dup.Score*100)
+	}
+	return fmt.Sprintf("⚠️ *Possible duplicate:* _%s_ %s.\n\n", escapeMarkdown(dup.Recipe.Title), reason)
+}
+
+// formatRecipePreview renders an extracted recipe for review before it is published.
+func formatRecipePreview(r *clipper.ExtractedRecipe, manualTags []string) string {
+	var sb strings.Builder
+	sb.WriteString("👀 *Recipe Preview*\n\n")
+	sb.WriteString(fmt.Sprintf("*%s*\n", escapeMarkdown(r.Title)))
+
+	var details []string
+	if r.PrepTime != "" {
+		details = append(details, "⏱ "+escapeMarkdown(r.PrepTime))
+	}
+	if r.Servings != "" {
+		details = append(details, "🍽 "+escapeMarkdown(r.Servings))
+	}
+	if len(details) > 0 {
+		sb.WriteString(strings.Join(details, " | ") + "\n")
+	}
+	if len(manualTags) > 0 {
+		sb.WriteString("🏷 " + escapeMarkdown(strings.Join(manualTags, ", ")) + "\n")
+	}
+
+	sb.WriteString("\n*Ingredients*\n")
+	for _, ing := range r.Ingredients {
+		sb.WriteString("• " + escapeMarkdown(ing) + "\n")
+	}
+
+	sb.WriteString("\n*Steps*\n")
+	for i, step := range r.Steps {
+		line := fmt.Sprintf("%d. %s\n", i+1, escapeMarkdown(step))
+		if sb.Len()+len(line) > maxPreviewLength {
+			sb.WriteString(fmt.Sprintf("_…and %d more steps_\n", len(r.Steps)-i))
+			break
+		}
+		sb.WriteString(line)
+	}
+
+	sb.WriteString("\nPublish this recipe?")
+	return sb.String()
+}
+
+// editMarkdown replaces the text of a message, dropping its buttons.
+func (b *Bot) editMarkdown(chatID int64, messageID int, text string) {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = "Markdown"
+	b.api.Send(edit)
+}
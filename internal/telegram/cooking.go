@@ -0,0 +1,301 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/value"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// cookingTTL keeps a cooking session alive long enough for slow braises and bakes.
+const cookingTTL = 4 * 60 * 60
+
+// cookingTimerCheckInterval is how often the bot looks for cooking timers that rang.
+const cookingTimerCheckInterval = 20 * time.Second
+
+// maxTimerButtons limits the timer buttons shown under a single step.
+const maxTimerButtons = 3
+
+// planCookingKeyboard adds a "Start cooking" button for every Cook day of a confirmed plan.
+// Reuse days are skipped since there is nothing to cook. Returns nil when no day qualifies.
+func planCookingKeyboard(plan *planner.MealPlan) *tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, day := range plan.Plan {
+		if day.RecipeID == "" || day.IsReuse() {
+			continue
+		}
+		label := fmt.Sprintf("👩‍🍳 Start cooking %s", day.Day)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("cook|%d|%d", plan.ID, i)),
+		))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+// householdServings is the number of portions the household eats; children count as half.
+func (b *Bot) householdServings() float64 {
+	return float64(b.cfg.DefaultAdults) + float64(b.cfg.DefaultChildren)/2
+}
+
+// handleStartCooking opens a cooking session for the recipe of the chosen plan day.
+func (b *Bot) handleStartCooking(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	var planID int64
+	var dayIndex int
+	fmt.Sscanf(parts[1], "%d", &planID)
+	fmt.Sscanf(parts[2], "%d", &dayIndex)
+
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || plan == nil || dayIndex < 0 || dayIndex >= len(plan.Plan) {
+		log.Printf("Error retrieving plan %d for cooking: %v", planID, err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not find that plan day."))
+		return
+	}
+	// Buttons of a plan that was since replaced, or of a draft, cook nothing
+	if plan.Status != planner.StatusFinal {
+		b.answerCallback(query, "Only the confirmed plan can be cooked from")
+		return
+	}
+
+	rec, err := b.recipeRepo.Get(ctx, plan.Plan[dayIndex].RecipeID)
+	if err != nil {
+		log.Printf("Error loading recipe %s for cooking: %v", plan.Plan[dayIndex].RecipeID, err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not load the recipe."))
+		return
+	}
+	if len(rec.Steps) == 0 {
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "📭 This recipe has no saved steps yet. Run a backfill to extract them."))
+		return
+	}
+
+	sessionCtx := SessionContextData{PlanID: planID, RecipeID: rec.ID}
+	sessionID, err := b.sessionRepo.Create(ctx, userID, SessionTypeCooking, StateCooking, sessionCtx, cookingTTL)
+	if err != nil {
+		log.Printf("Error creating cooking session: %v", err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not start cooking mode. Please try again."))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, b.formatCookingPage(rec, 0))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = cookingKeyboard(sessionID, rec, 0)
+	b.api.Send(msg)
+}
+
+// handleCookingNav moves the cooking session one page forward or back.
+func (b *Bot) handleCookingNav(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string, delta int) {
+	session, data, rec, ok := b.loadCookingSession(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	data.Step = max(0, min(data.Step+delta, len(rec.Steps)))
+	if err := b.sessionRepo.Update(ctx, session.ID, StateCooking, data); err != nil {
+		log.Printf("Error updating cooking session %d: %v", session.ID, err)
+	}
+
+	keyboard := cookingKeyboard(session.ID, rec, data.Step)
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, b.formatCookingPage(rec, data.Step))
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// handleCookingTimer starts a timer for the current step and reminds the user when it expires.
+// Timers are stored, so one started before a restart of the bot still rings.
+func (b *Bot) handleCookingTimer(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	_, data, rec, ok := b.loadCookingSession(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	var minutes int
+	fmt.Sscanf(parts[2], "%d", &minutes)
+	if minutes <= 0 {
+		return
+	}
+
+	chatID := query.Message.Chat.ID
+	duration := time.Duration(minutes) * time.Minute
+	reminder := fmt.Sprintf("⏰ *Time's up!* %s — step %d of %d.", escapeMarkdown(rec.Title), data.Step, len(rec.Steps))
+	if err := b.sessionRepo.AddCookingTimer(ctx, chatID, reminder, time.Now().Add(duration)); err != nil {
+		log.Printf("Error saving cooking timer: %v", err)
+		b.api.Send(tgbotapi.NewMessage(chatID, "❌ Could not start the timer. Please try again."))
+		return
+	}
+
+	b.api.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("⏲ Timer started: %s. I'll remind you when it's done.", formatTimer(duration))))
+}
+
+// StartCookingTimers rings the cooking timers as they expire, checking every
+// cookingTimerCheckInterval until ctx is cancelled.
+func (b *Bot) StartCookingTimers(ctx context.Context) {
+	ticker := time.NewTicker(cookingTimerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		b.ringCookingTimers(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ringCookingTimers sends the reminder of every timer due at now. A timer whose reminder
+// cannot be sent stays stored and is tried again on the next check.
+func (b *Bot) ringCookingTimers(ctx context.Context, now time.Time) {
+	timers, err := b.sessionRepo.DueCookingTimers(ctx, now)
+	if err != nil {
+		log.Printf("Error listing cooking timers: %v", err)
+		return
+	}
+
+	for _, timer := range timers {
+		msg := tgbotapi.NewMessage(timer.ChatID, timer.Message)
+		msg.ParseMode = "Markdown"
+		if _, err := b.api.Send(msg); err != nil {
+			log.Printf("Error sending cooking timer %d: %v", timer.ID, err)
+			continue
+		}
+		if err := b.sessionRepo.DeleteCookingTimer(ctx, timer.ID); err != nil {
+			log.Printf("Error deleting cooking timer %d: %v", timer.ID, err)
+		}
+	}
+}
+
+// handleCookingDone ends the cooking session.
+func (b *Bot) handleCookingDone(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	session, _, rec, ok := b.loadCookingSession(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+		log.Printf("Error cleaning up session %d: %v", session.ID, err)
+	}
+	b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, fmt.Sprintf("🍽 *%s* is ready. Enjoy!", escapeMarkdown(rec.Title)))
+}
+
+// loadCookingSession resolves the cooking session and recipe referenced by a callback.
+func (b *Bot) loadCookingSession(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) (*Session, SessionContextData, value.Recipe, bool) {
+	var sessionID int64
+	fmt.Sscanf(parts[1], "%d", &sessionID)
+
+	session, err := b.sessionRepo.GetByID(ctx, sessionID, userID, time.Now())
+	if err != nil {
+		log.Printf("Error retrieving cooking session: %v", err)
+	}
+	if session == nil || session.SessionType != SessionTypeCooking {
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "⌛ *This cooking session has ended.* Start it again from your plan.")
+		return nil, SessionContextData{}, value.Recipe{}, false
+	}
+
+	data, err := session.GetContextData()
+	if err != nil || data.RecipeID == "" {
+		log.Printf("Error parsing cooking session %d: %v", session.ID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Invalid session data.")
+		return nil, SessionContextData{}, value.Recipe{}, false
+	}
+
+	rec, err := b.recipeRepo.Get(ctx, data.RecipeID)
+	if err != nil {
+		log.Printf("Error loading recipe %s for cooking: %v", data.RecipeID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not load the recipe.")
+		return nil, SessionContextData{}, value.Recipe{}, false
+	}
+
+	return session, data, rec, true
+}
+
+// formatCookingPage renders page 0 as the scaled ingredient list and pages 1..n as the steps.
+func (b *Bot) formatCookingPage(rec value.Recipe, page int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("👩‍🍳 *%s*\n", escapeMarkdown(rec.Title)))
+
+	if page > 0 {
+		sb.WriteString(fmt.Sprintf("_Step %d of %d_\n\n", page, len(rec.Steps)))
+		sb.WriteString(escapeMarkdown(rec.Steps[page-1]))
+		return sb.String()
+	}
+
+	servings := b.householdServings()
+	factor := 1.0
+	if rec.Yield > 0 && servings > 0 {
+		factor = servings / float64(rec.Yield)
+		sb.WriteString(fmt.Sprintf("_Scaled for %g servings (recipe makes %d)_\n", servings, rec.Yield))
+	}
+
+	sb.WriteString("\n*Ingredients*\n")
+	for _, ing := range rec.Ingredients {
+		sb.WriteString("• " + escapeMarkdown(recipe.ScaleIngredient(ing, factor)) + "\n")
+	}
+	if len(rec.Equipment) > 0 {
+		sb.WriteString("\n*Equipment:* " + escapeMarkdown(strings.Join(rec.Equipment, ", ")) + "\n")
+	}
+	sb.WriteString("\nTap *Next* when you're ready to start.")
+	return sb.String()
+}
+
+// cookingKeyboard builds the navigation buttons for a cooking page, plus timers found in the step text.
+func cookingKeyboard(sessionID int64, rec value.Recipe, page int) tgbotapi.InlineKeyboardMarkup {
+	id := fmt.Sprintf("%d", sessionID)
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("⬅️ Back", "cookprev|"+id))
+	}
+	if page < len(rec.Steps) {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("Next ➡️", "cooknext|"+id))
+	} else {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("✅ Done", "cookdone|"+id))
+	}
+	rows := [][]tgbotapi.InlineKeyboardButton{nav}
+
+	if page > 0 {
+		var timers []tgbotapi.InlineKeyboardButton
+		for _, d := range recipe.StepTimers(rec.Steps[page-1]) {
+			if len(timers) == maxTimerButtons {
+				break
+			}
+			data := fmt.Sprintf("cooktimer|%s|%d", id, int(d.Minutes()))
+			timers = append(timers, tgbotapi.NewInlineKeyboardButtonData("⏲ "+formatTimer(d), data))
+		}
+		if len(timers) > 0 {
+			rows = append(rows, timers)
+		}
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// formatTimer renders a duration as "20 min" or "1 h 30 min".
+func formatTimer(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%d min", m)
+	case m == 0:
+		return fmt.Sprintf("%d h", h)
+	default:
+		return fmt.Sprintf("%d h %d min", h, m)
+	}
+}
@@ -0,0 +1,677 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-meal-planner/internal/audit"
+	"ai-meal-planner/internal/config"
+	"ai-meal-planner/internal/database"
+	"ai-meal-planner/internal/llm"
+	"ai-meal-planner/internal/llm/llmtest"
+	"ai-meal-planner/internal/metrics"
+	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/rating"
+	"ai-meal-planner/internal/recipe"
+	"ai-meal-planner/internal/reminder"
+	"ai-meal-planner/internal/shopping"
+	"ai-meal-planner/internal/telegram/telegramtest"
+	"ai-meal-planner/internal/value"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	e2eUserID        = 42
+	e2eWebhookSecret = "s3cret"
+)
+
+// e2eChat drives the bot through handleWebhook as one user would, recording what the
+// bot sends in a fake messenger.
+type e2eChat struct {
+	t        *testing.T
+	bot      *Bot
+	fake     *telegramtest.Messenger
+	updateID int
+}
+
+// newE2EChat builds a bot on a migrated database seeded with recipes, with the agents
+// answering from the given mocks.
+func newE2EChat(t *testing.T, analystGen, chefGen, reviewerGen llm.TextGenerator, recipes ...value.Recipe) (*e2eChat, *database.DB) {
+	t.Helper()
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "e2e_test.db")
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.MigrateUp(dbPath); err != nil {
+		t.Fatalf("Failed to migrate test DB: %v", err)
+	}
+
+	recipeRepo := recipe.NewRepository(db.SQL)
+	vectorRepo := llm.NewVectorRepository(db.SQL)
+	for i, rec := range recipes {
+		if err := recipeRepo.Save(ctx, rec); err != nil {
+			t.Fatalf("Save(%s) error = %v", rec.ID, err)
+		}
+		emb := []float32{float32(i), 1}
+		if err := vectorRepo.Save(ctx, rec.ID, emb, "hash-"+rec.ID, llm.EmbeddingMetadata{Model: "test-embedding-model", Dimensions: len(emb)}); err != nil {
+			t.Fatalf("Save embedding(%s) error = %v", rec.ID, err)
+		}
+	}
+
+	embedGen := &llmtest.MockEmbeddingGenerator{Values: []float32{0, 1}}
+	ratingRepo := rating.NewRepository(db.SQL)
+	planRepo := planner.NewPlanRepository(db.SQL)
+	searcher := recipe.NewSearchService(recipeRepo, vectorRepo, embedGen, ratingRepo, planRepo)
+	cfg := &config.Config{
+		TelegramBotToken:       "test-token",
+		TelegramWebhookSecret:  e2eWebhookSecret,
+		TelegramAllowedUserIDs: []int64{e2eUserID},
+		DefaultAdults:          2,
+	}
+
+	fake := &telegramtest.Messenger{}
+	b := newBot(
+		fake,
+		cfg,
+		planner.NewPlanner(searcher, searcher, planRepo, analystGen, chefGen, reviewerGen),
+		nil,
+		nil,
+		metrics.NewStore(db.SQL),
+		&llmtest.MockTextGenerator{},
+		&llmtest.MockTextGenerator{},
+		&llmtest.MockTextGenerator{ShouldError: true},
+		embedGen,
+		planRepo,
+		recipeRepo,
+		vectorRepo,
+		shopping.NewRepository(db.SQL),
+		NewSessionRepository(db.SQL),
+		audit.NewAuditRepository(db.SQL),
+		recipe.NewTaxonomy(db.SQL),
+		ratingRepo,
+		reminder.NewRepository(db.SQL),
+	)
+	return &e2eChat{t: t, bot: b, fake: fake}, db
+}
+
+// post delivers an update through the webhook and waits for the bot to finish with it.
+func (c *e2eChat) post(update tgbotapi.Update) {
+	c.t.Helper()
+	c.updateID++
+	update.UpdateID = c.updateID
+	body, err := json.Marshal(update)
+	if err != nil {
+		c.t.Fatalf("Marshal update: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(string(body)))
+	req.Header.Set(webhookSecretHeader, e2eWebhookSecret)
+	rec := httptest.NewRecorder()
+	c.bot.handleWebhook(rec, req)
+	if rec.Code != http.StatusOK {
+		c.t.Fatalf("webhook status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	c.bot.Wait()
+}
+
+// say sends a text message from the user.
+func (c *e2eChat) say(text string) {
+	c.t.Helper()
+	c.post(tgbotapi.Update{Message: &tgbotapi.Message{
+		MessageID: 1000 + c.updateID,
+		From:      &tgbotapi.User{ID: e2eUserID},
+		Chat:      &tgbotapi.Chat{ID: e2eUserID},
+		Text:      text,
+	}})
+}
+
+// press taps the button labelled label on a message as it currently stands.
+func (c *e2eChat) press(messageID int, label string) {
+	c.t.Helper()
+	msg, ok := c.fake.Current(e2eUserID, messageID)
+	if !ok {
+		c.t.Fatalf("no message %d to press %q on", messageID, label)
+	}
+	data, ok := msg.Button(label)
+	if !ok {
+		c.t.Fatalf("message %d has no %q button:\n%s", messageID, label, msg.Text)
+	}
+	queryID := fmt.Sprintf("cb%d", c.updateID+1)
+	c.post(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      queryID,
+		From:    &tgbotapi.User{ID: e2eUserID},
+		Message: &tgbotapi.Message{MessageID: msg.MessageID, Chat: &tgbotapi.Chat{ID: e2eUserID}, Text: msg.Text},
+		Data:    data,
+	}})
+
+	// Telegram takes exactly one answer per press
+	answers := 0
+	for _, answer := range c.fake.Callbacks() {
+		if answer.CallbackQueryID == queryID {
+			answers++
+		}
+	}
+	if answers != 1 {
+		c.t.Errorf("pressing %q was answered %d times, want once", label, answers)
+	}
+}
+
+// toast returns the text of the most recent answer to a button press.
+func (c *e2eChat) toast() string {
+	c.t.Helper()
+	answers := c.fake.Callbacks()
+	if len(answers) == 0 {
+		c.t.Fatal("no button press was answered")
+	}
+	return answers[len(answers)-1].Text
+}
+
+// last returns the most recent message or edit the bot made.
+func (c *e2eChat) last() telegramtest.Message {
+	c.t.Helper()
+	msg, ok := c.fake.Last()
+	if !ok {
+		c.t.Fatal("the bot sent nothing")
+	}
+	return msg
+}
+
+func toolCall(name string, args map[string]any) llm.ContentResponse {
+	return llm.ContentResponse{Message: llm.Message{
+		Role:      "assistant",
+		ToolCalls: []llm.ToolCall{{ID: "call_" + name, Name: name, Args: args}},
+	}}
+}
+
+func chefReply(content string) llm.ContentResponse {
+	return llm.ContentResponse{Message: llm.Message{Role: "assistant", Content: content}}
+}
+
+func TestEndToEndPlanAdjustConfirm(t *testing.T) {
+	ctx := context.Background()
+	analyst := &llmtest.MockTextGenerator{ResponseChain: []llm.ContentResponse{
+		toolCall("search_recipes_semantic", map[string]any{"query": "something light"}),
+		toolCall("submit_meal_proposal", map[string]any{"planned_meals": []any{
+			map[string]any{"day": "Monday", "action": "Cook", "recipe_title": "Pasta", "note": "Quick"},
+			map[string]any{"day": "Tuesday", "action": "Cook", "recipe_title": "Salad", "note": "Light"},
+		}}),
+	}}
+	chef := &llmtest.MockTextGenerator{ResponseChain: []llm.ContentResponse{
+		chefReply(`{"plan": [{"day": "Monday", "recipe_title": "Cook: Pasta", "prep_time": "15 mins"}, {"day": "Tuesday", "recipe_title": "Cook: Salad", "prep_time": "10 mins"}], "shopping_list": ["Pasta", "Lettuce"]}`),
+		chefReply(`{"plan": [{"day": "Monday", "recipe_title": "Cook: Salad"}, {"day": "Tuesday", "recipe_title": "Cook: Pasta"}], "shopping_list": ["1 head Lettuce", "500g Pasta", "4 Tomatoes"]}`),
+	}}
+	reviewer := &llmtest.MockTextGenerator{ResponseChain: []llm.ContentResponse{
+		toolCall("submit_revised_plan", map[string]any{"plan": []any{
+			map[string]any{"day": "Monday", "recipe_title": "Cook: Salad", "note": "Swapped"},
+			map[string]any{"day": "Tuesday", "recipe_title": "Cook: Pasta", "note": "Swapped"},
+		}}),
+	}}
+	chat, db := newE2EChat(t, analyst, chef, reviewer,
+		value.Recipe{ID: "pasta", Title: "Pasta", Ingredients: []string{"Pasta", "Tomato"}, UpdatedAt: "2023-01-01T00:00:00Z"},
+		value.Recipe{ID: "salad", Title: "Salad", Ingredients: []string{"Lettuce", "Tomato"}, UpdatedAt: "2023-01-01T00:00:00Z"},
+	)
+
+	// 1. /plan turns the thinking message into a draft with its buttons
+	chat.say("/plan something light")
+	draft := chat.last()
+	if !draft.Edit || !strings.Contains(draft.Text, "*Monday*: Pasta") || !strings.Contains(draft.Text, "*Tuesday*: Salad") {
+		t.Fatalf("draft message = %+v", draft)
+	}
+	for _, label := range []string{"Confirm", "Adjust", "Start Over"} {
+		if _, ok := draft.Button(label); !ok {
+			t.Errorf("draft has no %q button", label)
+		}
+	}
+
+	// 2. Adjust clears the draft's buttons and asks for feedback
+	chat.press(draft.MessageID, "Adjust")
+	if cleared, _ := chat.fake.Current(e2eUserID, draft.MessageID); cleared.Keyboard == nil || len(cleared.Keyboard.InlineKeyboard) != 0 {
+		t.Errorf("draft keyboard after Adjust = %+v, want empty", cleared.Keyboard)
+	}
+	if prompt := chat.last(); prompt.Edit || prompt.Text != adjustmentPrompt {
+		t.Errorf("adjustment prompt = %+v", prompt)
+	}
+
+	// 3. Feedback goes to the reviewer and the revised draft waits for more
+	chat.say("swap monday and tuesday")
+	revised := chat.last()
+	if !revised.Edit || !strings.Contains(revised.Text, "Send more feedback") {
+		t.Fatalf("revised message = %+v", revised)
+	}
+	if !strings.Contains(revised.Text, "*Monday*: Salad") || !strings.Contains(revised.Text, "*Tuesday*: Pasta") {
+		t.Errorf("revised plan did not swap the days:\n%s", revised.Text)
+	}
+
+	// 4. Done ends the conversation and brings the draft buttons back
+	chat.press(revised.MessageID, "Done")
+	done := chat.last()
+	if _, ok := done.Button("Confirm"); !ok || done.MessageID != revised.MessageID {
+		t.Fatalf("message after Done = %+v", done)
+	}
+	if _, ok := done.Button("Undo"); !ok {
+		t.Error("revised draft has no Undo button")
+	}
+
+	// 5. Confirm finalizes the plan and sends the shopping list
+	chat.press(done.MessageID, "Confirm")
+	sent := chat.fake.Sent()
+	confirmed, list := sent[len(sent)-2], sent[len(sent)-1]
+	if !confirmed.Edit || !strings.HasPrefix(confirmed.Text, "✅ *Plan Confirmed!*") {
+		t.Errorf("confirmed message = %+v", confirmed)
+	}
+	if list.Edit || !strings.Contains(list.Text, "• 500g Pasta") || !strings.Contains(list.Text, "• 4 Tomatoes") {
+		t.Errorf("shopping list message = %+v", list)
+	}
+
+	plan, err := planner.NewPlanRepository(db.SQL).GetCurrentByUserAndWeek(ctx, "42", planner.GetNextMonday(time.Now()))
+	if err != nil || plan == nil {
+		t.Fatalf("GetCurrentByUserAndWeek() = %v, %v", plan, err)
+	}
+	if plan.Status != planner.StatusFinal || plan.Plan[0].RecipeID != "salad" {
+		t.Errorf("stored plan status = %s, Monday = %q", plan.Status, plan.Plan[0].RecipeID)
+	}
+	stored, err := shopping.NewRepository(db.SQL).GetByMealPlanID(ctx, plan.ID)
+	if err != nil || stored == nil || !slices.Equal(stored.Items, []string{"1 head Lettuce", "500g Pasta", "4 Tomatoes"}) {
+		t.Errorf("stored shopping list = %+v, %v", stored, err)
+	}
+
+	// 6. Confirming ended the conversation, so later messages are not feedback
+	chat.say("what's for dinner?")
+	if last := chat.last(); strings.Contains(last.Text, "Revising") {
+		t.Errorf("message after Confirm was taken as feedback: %+v", last)
+	}
+
+	// Every press was answered, and every button carried a signature for this chat
+	if got := len(chat.fake.Callbacks()); got != 3 {
+		t.Errorf("answered %d button presses, want 3", got)
+	}
+	for _, msg := range sent {
+		if msg.Keyboard == nil {
+			continue
+		}
+		for _, row := range msg.Keyboard.InlineKeyboard {
+			for _, button := range row {
+				if button.CallbackData == nil {
+					continue
+				}
+				if _, ok := chat.bot.signer.Verify(e2eUserID, *button.CallbackData); !ok {
+					t.Errorf("button %q sent unsigned: %q", button.Text, *button.CallbackData)
+				}
+			}
+		}
+	}
+}
+
+func TestEndToEndRedoKeepsConfirmedPlan(t *testing.T) {
+	ctx := context.Background()
+	analyst := &llmtest.MockTextGenerator{ResponseChain: []llm.ContentResponse{
+		toolCall("search_recipes_semantic", map[string]any{"query": "something new"}),
+		toolCall("submit_meal_proposal", map[string]any{"planned_meals": []any{
+			map[string]any{"day": "Monday", "action": "Cook", "recipe_title": "Salad", "note": "Light"},
+		}}),
+	}}
+	chef := &llmtest.MockTextGenerator{ResponseChain: []llm.ContentResponse{
+		chefReply(`{"plan": [{"day": "Monday", "recipe_title": "Cook: Salad"}], "shopping_list": ["Lettuce"]}`),
+		chefReply(`{"plan": [{"day": "Monday", "recipe_title": "Cook: Salad"}], "shopping_list": ["1 head Lettuce"]}`),
+	}}
+	chat, db := newE2EChat(t, analyst, chef, &llmtest.MockTextGenerator{ShouldError: true},
+		value.Recipe{ID: "pasta", Title: "Pasta", UpdatedAt: "2023-01-01T00:00:00Z"},
+		value.Recipe{ID: "salad", Title: "Salad", UpdatedAt: "2023-01-01T00:00:00Z"},
+	)
+	planRepo := planner.NewPlanRepository(db.SQL)
+	nextMonday := planner.GetNextMonday(time.Now())
+	confirmedID, err := planRepo.SaveVersion(ctx, "42", &planner.MealPlan{
+		WeekStart: nextMonday,
+		Status:    planner.StatusFinal,
+		Plan:      []planner.DayPlan{{Day: "Monday", RecipeID: "pasta", RecipeTitle: "Cook: Pasta"}},
+	}, planner.VersionAuthorAnalyst, "pasta week")
+	if err != nil {
+		t.Fatalf("SaveVersion() error = %v", err)
+	}
+
+	// Redoing the confirmed week drafts a new plan without touching the confirmed one
+	chat.say("/plan something new")
+	chat.press(chat.last().MessageID, "Redo Next Week")
+	draft := chat.last()
+	if _, ok := draft.Button("Confirm"); !ok || !strings.Contains(draft.Text, "*Monday*: Salad") {
+		t.Fatalf("redo message = %+v", draft)
+	}
+
+	confirmed, err := planRepo.GetFinalByUserAndWeek(ctx, "42", nextMonday)
+	if err != nil || confirmed == nil || confirmed.ID != confirmedID || confirmed.Plan[0].RecipeID != "pasta" {
+		t.Fatalf("confirmed plan during redo = %+v, %v", confirmed, err)
+	}
+	if meals, err := chat.bot.finalPlanDay(ctx, "42", nextMonday); err != nil || len(meals) != 1 || meals[0].RecipeID != "pasta" {
+		t.Errorf("reminder meals during redo = %+v, %v", meals, err)
+	}
+	current, err := planRepo.GetCurrentByUserAndWeek(ctx, "42", nextMonday)
+	if err != nil || current == nil || current.ID == confirmedID || current.Status != planner.StatusDraft {
+		t.Fatalf("current plan during redo = %+v, %v", current, err)
+	}
+
+	// Confirming the new draft retires the plan it replaces
+	chat.press(draft.MessageID, "Confirm")
+	confirmed, err = planRepo.GetFinalByUserAndWeek(ctx, "42", nextMonday)
+	if err != nil || confirmed == nil || confirmed.ID != current.ID {
+		t.Fatalf("confirmed plan after redo = %+v, %v", confirmed, err)
+	}
+	if old, err := planRepo.GetByIDForUser(ctx, confirmedID, "42"); err != nil || old.Status != planner.StatusReplaced {
+		t.Errorf("replaced plan = %+v, %v", old, err)
+	}
+	if list, err := shopping.NewRepository(db.SQL).GetByUserAndWeek(ctx, "42", nextMonday); err != nil || list == nil || list.MealPlanID != current.ID {
+		t.Errorf("week's shopping list = %+v, %v", list, err)
+	}
+}
+
+func TestEndToEndFeedbackIgnoresConfirmedPlan(t *testing.T) {
+	ctx := context.Background()
+	chat, db := newE2EChat(t, &llmtest.MockTextGenerator{ShouldError: true}, &llmtest.MockTextGenerator{ShouldError: true}, &llmtest.MockTextGenerator{ShouldError: true})
+	plan := &planner.MealPlan{
+		WeekStart: planner.GetNextMonday(time.Now()),
+		Status:    planner.StatusFinal,
+		Plan:      []planner.DayPlan{{Day: "Monday", RecipeID: "pasta", RecipeTitle: "Cook: Pasta"}},
+	}
+	if _, err := planner.NewPlanRepository(db.SQL).SaveVersion(ctx, "42", plan, planner.VersionAuthorAnalyst, "pasta week"); err != nil {
+		t.Fatalf("SaveVersion() error = %v", err)
+	}
+	// An adjustment opened before the plan was confirmed elsewhere
+	if _, err := chat.bot.openAdjustSession(ctx, "42", plan); err != nil {
+		t.Fatalf("openAdjustSession() error = %v", err)
+	}
+
+	chat.say("what's for dinner?")
+	for _, msg := range chat.fake.Sent() {
+		if strings.Contains(msg.Text, "Revising") {
+			t.Errorf("confirmed plan took feedback: %+v", msg)
+		}
+	}
+	if session, err := chat.bot.sessionRepo.GetActive(ctx, "42", time.Now(), SessionTypeAdjustPlan); err != nil || (session != nil && session.SessionType == SessionTypeAdjustPlan) {
+		t.Errorf("adjustment session still active: %+v, %v", session, err)
+	}
+	stored, _ := planner.NewPlanRepository(db.SQL).GetByIDForUser(ctx, plan.ID, "42")
+	if stored == nil || stored.Status != planner.StatusFinal {
+		t.Errorf("stored plan = %+v, want it still FINAL", stored)
+	}
+}
+
+func TestEndToEndRejectsStrangersAndForgedButtons(t *testing.T) {
+	chat, _ := newE2EChat(t, &llmtest.MockTextGenerator{ShouldError: true}, &llmtest.MockTextGenerator{ShouldError: true}, &llmtest.MockTextGenerator{ShouldError: true})
+
+	chat.post(tgbotapi.Update{Message: &tgbotapi.Message{
+		MessageID: 1,
+		From:      &tgbotapi.User{ID: 7},
+		Chat:      &tgbotapi.Chat{ID: 7},
+		Text:      "/plan",
+	}})
+	if sent := chat.fake.Sent(); len(sent) != 0 {
+		t.Errorf("bot answered a stranger: %+v", sent)
+	}
+
+	chat.post(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: e2eUserID},
+		Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: e2eUserID}},
+		Data:    "confirm|1",
+	}})
+	if sent := chat.fake.Sent(); len(sent) != 0 {
+		t.Errorf("forged button changed messages: %+v", sent)
+	}
+	callbacks := chat.fake.Callbacks()
+	if len(callbacks) != 1 || !strings.Contains(callbacks[0].Text, "no longer valid") {
+		t.Errorf("forged button answer = %+v", callbacks)
+	}
+}
+
+func TestEndToEndSearchAddKeepsConfirmedPlan(t *testing.T) {
+	ctx := context.Background()
+	chef := &llmtest.MockTextGenerator{ResponseChain: []llm.ContentResponse{
+		chefReply(`{"plan": [{"day": "Sunday (Dinner)", "recipe_title": "Cook: Salad"}], "shopping_list": ["1 head Lettuce"]}`),
+	}}
+	chat, db := newE2EChat(t, &llmtest.MockTextGenerator{ShouldError: true}, chef, &llmtest.MockTextGenerator{ShouldError: true},
+		value.Recipe{ID: "salad", Title: "Salad", UpdatedAt: "2023-01-01T00:00:00Z"},
+	)
+	planRepo := planner.NewPlanRepository(db.SQL)
+	shoppingRepo := shopping.NewRepository(db.SQL)
+	thisMonday := planner.GetNextMonday(time.Now()).AddDate(0, 0, -7)
+	plan := &planner.MealPlan{
+		WeekStart: thisMonday,
+		Status:    planner.StatusFinal,
+		Plan:      []planner.DayPlan{{Day: "Sunday (Dinner)", RecipeID: "pasta", RecipeTitle: "Cook: Pasta"}},
+	}
+	if _, err := planRepo.SaveVersion(ctx, "42", plan, planner.VersionAuthorAnalyst, "pasta week"); err != nil {
+		t.Fatalf("SaveVersion() error = %v", err)
+	}
+	if _, err := shoppingRepo.Save(ctx, &shopping.ShoppingList{UserID: "42", MealPlanID: plan.ID, Items: []string{"Spaghetti"}}); err != nil {
+		t.Fatalf("Save shopping list error = %v", err)
+	}
+	// A draft regenerated for the week must not be the plan search adds to
+	if _, err := planRepo.SaveVersion(ctx, "42", &planner.MealPlan{
+		WeekStart: thisMonday,
+		Status:    planner.StatusDraft,
+		Plan:      []planner.DayPlan{{Day: "Sunday (Dinner)", RecipeID: "pasta", RecipeTitle: "Cook: Pasta"}},
+	}, planner.VersionAuthorAnalyst, "redo"); err != nil {
+		t.Fatalf("SaveVersion() error = %v", err)
+	}
+
+	chat.say("/search salad")
+	results := chat.last()
+	chat.press(results.MessageID, "➕ 1.")
+	chat.press(results.MessageID, "Sun D")
+	if got := chat.toast(); got != "Added to Sunday (Dinner)" {
+		t.Errorf("search add answer = %q, want %q", got, "Added to Sunday (Dinner)")
+	}
+
+	stored, err := planRepo.GetByIDForUser(ctx, plan.ID, "42")
+	if err != nil || stored.Status != planner.StatusFinal || stored.Plan[0].RecipeID != "salad" {
+		t.Fatalf("this week's plan = %+v, %v", stored, err)
+	}
+	list, err := shoppingRepo.GetByMealPlanID(ctx, plan.ID)
+	if err != nil || list == nil || !slices.Equal(list.Items, []string{"1 head Lettuce"}) {
+		t.Errorf("shopping list = %+v, %v", list, err)
+	}
+	if msg := chat.last(); !strings.Contains(msg.Text, "Lettuce") {
+		t.Errorf("last message = %q, want the new shopping list", msg.Text)
+	}
+}
+
+func TestEndToEndPolling(t *testing.T) {
+	chat, _ := newE2EChat(t, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{})
+	chat.fake.Updates = make(chan tgbotapi.Update)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		chat.bot.StartPolling(ctx)
+		close(stopped)
+	}()
+
+	chat.fake.Updates <- tgbotapi.Update{UpdateID: 1, Message: &tgbotapi.Message{
+		MessageID: 1,
+		From:      &tgbotapi.User{ID: e2eUserID},
+		Chat:      &tgbotapi.Chat{ID: e2eUserID},
+		Text:      "/help",
+	}}
+	cancel()
+	<-stopped
+	chat.bot.Wait()
+
+	if msg := chat.last(); !strings.Contains(msg.Text, "I plan your family's meals") {
+		t.Errorf("reply to a polled /help = %q, want the help message", msg.Text)
+	}
+}
+
+func TestActiveSessionIsScopedByType(t *testing.T) {
+	ctx := context.Background()
+	chat, _ := newE2EChat(t, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{})
+	repo := chat.bot.sessionRepo
+
+	adjustID, err := repo.Create(ctx, "42", SessionTypeAdjustPlan, StateAwaitingFeedback, SessionContextData{PlanID: 1}, adjustSessionTTL)
+	if err != nil {
+		t.Fatalf("Create(adjust) error = %v", err)
+	}
+	// Cooking starts later and lasts longer, but must not hide the adjustment
+	if _, err := repo.Create(ctx, "42", SessionTypeCooking, StateCooking, SessionContextData{RecipeID: "pasta"}, cookingTTL); err != nil {
+		t.Fatalf("Create(cooking) error = %v", err)
+	}
+
+	session, err := repo.GetActive(ctx, "42", time.Now(), SessionTypeAdjustPlan, SessionTypeEditClip, SessionTypeRateRecipe)
+	if err != nil || session == nil || session.ID != adjustID {
+		t.Errorf("GetActive(text replies) = %+v, %v; want the adjustment session %d", session, err, adjustID)
+	}
+	if session, err := repo.GetActive(ctx, "42", time.Now(), SessionTypeSearch); err != nil || session != nil {
+		t.Errorf("GetActive(search) = %+v, %v; want none", session, err)
+	}
+}
+
+func TestEndToEndCookingTimerIsStored(t *testing.T) {
+	ctx := context.Background()
+	chat, _ := newE2EChat(t, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{},
+		value.Recipe{ID: "pasta", Title: "Pasta", Steps: []string{"Boil the pasta for 20 minutes."}, UpdatedAt: "2023-01-01T00:00:00Z"},
+	)
+	rec, err := chat.bot.recipeRepo.Get(ctx, "pasta")
+	if err != nil {
+		t.Fatalf("Get(pasta) error = %v", err)
+	}
+	sessionID, err := chat.bot.sessionRepo.Create(ctx, "42", SessionTypeCooking, StateCooking, SessionContextData{RecipeID: "pasta", Step: 1}, cookingTTL)
+	if err != nil {
+		t.Fatalf("Create(cooking) error = %v", err)
+	}
+	page := tgbotapi.NewMessage(e2eUserID, chat.bot.formatCookingPage(rec, 1))
+	page.ReplyMarkup = cookingKeyboard(sessionID, rec, 1)
+	sent, err := chat.bot.api.Send(page)
+	if err != nil {
+		t.Fatalf("Send(cooking page) error = %v", err)
+	}
+
+	chat.press(sent.MessageID, "20 min")
+	if got := chat.last().Text; !strings.Contains(got, "Timer started: 20 min") {
+		t.Fatalf("after pressing the timer = %q, want it started", got)
+	}
+
+	// The timer lives in the database, so it rings from whichever process checks it
+	sentBefore := len(chat.fake.Sent())
+	chat.bot.ringCookingTimers(ctx, time.Now().Add(19*time.Minute))
+	if len(chat.fake.Sent()) != sentBefore {
+		t.Fatalf("timer rang early: %q", chat.last().Text)
+	}
+	chat.bot.ringCookingTimers(ctx, time.Now().Add(21*time.Minute))
+	if got := chat.last().Text; !strings.Contains(got, "Time's up!") || !strings.Contains(got, "step 1 of 1") {
+		t.Errorf("rung timer = %q, want the step reminder", got)
+	}
+	chat.bot.ringCookingTimers(ctx, time.Now().Add(22*time.Minute))
+	if len(chat.fake.Sent()) != sentBefore+1 {
+		t.Errorf("timer rang %d times, want once", len(chat.fake.Sent())-sentBefore)
+	}
+}
+
+func TestEndToEndHistoryRestoreOnlyChangesDrafts(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		confirm   bool
+		wantToast string
+		wantTitle string
+		wantStat  planner.PlanStatus
+	}{
+		{name: "Draft", wantToast: "⏪ Restored", wantTitle: "Cook: Pasta", wantStat: planner.StatusDraft},
+		{name: "Confirmed", confirm: true, wantToast: "Only draft plans can be changed", wantTitle: "Cook: Salad", wantStat: planner.StatusFinal},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			chat, db := newE2EChat(t, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{})
+			planRepo := planner.NewPlanRepository(db.SQL)
+
+			plan := &planner.MealPlan{
+				WeekStart: planner.GetNextMonday(time.Now()),
+				Status:    planner.StatusDraft,
+				Plan:      []planner.DayPlan{{Day: "Monday", RecipeID: "pasta", RecipeTitle: "Cook: Pasta"}},
+			}
+			if _, err := planRepo.SaveVersion(ctx, "42", plan, planner.VersionAuthorAnalyst, "pasta week"); err != nil {
+				t.Fatalf("SaveVersion() error = %v", err)
+			}
+			before := *plan
+			revised := *plan
+			revised.Plan = []planner.DayPlan{{Day: "Monday", RecipeID: "salad", RecipeTitle: "Cook: Salad"}}
+			if _, err := planRepo.SaveVersion(ctx, "42", &revised, planner.VersionAuthorPlanReviewer, "salad instead"); err != nil {
+				t.Fatalf("SaveVersion() error = %v", err)
+			}
+			if err := chat.bot.auditRepo.LogInteraction(ctx, "42", plan.ID, audit.ActionRevisePlan, "pasta week", "salad instead", &before, &revised); err != nil {
+				t.Fatalf("LogInteraction() error = %v", err)
+			}
+			if tc.confirm {
+				if err := planRepo.Confirm(ctx, "42", plan.ID); err != nil {
+					t.Fatalf("Confirm() error = %v", err)
+				}
+			}
+
+			chat.say("/history")
+			chat.press(chat.last().MessageID, "0")
+			if got := chat.toast(); got != tc.wantToast {
+				t.Errorf("restore answer = %q, want %q", got, tc.wantToast)
+			}
+
+			stored, err := planRepo.GetByIDForUser(ctx, plan.ID, "42")
+			if err != nil || stored.Status != tc.wantStat || stored.Plan[0].RecipeTitle != tc.wantTitle {
+				t.Errorf("stored plan = %+v, %v; want %s with %q", stored, err, tc.wantStat, tc.wantTitle)
+			}
+		})
+	}
+}
+
+func TestEndToEndCookingNeedsConfirmedPlan(t *testing.T) {
+	ctx := context.Background()
+	chat, db := newE2EChat(t, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{}, &llmtest.MockTextGenerator{},
+		value.Recipe{ID: "pasta", Title: "Pasta", Steps: []string{"Boil the pasta."}, UpdatedAt: "2023-01-01T00:00:00Z"},
+	)
+	planRepo := planner.NewPlanRepository(db.SQL)
+	nextMonday := planner.GetNextMonday(time.Now())
+
+	var pages []int
+	for _, request := range []string{"first", "second"} {
+		plan := &planner.MealPlan{
+			WeekStart: nextMonday,
+			Status:    planner.StatusDraft,
+			Plan:      []planner.DayPlan{{Day: "Monday", RecipeID: "pasta", RecipeTitle: "Cook: Pasta"}},
+		}
+		if _, err := planRepo.SaveVersion(ctx, "42", plan, planner.VersionAuthorAnalyst, request); err != nil {
+			t.Fatalf("SaveVersion() error = %v", err)
+		}
+		if err := planRepo.Confirm(ctx, "42", plan.ID); err != nil {
+			t.Fatalf("Confirm() error = %v", err)
+		}
+		reply := tgbotapi.NewMessage(e2eUserID, "✅ Plan Confirmed!")
+		reply.ReplyMarkup = planCookingKeyboard(plan)
+		sent, err := chat.bot.api.Send(reply)
+		if err != nil {
+			t.Fatalf("Send(confirmed plan) error = %v", err)
+		}
+		pages = append(pages, sent.MessageID)
+	}
+
+	// The first plan was replaced by the second
+	sentBefore := len(chat.fake.Sent())
+	chat.press(pages[0], "Start cooking Monday")
+	if got := chat.toast(); got != "Only the confirmed plan can be cooked from" {
+		t.Errorf("cooking a replaced plan answer = %q", got)
+	}
+	if len(chat.fake.Sent()) != sentBefore {
+		t.Errorf("cooking a replaced plan sent %q", chat.last().Text)
+	}
+
+	chat.press(pages[1], "Start cooking Monday")
+	if got := chat.last().Text; !strings.Contains(got, "*Pasta*") {
+		t.Errorf("cooking the confirmed plan sent %q, want the recipe", got)
+	}
+}
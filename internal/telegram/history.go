@@ -0,0 +1,265 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/audit"
+	"ai-meal-planner/internal/planner"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// historyCommand lists the changes made to the current week's plan.
+const historyCommand = "/history"
+
+// historyLimit is how many of the latest changes /history offers to restore.
+const historyLimit = 8
+
+// undoLookback is how many audit entries Undo walks back through to pair undos with changes.
+const undoLookback = 50
+
+// Callback sides of an audit entry: the plan before or after the change.
+const (
+	restoreBefore = "before"
+	restoreAfter  = "after"
+)
+
+// handleHistoryCommand lists the recent changes of the current week's plan with a
+// button to restore the plan as it was after each one, or before the first.
+func (b *Bot) handleHistoryCommand(ctx context.Context, msg *tgbotapi.Message) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+
+	plan, err := b.currentWeekPlan(ctx, userID, time.Now())
+	if err != nil || plan == nil {
+		if err != nil {
+			log.Printf("Error loading plan for history: %v", err)
+		}
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🗓️ There is no plan for this week or next week yet."))
+		return
+	}
+
+	entries, err := b.auditRepo.ListByPlan(ctx, plan.ID, historyLimit)
+	if err != nil {
+		log.Printf("Error loading plan history: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not load the plan history."))
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, formatPlanHistory(plan, entries))
+	reply.ParseMode = "Markdown"
+	if len(entries) > 0 {
+		keyboard := historyKeyboard(entries)
+		reply.ReplyMarkup = keyboard
+	}
+	b.api.Send(reply)
+}
+
+// currentWeekPlan returns the plan being drafted for next week, or else this week's plan.
+func (b *Bot) currentWeekPlan(ctx context.Context, userID string, now time.Time) (*planner.MealPlan, error) {
+	nextMonday := planner.GetNextMonday(now)
+	plan, err := b.planRepo.GetCurrentByUserAndWeek(ctx, userID, nextMonday)
+	if err != nil || plan != nil {
+		return plan, err
+	}
+	return b.planRepo.GetCurrentByUserAndWeek(ctx, userID, nextMonday.AddDate(0, 0, -7))
+}
+
+// formatPlanHistory numbers the changes oldest first; 0 is the plan before the first listed change.
+func formatPlanHistory(plan *planner.MealPlan, entries []audit.Entry) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🕘 *Plan history* — week of %s (now v%d)\n\n", plan.WeekStart.Format("2006-01-02"), plan.Version))
+	if len(entries) == 0 {
+		sb.WriteString("_No changes yet._")
+		return sb.String()
+	}
+
+	sb.WriteString("*0.* Before these changes\n")
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		sb.WriteString(fmt.Sprintf("*%d.* %s %s", len(entries)-i, e.CreatedAt.Local().Format("02/01 15:04"), historyActionLabel(e.ActionType)))
+		if e.UserFeedback != "" {
+			sb.WriteString(fmt.Sprintf(" — _%s_", escapeMarkdown(e.UserFeedback)))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n_Tap a number to make that state the current draft._")
+	return sb.String()
+}
+
+func historyActionLabel(action string) string {
+	switch action {
+	case audit.ActionRevisePlan:
+		return "✏️ Revised"
+	case audit.ActionRestorePlan:
+		return "⏪ Restored"
+	case audit.ActionUndoChange:
+		return "↩️ Undone"
+	case audit.ActionSwapDay:
+		return "🔀 Swapped"
+	case audit.ActionToggleLock:
+		return "🔒 Locks changed"
+	default:
+		return action
+	}
+}
+
+// historyKeyboard has one button per numbered state in formatPlanHistory, five per row.
+func historyKeyboard(entries []audit.Entry) tgbotapi.InlineKeyboardMarkup {
+	oldest := entries[len(entries)-1]
+	buttons := []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData("0", fmt.Sprintf("restore|%d|%s", oldest.ID, restoreBefore)),
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			strconv.Itoa(len(entries)-i),
+			fmt.Sprintf("restore|%d|%s", entries[i].ID, restoreAfter),
+		))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for start := 0; start < len(buttons); start += 5 {
+		rows = append(rows, buttons[start:min(start+5, len(buttons))])
+	}
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// handleHistoryRestore makes a state from /history the current draft. Format: "restore|entryID|side".
+func (b *Bot) handleHistoryRestore(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	entryID, _ := strconv.ParseInt(parts[1], 10, 64)
+
+	entry, err := b.auditRepo.GetByID(ctx, entryID)
+	if err != nil || entry == nil || entry.UserID != userID {
+		log.Printf("Error retrieving history entry %d: %v", entryID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not find that change.")
+		return
+	}
+
+	raw := entry.NewState
+	if parts[2] == restoreBefore {
+		raw = entry.PreviousState
+	}
+	b.restorePlanState(ctx, query, userID, entry.PlanID, raw, audit.ActionRestorePlan, "")
+}
+
+// handleUndo reverts the latest change of a plan. Format: "undo|planID".
+func (b *Bot) handleUndo(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	planID, _ := strconv.ParseInt(parts[1], 10, 64)
+
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || plan == nil {
+		log.Printf("Error retrieving plan for undo: %v", err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not retrieve plan.")
+		return
+	}
+
+	entries, err := b.auditRepo.ListByPlan(ctx, planID, undoLookback)
+	if err != nil {
+		log.Printf("Error loading changes for undo: %v", err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not load the plan history.")
+		return
+	}
+
+	entry := undoEntry(entries, plan.Version)
+	if entry == nil {
+		b.answerCallback(query, "Nothing to undo")
+		return
+	}
+	b.restorePlanState(ctx, query, userID, planID, entry.PreviousState, audit.ActionUndoChange, entry.UserFeedback)
+}
+
+// undoEntry picks the change Undo should revert from entries listed newest first:
+// each earlier undo cancels the change before it. It returns nil when nothing is left
+// or the plan was changed outside the log, e.g. regenerated, since the latest entry.
+func undoEntry(entries []audit.Entry, currentVersion int) *audit.Entry {
+	if len(entries) == 0 {
+		return nil
+	}
+	latest, err := decodePlanState(entries[0].NewState)
+	if err != nil || latest.Version != currentVersion {
+		return nil
+	}
+
+	pending := 0
+	for i, e := range entries {
+		switch e.ActionType {
+		case audit.ActionUndoChange:
+			pending++
+		case audit.ActionRevisePlan, audit.ActionRestorePlan, audit.ActionSwapDay, audit.ActionToggleLock:
+			if pending > 0 {
+				pending--
+				continue
+			}
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// restorePlanState saves a recorded plan state as the current draft of planID, logs the
+// change under action with feedback and shows the draft in place of the callback's message.
+// Plans that are no longer drafts are left as they are.
+func (b *Bot) restorePlanState(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, planID int64, raw json.RawMessage, action, feedback string) {
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+
+	current, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || current == nil {
+		log.Printf("Error retrieving plan %d to restore: %v", planID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not retrieve plan.")
+		return
+	}
+	// Confirmed plans are followed as they are; restoring would turn them back into drafts
+	if current.Status != planner.StatusDraft {
+		b.answerCallback(query, "Only draft plans can be changed")
+		return
+	}
+	state, err := decodePlanState(raw)
+	if err != nil {
+		log.Printf("Error decoding plan state: %v", err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* That change has no saved plan.")
+		return
+	}
+
+	var restored *planner.MealPlan
+	if state.Version > 0 {
+		restored, err = b.planRepo.RestoreVersion(ctx, userID, planID, state.Version)
+	} else {
+		// States logged before plans were versioned are saved as a new version instead
+		state.ID = planID
+		state.Status = planner.StatusDraft
+		state.ShoppingList = nil
+		_, err = b.planRepo.SaveVersion(ctx, userID, state, planner.VersionAuthorRestore, "Restored from history")
+		restored = state
+	}
+	if err != nil {
+		log.Printf("Error restoring plan %d: %v", planID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not restore the plan.")
+		return
+	}
+
+	_ = b.auditRepo.LogInteraction(ctx, userID, planID, action, current.OriginalRequest, feedback, current, restored)
+	b.answerCallback(query, historyActionLabel(action))
+	b.sendDraftPlan(chatID, messageID, restored)
+}
+
+func decodePlanState(raw json.RawMessage) (*planner.MealPlan, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty plan state")
+	}
+	plan := &planner.MealPlan{}
+	if err := json.Unmarshal(raw, plan); err != nil {
+		return nil, fmt.Errorf("failed to decode plan state: %w", err)
+	}
+	if len(plan.Plan) == 0 {
+		return nil, fmt.Errorf("plan state has no days")
+	}
+	return plan, nil
+}
@@ -0,0 +1,177 @@
+package telegram
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/llm"
+	"ai-meal-planner/internal/shared"
+)
+
+//go:embed intent_prompt.md
+var intentPrompt string
+
+// IntentKind is what a message asks the bot to do.
+type IntentKind string
+
+const (
+	IntentPlan           IntentKind = "plan"
+	IntentAdjust         IntentKind = "adjust"
+	IntentQueryPlan      IntentKind = "query_plan"
+	IntentShoppingAdd    IntentKind = "shopping_add"
+	IntentShoppingRemove IntentKind = "shopping_remove"
+	IntentShoppingShow   IntentKind = "shopping_show"
+	IntentSearch         IntentKind = "search"
+	IntentRandom         IntentKind = "random"
+	IntentClip           IntentKind = "clip"
+	IntentHelp           IntentKind = "help"
+)
+
+// Intent is a routed message with the arguments its handler needs.
+type Intent struct {
+	Kind    IntentKind `json:"intent"`
+	Request string     `json:"request"` // Planning request, adjustment feedback, search query or clip URL
+	Day     string     `json:"day"`     // "today", "tomorrow", a weekday name, or empty for the whole week
+	Items   []string   `json:"items"`   // Shopping list items to add or remove
+}
+
+// Expensive reports whether the intent runs a planning agent, so free text asks
+// for confirmation before it starts.
+func (i Intent) Expensive() bool {
+	return i.Kind == IntentPlan || i.Kind == IntentAdjust
+}
+
+// Commands that route without the classifier. /metrics, /repeat, /history and
+// /reminders are handled before routing.
+const (
+	planCommand     = "/plan"
+	adjustCommand   = "/adjust"
+	todayCommand    = "/today"
+	tomorrowCommand = "/tomorrow"
+	weekCommand     = "/week"
+	listCommand     = "/list"
+	addCommand      = "/add"
+	removeCommand   = "/remove"
+	searchCommand   = "/search"
+	randomCommand   = "/random"
+	helpCommand     = "/help"
+	startCommand    = "/start"
+)
+
+// parseCommandIntent routes links and slash commands deterministically. It reports
+// false for free text, which needs the classifier.
+func parseCommandIntent(text string) (Intent, bool) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://") {
+		return Intent{Kind: IntentClip, Request: text}, true
+	}
+	if !strings.HasPrefix(text, "/") {
+		return Intent{}, false
+	}
+
+	command, args, _ := strings.Cut(text, " ")
+	// Commands sent from a group menu carry the bot name: /plan@MealBot
+	command, _, _ = strings.Cut(strings.ToLower(command), "@")
+	args = strings.TrimSpace(args)
+
+	switch command {
+	case planCommand:
+		return Intent{Kind: IntentPlan, Request: args}, true
+	case adjustCommand:
+		return Intent{Kind: IntentAdjust, Request: args}, true
+	case todayCommand:
+		return Intent{Kind: IntentQueryPlan, Day: "today"}, true
+	case tomorrowCommand:
+		return Intent{Kind: IntentQueryPlan, Day: "tomorrow"}, true
+	case weekCommand:
+		return Intent{Kind: IntentQueryPlan}, true
+	case listCommand:
+		return Intent{Kind: IntentShoppingShow}, true
+	case addCommand:
+		return Intent{Kind: IntentShoppingAdd, Items: splitItems(args)}, true
+	case removeCommand:
+		return Intent{Kind: IntentShoppingRemove, Items: splitItems(args)}, true
+	case searchCommand:
+		return Intent{Kind: IntentSearch, Request: args}, true
+	case randomCommand:
+		return Intent{Kind: IntentRandom}, true
+	default:
+		return Intent{Kind: IntentHelp}, true
+	}
+}
+
+// splitItems reads a comma or newline separated list of groceries.
+func splitItems(text string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+type IntentResult struct {
+	Intent Intent
+	Meta   shared.AgentMeta
+}
+
+// IntentClassifier routes free text with a small, cheap model.
+type IntentClassifier struct {
+	textGen llm.TextGenerator
+}
+
+func NewIntentClassifier(textGen llm.TextGenerator) *IntentClassifier {
+	return &IntentClassifier{textGen: textGen}
+}
+
+// Classify asks the model what the message wants and validates the answer.
+func (c *IntentClassifier) Classify(ctx context.Context, text string) (IntentResult, error) {
+	if c == nil || c.textGen == nil {
+		return IntentResult{}, fmt.Errorf("intent classifier text generator is not configured")
+	}
+
+	start := time.Now()
+	resp, err := c.textGen.GenerateContent(ctx, llm.Conversation{
+		{Role: "system", Content: intentPrompt},
+		{Role: "user", Content: text},
+	}, llm.NoTools)
+	if err != nil {
+		return IntentResult{}, fmt.Errorf("failed to get intent classifier response: %w", err)
+	}
+	meta := shared.AgentMeta{
+		AgentName: "IntentClassifier",
+		Usage:     resp.Usage,
+		Latency:   time.Since(start),
+	}
+
+	intent, err := parseIntentResponse(resp.Message.Content)
+	if err != nil {
+		return IntentResult{Meta: meta}, err
+	}
+	return IntentResult{Intent: intent, Meta: meta}, nil
+}
+
+func parseIntentResponse(content string) (Intent, error) {
+	var intent Intent
+	if err := json.Unmarshal([]byte(llm.CleanJSON(content)), &intent); err != nil {
+		return Intent{}, fmt.Errorf("failed to parse intent classifier response: %w. Response: %s", err, content)
+	}
+
+	intent.Day = strings.ToLower(strings.TrimSpace(intent.Day))
+	intent.Request = strings.TrimSpace(intent.Request)
+	switch intent.Kind {
+	case IntentPlan, IntentAdjust, IntentQueryPlan, IntentShoppingShow, IntentSearch, IntentRandom, IntentHelp:
+	case IntentShoppingAdd, IntentShoppingRemove:
+		if len(intent.Items) == 0 {
+			return Intent{}, fmt.Errorf("intent %s has no items", intent.Kind)
+		}
+	default:
+		return Intent{}, fmt.Errorf("unknown intent %q", intent.Kind)
+	}
+	return intent, nil
+}
@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+
+	"ai-meal-planner/internal/audit"
+	"ai-meal-planner/internal/planner"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// lockKeyboardRows adds a lock toggle for every Cook day of a draft plan: "🔒 <day>"
+// while the day is locked, "🔓 <day>" while the reviewer may change it.
+func lockKeyboardRows(plan *planner.MealPlan) [][]tgbotapi.InlineKeyboardButton {
+	var buttons []tgbotapi.InlineKeyboardButton
+	for i, day := range plan.Plan {
+		if day.RecipeID == "" || day.IsReuse() {
+			continue
+		}
+		label := "🔓 " + shortDay(day.Day)
+		if day.Locked {
+			label = "🔒 " + shortDay(day.Day)
+		}
+		data := fmt.Sprintf("lock|%d|%d", plan.ID, i)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(label, data))
+	}
+	return dayButtonRows(buttons)
+}
+
+// lockMark flags locked days in the draft view.
+func lockMark(day planner.DayPlan) string {
+	if day.Locked {
+		return " 🔒"
+	}
+	return ""
+}
+
+// handleToggleLock locks or unlocks a Cook day and its leftovers and saves the result
+// as a new draft version, so Undo can revert it. Format: "lock|planID|day".
+func (b *Bot) handleToggleLock(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+	planID, _ := strconv.ParseInt(parts[1], 10, 64)
+	dayIndex, _ := strconv.Atoi(parts[2])
+
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || plan == nil {
+		log.Printf("Error retrieving plan %d to lock: %v", planID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not retrieve plan.")
+		return
+	}
+	if plan.Status != planner.StatusDraft {
+		b.answerCallback(query, "Only draft plans can be changed")
+		return
+	}
+
+	previous := *plan
+	previous.Plan = slices.Clone(plan.Plan)
+	locked, err := planner.ToggleLock(plan, dayIndex)
+	if err != nil {
+		b.answerCallback(query, "That day cannot be locked")
+		return
+	}
+
+	feedback := "Unlocked " + plan.Plan[dayIndex].Day
+	if locked {
+		feedback = "Locked " + plan.Plan[dayIndex].Day
+	}
+	if _, err := b.planRepo.SaveVersion(ctx, userID, plan, planner.VersionAuthorLock, feedback); err != nil {
+		log.Printf("Error saving locks of plan %d: %v", plan.ID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not save the plan.")
+		return
+	}
+	_ = b.auditRepo.LogInteraction(ctx, userID, plan.ID, audit.ActionToggleLock, plan.OriginalRequest, feedback, &previous, plan)
+
+	b.answerCallback(query, feedback)
+	b.sendDraftPlan(chatID, messageID, plan)
+}
@@ -0,0 +1,29 @@
+package telegram
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Messenger is the part of the Telegram Bot API the bot talks through. *tgbotapi.BotAPI
+// implements it; tests use the recording fake in telegramtest.
+type Messenger interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+	GetFileDirectURL(fileID string) (string, error)
+	GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
+	StopReceivingUpdates()
+}
+
+// signingMessenger is a Messenger with every inline keyboard signed on the way out.
+type signingMessenger struct {
+	Messenger
+	signer callbackSigner
+}
+
+func (m signingMessenger) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return m.Messenger.Send(m.signer.signMarkup(c))
+}
+
+func (m signingMessenger) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return m.Messenger.Request(m.signer.signMarkup(c))
+}
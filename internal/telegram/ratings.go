@@ -0,0 +1,232 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/planner"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ratingPromptHour is the local hour from which the day-after rating prompts go out.
+const ratingPromptHour = 10
+
+// ratingCheckInterval is how often the bot looks for cooked recipes to ask about.
+const ratingCheckInterval = time.Hour
+
+// ratingPlanLookback is how many recent plans are checked for yesterday's Cook days.
+// Drafts and revisions count too, so it covers a couple of weeks of planning.
+const ratingPlanLookback = 10
+
+// StartRatingPrompts asks the allowed users to rate the recipes they cooked the day before,
+// checking once an hour until ctx is cancelled.
+func (b *Bot) StartRatingPrompts(ctx context.Context) {
+	ticker := time.NewTicker(ratingCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		b.sendRatingPrompts(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendRatingPrompts sends one rating prompt per Cook day of yesterday in each user's confirmed plans.
+// Users talk to the bot in private chats, so the user ID doubles as the chat ID.
+func (b *Bot) sendRatingPrompts(ctx context.Context, now time.Time) {
+	if now.Hour() < ratingPromptHour {
+		return
+	}
+
+	for _, chatID := range b.cfg.TelegramAllowedUserIDs {
+		userID := fmt.Sprintf("%d", chatID)
+		plans, err := b.planRepo.ListRecentByUserID(ctx, userID, ratingPlanLookback)
+		if err != nil {
+			log.Printf("Error listing plans for rating prompts: %v", err)
+			continue
+		}
+
+		for _, plan := range plans {
+			if plan.Status != planner.StatusFinal {
+				continue
+			}
+			for _, dayIndex := range ratingDueDays(&plan, now) {
+				claimed, err := b.ratingRepo.ClaimPrompt(ctx, userID, plan.ID, dayIndex)
+				if err != nil {
+					log.Printf("Error claiming rating prompt for plan %d: %v", plan.ID, err)
+					continue
+				}
+				if !claimed {
+					continue
+				}
+
+				day := plan.Plan[dayIndex]
+				text := fmt.Sprintf("🍽 How was *%s* yesterday?", escapeMarkdown(strings.TrimPrefix(day.RecipeTitle, "Cook: ")))
+				msg := tgbotapi.NewMessage(chatID, text)
+				msg.ParseMode = "Markdown"
+				msg.ReplyMarkup = ratingKeyboard(plan.ID, dayIndex)
+				if _, err := b.api.Send(msg); err != nil {
+					log.Printf("Error sending rating prompt for plan %d: %v", plan.ID, err)
+					// Ask again on the next run instead of losing the prompt
+					if err := b.ratingRepo.ReleasePrompt(ctx, plan.ID, dayIndex); err != nil {
+						log.Printf("Error releasing rating prompt for plan %d: %v", plan.ID, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// ratingDueDays returns the indexes of the plan's Cook days that fell on the day before now.
+func ratingDueDays(plan *planner.MealPlan, now time.Time) []int {
+	yesterday := now.AddDate(0, 0, -1)
+	var due []int
+	for i, day := range plan.Plan {
+		if day.RecipeID == "" || day.IsReuse() {
+			continue
+		}
+		if sameDate(plan.DayDate(i).In(now.Location()), yesterday) {
+			due = append(due, i)
+		}
+	}
+	return due
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// ratingKeyboard offers 1-5 stars, a comment and the permanent "never suggest again" option.
+func ratingKeyboard(planID int64, dayIndex int) tgbotapi.InlineKeyboardMarkup {
+	ref := fmt.Sprintf("%d|%d", planID, dayIndex)
+
+	var stars []tgbotapi.InlineKeyboardButton
+	for n := 1; n <= 5; n++ {
+		stars = append(stars, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d⭐", n), fmt.Sprintf("rate|%s|%d", ref, n)))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		stars,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💬 Comment", "ratecomment|"+ref),
+			tgbotapi.NewInlineKeyboardButtonData("🚫 Never suggest again", "ratenever|"+ref),
+		),
+	)
+}
+
+// handleRate stores the stars picked for a plan day, leaving the comment and never-suggest buttons.
+func (b *Bot) handleRate(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 4 {
+		return
+	}
+	plan, dayIndex, ok := b.loadRatedDay(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	var stars int
+	fmt.Sscanf(parts[3], "%d", &stars)
+	day := plan.Plan[dayIndex]
+	if err := b.ratingRepo.Rate(ctx, userID, day.RecipeID, plan.ID, stars); err != nil {
+		log.Printf("Error saving rating: %v", err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not save your rating.")
+		return
+	}
+
+	text := fmt.Sprintf("%s *%s* — thanks! I'll keep that in mind for future plans.", strings.Repeat("⭐", stars), escapeMarkdown(strings.TrimPrefix(day.RecipeTitle, "Cook: ")))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(ratingKeyboard(plan.ID, dayIndex).InlineKeyboard[1])
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, text)
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// handleRateNever permanently excludes the recipe of a plan day from the user's plans.
+func (b *Bot) handleRateNever(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	plan, dayIndex, ok := b.loadRatedDay(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	day := plan.Plan[dayIndex]
+	if err := b.ratingRepo.NeverSuggest(ctx, userID, day.RecipeID, plan.ID); err != nil {
+		log.Printf("Error saving never-suggest flag: %v", err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not save your choice.")
+		return
+	}
+
+	b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, fmt.Sprintf("🚫 Got it — *%s* won't be suggested again.", escapeMarkdown(strings.TrimPrefix(day.RecipeTitle, "Cook: "))))
+}
+
+// handleRateCommentPrompt waits for a free-text comment on the recipe of a plan day.
+func (b *Bot) handleRateCommentPrompt(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	plan, dayIndex, ok := b.loadRatedDay(ctx, query, userID, parts)
+	if !ok {
+		return
+	}
+
+	day := plan.Plan[dayIndex]
+	sessionCtx := SessionContextData{PlanID: plan.ID, RecipeID: day.RecipeID}
+	if _, err := b.sessionRepo.Create(ctx, userID, SessionTypeRateRecipe, StateAwaitingComment, sessionCtx, 900); err != nil {
+		log.Printf("Error creating rating session: %v", err)
+		b.api.Send(tgbotapi.NewMessage(query.Message.Chat.ID, "❌ Could not start the comment. Please try again."))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, fmt.Sprintf("💬 What did you think of *%s*? Send me a short comment.", escapeMarkdown(strings.TrimPrefix(day.RecipeTitle, "Cook: "))))
+	msg.ParseMode = "Markdown"
+	b.api.Send(msg)
+}
+
+// handleRatingComment stores the comment sent while a rating session is open.
+func (b *Bot) handleRatingComment(ctx context.Context, msg *tgbotapi.Message, session *Session) {
+	defer func() {
+		if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+			log.Printf("Error cleaning up session %d: %v", session.ID, err)
+		}
+	}()
+
+	data, err := session.GetContextData()
+	if err != nil || data.RecipeID == "" {
+		log.Printf("Error parsing rating session %d: %v", session.ID, err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Invalid session data."))
+		return
+	}
+
+	userID := fmt.Sprintf("%d", msg.From.ID)
+	if err := b.ratingRepo.Comment(ctx, userID, data.RecipeID, data.PlanID, strings.TrimSpace(msg.Text)); err != nil {
+		log.Printf("Error saving rating comment: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not save your comment."))
+		return
+	}
+
+	b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "📝 Thanks, noted!"))
+}
+
+// loadRatedDay resolves the plan and Cook day referenced by a rating callback.
+func (b *Bot) loadRatedDay(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) (*planner.MealPlan, int, bool) {
+	if len(parts) < 3 {
+		return nil, 0, false
+	}
+	var planID int64
+	var dayIndex int
+	fmt.Sscanf(parts[1], "%d", &planID)
+	fmt.Sscanf(parts[2], "%d", &dayIndex)
+
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || plan == nil || dayIndex < 0 || dayIndex >= len(plan.Plan) || plan.Plan[dayIndex].RecipeID == "" {
+		log.Printf("Error retrieving plan %d for rating: %v", planID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not find that plan day.")
+		return nil, 0, false
+	}
+	return plan, dayIndex, true
+}
@@ -0,0 +1,212 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/reminder"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dailyReminderHour is the local hour of the "what's for dinner today" message.
+const dailyReminderHour = 8
+
+// The weekly nudge to plan next week goes out on this local day and hour.
+const (
+	planNudgeWeekday = time.Saturday
+	planNudgeHour    = 10
+)
+
+// reminderSendWindow is how many hours after its hour a reminder may still go out,
+// so a restart catches up without texting in the middle of the night.
+const reminderSendWindow = 3
+
+// reminderCheckInterval is how often the bot looks for reminders that are due.
+const reminderCheckInterval = 15 * time.Minute
+
+// StartReminders sends the allowed users their daily meal reminders and the weekly
+// planning nudge, checking every reminderCheckInterval until ctx is cancelled.
+func (b *Bot) StartReminders(ctx context.Context) {
+	ticker := time.NewTicker(reminderCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		b.sendReminders(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendReminders sends every reminder due at now in each user's timezone. Users talk to
+// the bot in private chats, so the user ID doubles as the chat ID.
+func (b *Bot) sendReminders(ctx context.Context, now time.Time) {
+	fallback := b.defaultLocation()
+
+	for _, chatID := range b.cfg.TelegramAllowedUserIDs {
+		userID := fmt.Sprintf("%d", chatID)
+		settings, err := b.reminderRepo.GetSettings(ctx, userID)
+		if err != nil {
+			log.Printf("Error loading reminder settings: %v", err)
+			continue
+		}
+		if !settings.Enabled {
+			continue
+		}
+		loc, err := reminder.LoadLocation(settings.Timezone, fallback)
+		if err != nil {
+			log.Printf("Invalid timezone for user %s, using the default: %v", userID, err)
+			loc = fallback
+		}
+
+		local := now.In(loc)
+		if inReminderWindow(local, dailyReminderHour) {
+			b.sendDailyMeal(ctx, chatID, userID, local)
+		}
+		if local.Weekday() == planNudgeWeekday && inReminderWindow(local, planNudgeHour) {
+			b.sendPlanNudge(ctx, chatID, userID, local)
+		}
+	}
+}
+
+func (b *Bot) defaultLocation() *time.Location {
+	loc, err := reminder.LoadLocation(b.cfg.DefaultTimezone, time.Local)
+	if err != nil {
+		log.Printf("Invalid DEFAULT_TIMEZONE, using server time: %v", err)
+		return time.Local
+	}
+	return loc
+}
+
+func inReminderWindow(local time.Time, hour int) bool {
+	return local.Hour() >= hour && local.Hour() < hour+reminderSendWindow
+}
+
+// calendarDay is the user's local date at midnight in the server's timezone, which is
+// how plan weeks are keyed.
+func calendarDay(local time.Time) time.Time {
+	y, m, d := local.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// sendDailyMeal tells the user what today's confirmed plan has for dinner and what
+// tomorrow's recipe needs done tonight.
+func (b *Bot) sendDailyMeal(ctx context.Context, chatID int64, userID string, local time.Time) {
+	today := calendarDay(local)
+	meals, err := b.finalPlanDay(ctx, userID, today)
+	if err != nil {
+		log.Printf("Error loading today's meals for reminders: %v", err)
+		return
+	}
+	tomorrow, err := b.finalPlanDay(ctx, userID, today.AddDate(0, 0, 1))
+	if err != nil {
+		log.Printf("Error loading tomorrow's meals for reminders: %v", err)
+	}
+
+	var cookIDs []string
+	for _, meal := range tomorrow {
+		if meal.RecipeID != "" && !meal.IsReuse() {
+			cookIDs = append(cookIDs, meal.RecipeID)
+		}
+	}
+	var prep []string
+	if len(cookIDs) > 0 {
+		recipes, err := b.planner.RecipeSearcher.GetByIds(ctx, cookIDs)
+		if err != nil {
+			log.Printf("Error loading tomorrow's recipes %v: %v", cookIDs, err)
+		}
+		for _, rec := range recipes {
+			prep = append(prep, reminder.PrepAhead(rec)...)
+		}
+	}
+
+	text := formatDailyReminder(today, meals, prep)
+	if text == "" {
+		return
+	}
+	b.deliverReminder(ctx, chatID, userID, reminder.KindDailyMeal, today, text)
+}
+
+// finalPlanDay returns the meals planned for day in the user's confirmed plan, one per
+// slot: weekend days have lunch and dinner.
+func (b *Bot) finalPlanDay(ctx context.Context, userID string, day time.Time) ([]planner.DayPlan, error) {
+	weekStart := planner.GetNextMonday(day).AddDate(0, 0, -7)
+	plan, err := b.planRepo.GetFinalByUserAndWeek(ctx, userID, weekStart)
+	if err != nil || plan == nil {
+		return nil, err
+	}
+	var meals []planner.DayPlan
+	for i := range plan.Plan {
+		if sameDate(plan.DayDate(i), day) {
+			meals = append(meals, plan.Plan[i])
+		}
+	}
+	return meals, nil
+}
+
+// formatDailyReminder returns an empty string when there is neither a meal today nor
+// anything to prepare for tomorrow.
+func formatDailyReminder(today time.Time, meals []planner.DayPlan, prep []string) string {
+	if len(meals) == 0 && len(prep) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	if len(meals) > 0 {
+		sb.WriteString(formatDayMeals(fmt.Sprintf("☀️ *Today (%s)*", today.Weekday()), meals))
+	}
+	if len(prep) > 0 {
+		if len(meals) > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString("🌙 *Tonight, for tomorrow:*")
+		for _, task := range prep {
+			sb.WriteString("\n" + escapeMarkdown(task))
+		}
+	}
+	return sb.String()
+}
+
+// sendPlanNudge reminds the user to plan next week when it has no plan yet.
+func (b *Bot) sendPlanNudge(ctx context.Context, chatID int64, userID string, local time.Time) {
+	today := calendarDay(local)
+	nextMonday := planner.GetNextMonday(today)
+	exists, err := b.planRepo.ExistsForWeek(ctx, userID, nextMonday)
+	if err != nil || exists {
+		if err != nil {
+			log.Printf("Error checking next week's plan for the nudge: %v", err)
+		}
+		return
+	}
+
+	text := fmt.Sprintf("🗓️ Next week (from *%s*) isn't planned yet. Tell me what you'd like to eat, or send /plan.", nextMonday.Format("2006-01-02"))
+	b.deliverReminder(ctx, chatID, userID, reminder.KindPlanNudge, today, text)
+}
+
+// deliverReminder sends a reminder of the given kind once per local day. A reminder that
+// cannot be sent is released, so the next run tries it again.
+func (b *Bot) deliverReminder(ctx context.Context, chatID int64, userID, kind string, today time.Time, text string) {
+	claimed, err := b.reminderRepo.Claim(ctx, userID, kind, today)
+	if err != nil || !claimed {
+		if err != nil {
+			log.Printf("Error claiming %s reminder: %v", kind, err)
+		}
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	if _, err := b.api.Send(msg); err != nil {
+		log.Printf("Error sending %s reminder: %v", kind, err)
+		if err := b.reminderRepo.Release(ctx, userID, kind, today); err != nil {
+			log.Printf("Error releasing %s reminder: %v", kind, err)
+		}
+	}
+}
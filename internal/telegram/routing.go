@@ -0,0 +1,437 @@
+package telegram
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/clipper"
+	"ai-meal-planner/internal/metrics"
+	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/shopping"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+//go:embed help_message.md
+var helpMessage string
+
+// intentConfirmTTL is how long a question like "Plan next week for ...?" waits for an answer.
+const intentConfirmTTL = 600
+
+// defaultPlanRequest stands in for /plan without a request.
+const defaultPlanRequest = "generate a meal plan"
+
+// routeMessage works out what a message outside any session asks for and runs it.
+// Links and commands route directly; free text goes through the classifier, and
+// planning runs it asks for wait for the user to confirm.
+func (b *Bot) routeMessage(ctx context.Context, msg *tgbotapi.Message) {
+	if intent, ok := parseCommandIntent(msg.Text); ok {
+		b.runIntent(ctx, msg, intent)
+		return
+	}
+
+	intent := b.classifyMessage(ctx, msg.Text)
+	if intent.Expensive() {
+		b.confirmIntent(ctx, msg, intent)
+		return
+	}
+	b.runIntent(ctx, msg, intent)
+}
+
+// classifyMessage falls back to planning, the bot's original behavior, when the
+// classifier is unavailable. Planning asks for confirmation, so a wrong guess is cheap.
+func (b *Bot) classifyMessage(ctx context.Context, text string) Intent {
+	fallback := Intent{Kind: IntentPlan, Request: text}
+	if b.intents == nil {
+		return fallback
+	}
+
+	result, err := b.intents.Classify(ctx, text)
+	if result.Meta.AgentName != "" {
+		_ = b.metricsStore.Record(metrics.ExecutionMetric{
+			AgentName:        result.Meta.AgentName,
+			Model:            result.Meta.Usage.Model,
+			PromptTokens:     result.Meta.Usage.PromptTokens,
+			CompletionTokens: result.Meta.Usage.CompletionTokens,
+			LatencyMS:        result.Meta.Latency.Milliseconds(),
+		})
+	}
+	if err != nil {
+		log.Printf("Error classifying message, falling back to planning: %v", err)
+		return fallback
+	}
+	if result.Intent.Expensive() && result.Intent.Request == "" {
+		result.Intent.Request = text
+	}
+	return result.Intent
+}
+
+// runIntent hands the message to the handler of its intent.
+func (b *Bot) runIntent(ctx context.Context, msg *tgbotapi.Message, intent Intent) {
+	switch intent.Kind {
+	case IntentClip:
+		if clipper.IsImageURL(strings.Fields(intent.Request)[0]) {
+			b.handleImageClipRequest(msg)
+			return
+		}
+		b.handleClipperRequest(msg)
+	case IntentPlan:
+		request := intent.Request
+		if request == "" {
+			request = defaultPlanRequest
+		}
+		b.handlePlannerRequest(withText(msg, request))
+	case IntentAdjust:
+		b.startAdjustment(ctx, msg, intent.Request)
+	case IntentQueryPlan:
+		b.handlePlanQuery(ctx, msg, intent.Day)
+	case IntentShoppingShow, IntentShoppingAdd, IntentShoppingRemove:
+		b.handleShoppingIntent(ctx, msg, intent)
+	case IntentSearch:
+		b.handleRecipeSearch(ctx, msg, intent.Request)
+	case IntentRandom:
+		b.handleRandomRecipes(ctx, msg)
+	default:
+		reply := tgbotapi.NewMessage(msg.Chat.ID, helpMessage)
+		reply.ParseMode = "Markdown"
+		b.api.Send(reply)
+	}
+}
+
+// withText copies a message with different text, so handlers that read msg.Text
+// receive the request an intent extracted.
+func withText(msg *tgbotapi.Message, text string) *tgbotapi.Message {
+	copied := *msg
+	copied.Text = text
+	return &copied
+}
+
+// confirmIntent asks before starting a planning run guessed from free text.
+func (b *Bot) confirmIntent(ctx context.Context, msg *tgbotapi.Message, intent Intent) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+	sessionID, err := b.sessionRepo.Create(ctx, userID, SessionTypeConfirmIntent, StateAwaitingConfirm, SessionContextData{
+		Intent:          string(intent.Kind),
+		OriginalRequest: intent.Request,
+	}, intentConfirmTTL)
+	if err != nil {
+		log.Printf("Error creating intent confirmation session: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Something went wrong. Please try again."))
+		return
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, formatIntentQuestion(intent))
+	reply.ParseMode = "Markdown"
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Yes", fmt.Sprintf("intent|%d|yes", sessionID)),
+		tgbotapi.NewInlineKeyboardButtonData("✖️ No", fmt.Sprintf("intent|%d|no", sessionID)),
+	))
+	b.api.Send(reply)
+}
+
+func formatIntentQuestion(intent Intent) string {
+	if intent.Kind == IntentAdjust {
+		return fmt.Sprintf("✏️ Change your draft plan: _%s_?", escapeMarkdown(intent.Request))
+	}
+	return fmt.Sprintf("🧑‍🍳 Plan next week for: _%s_?", escapeMarkdown(intent.Request))
+}
+
+// handleIntentConfirm runs or drops a confirmed intent. Format: "intent|sessionID|yes|no".
+func (b *Bot) handleIntentConfirm(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+	sessionID, _ := strconv.ParseInt(parts[1], 10, 64)
+
+	session, err := b.sessionRepo.GetByID(ctx, sessionID, userID, time.Now())
+	if err != nil || session == nil || session.SessionType != SessionTypeConfirmIntent {
+		if err != nil {
+			log.Printf("Error retrieving intent session %d: %v", sessionID, err)
+		}
+		b.editMarkdown(chatID, messageID, "⌛ This question has expired. Send your request again.")
+		return
+	}
+	if err := b.sessionRepo.Delete(ctx, session.ID); err != nil {
+		log.Printf("Error cleaning up session %d: %v", session.ID, err)
+	}
+
+	contextData, err := session.GetContextData()
+	if err != nil {
+		log.Printf("Error parsing session context: %v", err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Invalid session data.")
+		return
+	}
+	intent := Intent{Kind: IntentKind(contextData.Intent), Request: contextData.OriginalRequest}
+
+	if parts[2] != "yes" {
+		b.editMarkdown(chatID, messageID, "👍 Okay, nothing changed.")
+		return
+	}
+	b.editMarkdown(chatID, messageID, strings.TrimSuffix(formatIntentQuestion(intent), "?"))
+	b.runIntent(ctx, &tgbotapi.Message{From: query.From, Chat: query.Message.Chat, Text: intent.Request}, intent)
+}
+
+// startAdjustment opens an adjustment conversation on the current draft, applying
+// feedback right away when the message carried some.
+func (b *Bot) startAdjustment(ctx context.Context, msg *tgbotapi.Message, feedback string) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+
+	plan, err := b.currentWeekPlan(ctx, userID, time.Now())
+	if err != nil {
+		log.Printf("Error loading plan to adjust: %v", err)
+	}
+	if plan == nil {
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🗓️ There is no draft plan to change. Tell me what you'd like to eat and I'll plan next week."))
+		return
+	}
+	if plan.Status != planner.StatusDraft {
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "✅ That plan is already confirmed, so it can't be changed."))
+		return
+	}
+
+	sessionID, err := b.openAdjustSession(ctx, userID, plan)
+	if err != nil {
+		log.Printf("Error creating session: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not start adjustment mode."))
+		return
+	}
+
+	if feedback == "" {
+		prompt := tgbotapi.NewMessage(msg.Chat.ID, adjustmentPrompt)
+		prompt.ParseMode = "Markdown"
+		prompt.ReplyMarkup = adjustSessionKeyboard(sessionID)
+		b.api.Send(prompt)
+		return
+	}
+
+	session, err := b.sessionRepo.GetByID(ctx, sessionID, userID, time.Now())
+	if err != nil || session == nil {
+		log.Printf("Error loading adjustment session %d: %v", sessionID, err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not start adjustment mode."))
+		return
+	}
+	b.handleAdjustmentFeedback(ctx, withText(msg, feedback), session)
+}
+
+// handlePlanQuery answers what is planned for a day, or for the whole week when day is empty.
+func (b *Bot) handlePlanQuery(ctx context.Context, msg *tgbotapi.Message, day string) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+	now := time.Now()
+
+	if day == "" {
+		plan, err := b.currentWeekPlan(ctx, userID, now)
+		if err != nil {
+			log.Printf("Error loading plan for query: %v", err)
+		}
+		if plan == nil {
+			b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🗓️ There is no plan for this week or next week yet."))
+			return
+		}
+		planText, _ := formatPlanMarkdownParts(plan)
+		if plan.Status == planner.StatusDraft {
+			planText += "_This plan is still a draft._"
+		}
+		reply := tgbotapi.NewMessage(msg.Chat.ID, planText)
+		reply.ParseMode = "Markdown"
+		b.api.Send(reply)
+		return
+	}
+
+	date, ok := resolvePlanDay(day, now)
+	if !ok {
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🤔 I didn't understand which day you mean."))
+		return
+	}
+	// A confirmed plan answers even while a regenerated draft of its week waits
+	weekStart := planner.GetNextMonday(date).AddDate(0, 0, -7)
+	plan, err := b.planRepo.GetFinalByUserAndWeek(ctx, userID, weekStart)
+	if err == nil && plan == nil {
+		plan, err = b.planRepo.GetCurrentByUserAndWeek(ctx, userID, weekStart)
+	}
+	if err != nil {
+		log.Printf("Error loading plan for query: %v", err)
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, formatDayQuery(plan, day, date))
+	reply.ParseMode = "Markdown"
+	b.api.Send(reply)
+}
+
+// resolvePlanDay turns "today", "tomorrow" or a weekday name into a date, taking
+// weekdays as today or the next time that day comes around.
+func resolvePlanDay(day string, now time.Time) (time.Time, bool) {
+	switch day {
+	case "today":
+		return now, true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	}
+	for offset := 0; offset < 7; offset++ {
+		date := now.AddDate(0, 0, offset)
+		if strings.EqualFold(date.Weekday().String(), day) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func formatDayQuery(plan *planner.MealPlan, day string, date time.Time) string {
+	label := date.Weekday().String()
+	if day == "today" || day == "tomorrow" {
+		label = fmt.Sprintf("%s%s (%s)", strings.ToUpper(day[:1]), day[1:], label)
+	}
+	if plan == nil {
+		return fmt.Sprintf("🗓️ Nothing is planned for %s yet.", label)
+	}
+
+	var meals []planner.DayPlan
+	for i, meal := range plan.Plan {
+		if sameDate(plan.DayDate(i), date) {
+			meals = append(meals, meal)
+		}
+	}
+	if len(meals) > 0 {
+		text := formatDayMeals(fmt.Sprintf("🍽️ *%s*", label), meals)
+		if plan.Status == planner.StatusDraft {
+			text += "\n_This plan is still a draft._"
+		}
+		return text
+	}
+	return fmt.Sprintf("🗓️ Nothing is planned for %s.", label)
+}
+
+// formatDayMeals lists the meals of one date under header. A day with one meal fits on the
+// header's line; weekend days list each slot, e.g. "*Lunch*", on its own line.
+func formatDayMeals(header string, meals []planner.DayPlan) string {
+	var sb strings.Builder
+	sb.WriteString(header)
+	for _, meal := range meals {
+		if len(meals) == 1 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString(fmt.Sprintf("\n*%s*: ", escapeMarkdown(mealSlot(meal.Day))))
+		}
+		sb.WriteString(escapeMarkdown(meal.RecipeTitle))
+		if meal.PrepTime != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", escapeMarkdown(meal.PrepTime)))
+		}
+		if len(meal.SideDishes) > 0 {
+			sb.WriteString(fmt.Sprintf("\n   — %s", escapeMarkdown(strings.Join(meal.SideDishes, ", "))))
+		}
+	}
+	return sb.String()
+}
+
+// mealSlot returns the meal a slot name is for, "Lunch" in "Saturday (Lunch)", or the
+// whole name when it has none.
+func mealSlot(day string) string {
+	_, slot, ok := strings.Cut(day, "(")
+	if !ok {
+		return day
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(slot), ")"))
+}
+
+// handleShoppingIntent shows the current shopping list, adding or removing items first.
+func (b *Bot) handleShoppingIntent(ctx context.Context, msg *tgbotapi.Message, intent Intent) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+
+	list, err := b.currentShoppingList(ctx, userID, time.Now())
+	if err != nil {
+		log.Printf("Error loading shopping list: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not load the shopping list."))
+		return
+	}
+	if list == nil {
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🛒 There is no shopping list yet. It is made when you confirm a plan."))
+		return
+	}
+
+	var note string
+	switch intent.Kind {
+	case IntentShoppingAdd, IntentShoppingRemove:
+		if len(intent.Items) == 0 {
+			b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🛒 Which items? For example: /add milk, eggs"))
+			return
+		}
+		var changed []string
+		if intent.Kind == IntentShoppingAdd {
+			list.Items, changed = addShoppingItems(list.Items, intent.Items)
+			note = "➕ Added: "
+		} else {
+			list.Items, changed = removeShoppingItems(list.Items, intent.Items)
+			note = "➖ Removed: "
+		}
+		if len(changed) == 0 {
+			note = "_Nothing to change._"
+			break
+		}
+		if err := b.shoppingRepo.UpdateItems(ctx, list.ID, list.Items); err != nil {
+			log.Printf("Error updating shopping list: %v", err)
+			b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not update the shopping list."))
+			return
+		}
+		note += escapeMarkdown(strings.Join(changed, ", "))
+	}
+
+	text := formatShoppingList(list.Items)
+	if note != "" {
+		text = note + "\n\n" + text
+	}
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ParseMode = "Markdown"
+	b.api.Send(reply)
+}
+
+// currentShoppingList returns next week's shopping list, or else this week's.
+func (b *Bot) currentShoppingList(ctx context.Context, userID string, now time.Time) (*shopping.ShoppingList, error) {
+	nextMonday := planner.GetNextMonday(now)
+	list, err := b.shoppingRepo.GetByUserAndWeek(ctx, userID, nextMonday)
+	if err != nil || list != nil {
+		return list, err
+	}
+	return b.shoppingRepo.GetByUserAndWeek(ctx, userID, nextMonday.AddDate(0, 0, -7))
+}
+
+// addShoppingItems appends the items the list does not have yet, returning the
+// new list and the items added.
+func addShoppingItems(list, items []string) ([]string, []string) {
+	var added []string
+	for _, item := range items {
+		if !slices.ContainsFunc(list, func(existing string) bool { return strings.EqualFold(existing, item) }) {
+			list = append(list, item)
+			added = append(added, item)
+		}
+	}
+	return list, added
+}
+
+// removeShoppingItems drops every entry mentioning one of the items as a whole word, so
+// "milk" removes "1 L whole milk" and "egg" removes "6 eggs" but not "eggplant". It
+// returns the new list and the entries removed.
+func removeShoppingItems(list, items []string) ([]string, []string) {
+	patterns := make([]*regexp.Regexp, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			// Letters and digits are word characters in any language, and plurals count
+			patterns = append(patterns, regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])`+regexp.QuoteMeta(item)+`(?:e?s)?(?:$|[^\p{L}\p{N}])`))
+		}
+	}
+
+	var kept, removed []string
+	for _, entry := range list {
+		if slices.ContainsFunc(patterns, func(p *regexp.Regexp) bool { return p.MatchString(entry) }) {
+			removed = append(removed, entry)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept, removed
+}
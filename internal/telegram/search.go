@@ -0,0 +1,424 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/audit"
+	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/shared"
+	"ai-meal-planner/internal/shopping"
+	"ai-meal-planner/internal/value"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// searchPageSize is how many recipe cards one page of results shows.
+const searchPageSize = 3
+
+// randomRecipeCount is how many recipes /random draws from the library.
+const randomRecipeCount = 9
+
+// searchTTL keeps search results browsable for an hour.
+const searchTTL = 60 * 60
+
+// maxCardTags keeps recipe cards short; the Ghost post lists every tag.
+const maxCardTags = 4
+
+// handleRecipeSearch shows the library recipes that best match a query.
+func (b *Bot) handleRecipeSearch(ctx context.Context, msg *tgbotapi.Message, query string) {
+	if query == "" {
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🔎 What should I look for? For example: /search pumpkin soup"))
+		return
+	}
+	userID := fmt.Sprintf("%d", msg.From.ID)
+
+	recipes, err := b.planner.RecipeSearcher.RecipeSemanticSearch(ctx, query, shared.RecipeFilter{UserID: userID})
+	if err != nil {
+		log.Printf("Error searching recipes: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not search the recipes."))
+		return
+	}
+	b.sendRecipeResults(ctx, msg, userID, query, recipes)
+}
+
+// handleRandomRecipes shows recipes drawn at random, skipping the ones the user dislikes.
+func (b *Bot) handleRandomRecipes(ctx context.Context, msg *tgbotapi.Message) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+
+	recipes, err := b.planner.RecipeSearcher.RandomRecipes(ctx, randomRecipeCount, shared.RecipeFilter{UserID: userID})
+	if err != nil {
+		log.Printf("Error drawing random recipes: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not load the recipes."))
+		return
+	}
+	b.sendRecipeResults(ctx, msg, userID, "", recipes)
+}
+
+// sendRecipeResults keeps the result IDs in a session, so paging and adding to the
+// plan work without searching again, and shows the first page.
+func (b *Bot) sendRecipeResults(ctx context.Context, msg *tgbotapi.Message, userID, query string, recipes []value.Recipe) {
+	if len(recipes) == 0 {
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "🔎 No recipes found."))
+		return
+	}
+
+	data := SessionContextData{OriginalRequest: query}
+	for _, rec := range recipes {
+		data.RecipeIDs = append(data.RecipeIDs, rec.ID)
+	}
+	sessionID, err := b.sessionRepo.Create(ctx, userID, SessionTypeSearch, StateBrowsing, data, searchTTL)
+	if err != nil {
+		log.Printf("Error creating search session: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Something went wrong. Please try again."))
+		return
+	}
+
+	text, keyboard := formatSearchPage(sessionID, data, recipes[:min(len(recipes), searchPageSize)], 0)
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	reply.ParseMode = "Markdown"
+	reply.DisableWebPagePreview = true
+	reply.ReplyMarkup = keyboard
+	b.api.Send(reply)
+}
+
+// formatSearchPage renders one page of recipe cards with an add button per recipe and
+// buttons to the neighbouring pages.
+func formatSearchPage(sessionID int64, data SessionContextData, recipes []value.Recipe, page int) (string, tgbotapi.InlineKeyboardMarkup) {
+	first := page * searchPageSize
+	last := min(first+searchPageSize, len(data.RecipeIDs))
+
+	var sb strings.Builder
+	if data.OriginalRequest != "" {
+		sb.WriteString(fmt.Sprintf("🔎 *Recipes for* _%s_", escapeMarkdown(data.OriginalRequest)))
+	} else {
+		sb.WriteString("🎲 *Random recipes*")
+	}
+	sb.WriteString(fmt.Sprintf(" — %d-%d of %d\n", first+1, last, len(data.RecipeIDs)))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, rec := range recipes {
+		// Numbers follow the stored results, so a recipe deleted since the search leaves a gap
+		index := slices.Index(data.RecipeIDs, rec.ID)
+		sb.WriteString("\n" + formatRecipeCard(index+1, rec) + "\n")
+
+		label := fmt.Sprintf("➕ %d. %s", index+1, truncateTitle(rec.Title))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("searchadd|%d|%d", sessionID, index)),
+		))
+	}
+	if len(recipes) == 0 {
+		sb.WriteString("\n_These recipes are no longer in the library._\n")
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("⬅️ Previous", fmt.Sprintf("search|%d|%d", sessionID, page-1)))
+	}
+	if last < len(data.RecipeIDs) {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("Next ➡️", fmt.Sprintf("search|%d|%d", sessionID, page+1)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	return sb.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// formatRecipeCard shows a recipe's title, prep time, first tags and Ghost link.
+// Recipes extracted before post URLs were stored have no link until they are extracted again.
+func formatRecipeCard(number int, rec value.Recipe) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*%d. %s*", number, escapeMarkdown(rec.Title)))
+
+	var details []string
+	if rec.PrepTime != "" {
+		details = append(details, "⏱️ "+escapeMarkdown(rec.PrepTime))
+	}
+	if len(rec.Tags) > 0 {
+		details = append(details, "🏷️ "+escapeMarkdown(strings.Join(rec.Tags[:min(len(rec.Tags), maxCardTags)], ", ")))
+	}
+	if len(details) > 0 {
+		sb.WriteString("\n" + strings.Join(details, " · "))
+	}
+	if rec.PostURL != "" {
+		sb.WriteString(fmt.Sprintf("\n[🔗 Open recipe](%s)", escapeMarkdownURL(rec.PostURL)))
+	}
+	return sb.String()
+}
+
+// handleSearchPage shows another page of results. Format: "search|sessionID|page".
+func (b *Bot) handleSearchPage(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	sessionID, data, ok := b.loadSearchSession(ctx, query, userID, parts[1])
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(parts[2])
+	b.showSearchPage(ctx, query.Message.Chat.ID, query.Message.MessageID, sessionID, data, page)
+}
+
+func (b *Bot) showSearchPage(ctx context.Context, chatID int64, messageID int, sessionID int64, data SessionContextData, page int) {
+	pages := (len(data.RecipeIDs) + searchPageSize - 1) / searchPageSize
+	page = max(0, min(page, pages-1))
+	first := page * searchPageSize
+
+	recipes, err := b.planner.RecipeSearcher.GetByIds(ctx, data.RecipeIDs[first:min(first+searchPageSize, len(data.RecipeIDs))])
+	if err != nil {
+		log.Printf("Error loading search results: %v", err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not load the recipes.")
+		return
+	}
+
+	text, keyboard := formatSearchPage(sessionID, data, recipes, page)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = "Markdown"
+	edit.DisableWebPagePreview = true
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// handleSearchAdd asks which day of this week's plan gets the recipe.
+// Format: "searchadd|sessionID|index".
+func (b *Bot) handleSearchAdd(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 3 {
+		return
+	}
+	sessionID, data, ok := b.loadSearchSession(ctx, query, userID, parts[1])
+	if !ok {
+		return
+	}
+	rec, index, ok := b.searchResult(ctx, query, data, parts[2])
+	if !ok {
+		return
+	}
+
+	// The confirmed plan the user is following takes the recipe, not a draft waiting to replace it
+	now := time.Now()
+	thisMonday := planner.GetNextMonday(now).AddDate(0, 0, -7)
+	plan, err := b.planRepo.GetFinalByUserAndWeek(ctx, userID, thisMonday)
+	if err == nil && plan == nil {
+		plan, err = b.planRepo.GetCurrentByUserAndWeek(ctx, userID, thisMonday)
+	}
+	if err != nil {
+		log.Printf("Error loading the plan to add a recipe to: %v", err)
+		b.answerCallback(query, "Could not load your plan")
+		return
+	}
+	if plan == nil {
+		b.answerCallback(query, "You have no plan for this week yet. Send /plan first.")
+		return
+	}
+
+	text, keyboard, ok := formatSearchDayPicker(sessionID, index, rec, plan, now)
+	if !ok {
+		b.answerCallback(query, "No day left this week can be changed")
+		return
+	}
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, text)
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// formatSearchDayPicker offers the unlocked Cook days of the plan from today on for a
+// recipe from the results. It reports false when there is no such day.
+func formatSearchDayPicker(sessionID int64, index int, rec value.Recipe, plan *planner.MealPlan, now time.Time) (string, tgbotapi.InlineKeyboardMarkup, bool) {
+	var buttons []tgbotapi.InlineKeyboardButton
+	for i, day := range plan.Plan {
+		if day.RecipeID == "" || day.IsReuse() || day.Locked || pastPlanDay(plan, i, now) {
+			continue
+		}
+		data := fmt.Sprintf("searchto|%d|%d|%d|%d|%d", sessionID, index, plan.ID, plan.Version, i)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(shortDay(day.Day), data))
+	}
+	if len(buttons) == 0 {
+		return "", tgbotapi.InlineKeyboardMarkup{}, false
+	}
+
+	text := fmt.Sprintf("➕ Add *%s* to the plan for the week of %s on which day? It replaces that day's recipe and its leftovers.",
+		escapeMarkdown(rec.Title), plan.WeekStart.Format("2006-01-02"))
+	rows := dayButtonRows(buttons)
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅️ Back", fmt.Sprintf("search|%d|%d", sessionID, index/searchPageSize)),
+	))
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...), true
+}
+
+// pastPlanDay reports whether the i-th day of the plan falls before today.
+func pastPlanDay(plan *planner.MealPlan, i int, now time.Time) bool {
+	y, m, d := plan.DayDate(i).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = now.Date()
+	return day.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
+// handleSearchChoose puts a recipe from the results in a day of this week's plan. A draft
+// is sent back to confirm; a confirmed plan stays confirmed with a new shopping list.
+// Format: "searchto|sessionID|index|planID|version|day".
+func (b *Bot) handleSearchChoose(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 6 {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+	sessionID, data, ok := b.loadSearchSession(ctx, query, userID, parts[1])
+	if !ok {
+		return
+	}
+	rec, index, ok := b.searchResult(ctx, query, data, parts[2])
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	planID, _ := strconv.ParseInt(parts[3], 10, 64)
+	version, _ := strconv.Atoi(parts[4])
+	dayIndex, _ := strconv.Atoi(parts[5])
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || plan == nil {
+		log.Printf("Error retrieving plan %d to add a recipe to: %v", planID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not retrieve plan.")
+		return
+	}
+	if plan.Version != version {
+		b.answerCallback(query, "The plan changed, pick the day again")
+		if text, keyboard, ok := formatSearchDayPicker(sessionID, index, rec, plan, now); ok {
+			edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+			edit.ParseMode = "Markdown"
+			edit.ReplyMarkup = &keyboard
+			b.api.Send(edit)
+		}
+		return
+	}
+	if dayIndex < 0 || dayIndex >= len(plan.Plan) || plan.Plan[dayIndex].Locked || pastPlanDay(plan, dayIndex, now) {
+		b.answerCallback(query, "That day can't be changed")
+		return
+	}
+
+	if plan.Status == planner.StatusFinal {
+		if !b.addToConfirmedPlan(ctx, query, userID, plan, dayIndex, rec) {
+			return
+		}
+		b.showSearchPage(ctx, chatID, messageID, sessionID, data, index/searchPageSize)
+
+		planText, shoppingListText := formatPlanMarkdownParts(plan)
+		reply := tgbotapi.NewMessage(chatID, "✅ *Plan updated!*\n\n"+planText)
+		reply.ParseMode = "Markdown"
+		if keyboard := planCookingKeyboard(plan); keyboard != nil {
+			reply.ReplyMarkup = keyboard
+		}
+		b.api.Send(reply)
+		shoppingMsg := tgbotapi.NewMessage(chatID, shoppingListText)
+		shoppingMsg.ParseMode = "Markdown"
+		b.api.Send(shoppingMsg)
+		return
+	}
+
+	if err := b.swapPlanDay(ctx, userID, plan, dayIndex, rec); err != nil {
+		log.Printf("Error adding recipe %s to day %d of plan %d: %v", rec.ID, dayIndex, plan.ID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not add the recipe to the plan.")
+		return
+	}
+
+	b.answerCallback(query, fmt.Sprintf("Added to %s", plan.Plan[dayIndex].Day))
+	b.showSearchPage(ctx, chatID, messageID, sessionID, data, index/searchPageSize)
+
+	reply := tgbotapi.NewMessage(chatID, formatDraftPlanMarkdown(plan))
+	reply.ParseMode = "Markdown"
+	reply.ReplyMarkup = draftPlanKeyboard(plan)
+	b.api.Send(reply)
+}
+
+// addToConfirmedPlan swaps a day of a confirmed plan and keeps it confirmed, with its
+// shopping list generated again. It answers the button press and reports whether the plan changed.
+func (b *Bot) addToConfirmedPlan(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, plan *planner.MealPlan, dayIndex int, rec value.Recipe) bool {
+	previous := *plan
+	previous.Plan = slices.Clone(plan.Plan)
+	if err := planner.SwapDay(plan, dayIndex, rec); err != nil {
+		log.Printf("Error adding recipe %s to day %d of plan %d: %v", rec.ID, dayIndex, plan.ID, err)
+		b.answerCallback(query, "That day can't be changed")
+		return false
+	}
+
+	pCtx := planner.PlanningContext{
+		Adults:           b.cfg.DefaultAdults,
+		Children:         b.cfg.DefaultChildren,
+		ChildrenAges:     b.cfg.DefaultChildrenAges,
+		CookingFrequency: b.cfg.DefaultCookingFrequency,
+	}
+	items, err := b.planner.GenerateShoppingList(ctx, plan, pCtx)
+	if err != nil {
+		log.Printf("Error generating shopping list for plan %d: %v", plan.ID, err)
+		b.answerCallback(query, "Could not update the shopping list, the plan is unchanged")
+		return false
+	}
+	plan.ShoppingList = items
+
+	feedback := fmt.Sprintf("Add %s on %s", rec.Title, previous.Plan[dayIndex].Day)
+	if _, err := b.planRepo.SaveVersion(ctx, userID, plan, planner.VersionAuthorSwap, feedback); err != nil {
+		log.Printf("Error saving plan %d: %v", plan.ID, err)
+		b.answerCallback(query, "Could not save your plan")
+		return false
+	}
+	_ = b.auditRepo.LogInteraction(ctx, userID, plan.ID, audit.ActionSwapDay, plan.OriginalRequest, feedback, &previous, plan)
+	b.replaceShoppingList(ctx, userID, plan.ID, items)
+
+	b.answerCallback(query, fmt.Sprintf("Added to %s", plan.Plan[dayIndex].Day))
+	return true
+}
+
+// replaceShoppingList stores the items as the plan's shopping list, replacing the one
+// saved when it was confirmed.
+func (b *Bot) replaceShoppingList(ctx context.Context, userID string, planID int64, items []string) {
+	list, err := b.shoppingRepo.GetByMealPlanID(ctx, planID)
+	if err != nil {
+		log.Printf("Warning: failed to load shopping list of plan %d: %v", planID, err)
+		return
+	}
+	if list != nil {
+		err = b.shoppingRepo.UpdateItems(ctx, list.ID, items)
+	} else {
+		_, err = b.shoppingRepo.Save(ctx, &shopping.ShoppingList{UserID: userID, MealPlanID: planID, Items: items})
+	}
+	if err != nil {
+		log.Printf("Warning: failed to save shopping list of plan %d: %v", planID, err)
+	}
+}
+
+// loadSearchSession reads the results of a search, answering the callback when they expired.
+func (b *Bot) loadSearchSession(ctx context.Context, query *tgbotapi.CallbackQuery, userID, sessionArg string) (int64, SessionContextData, bool) {
+	sessionID, _ := strconv.ParseInt(sessionArg, 10, 64)
+	session, err := b.sessionRepo.GetByID(ctx, sessionID, userID, time.Now())
+	if err != nil || session == nil || session.SessionType != SessionTypeSearch {
+		b.answerCallback(query, "These results expired. Search again.")
+		return 0, SessionContextData{}, false
+	}
+	data, err := session.GetContextData()
+	if err != nil || len(data.RecipeIDs) == 0 {
+		log.Printf("Error reading search session %d: %v", sessionID, err)
+		b.answerCallback(query, "These results expired. Search again.")
+		return 0, SessionContextData{}, false
+	}
+	return sessionID, data, true
+}
+
+// searchResult loads the recipe at a position of the stored results.
+func (b *Bot) searchResult(ctx context.Context, query *tgbotapi.CallbackQuery, data SessionContextData, indexArg string) (value.Recipe, int, bool) {
+	index, err := strconv.Atoi(indexArg)
+	if err != nil || index < 0 || index >= len(data.RecipeIDs) {
+		return value.Recipe{}, 0, false
+	}
+	recipes, err := b.planner.RecipeSearcher.GetByIds(ctx, []string{data.RecipeIDs[index]})
+	if err != nil || len(recipes) == 0 {
+		log.Printf("Error retrieving search result %s: %v", data.RecipeIDs[index], err)
+		b.answerCallback(query, "That recipe is no longer in the library")
+		return value.Recipe{}, 0, false
+	}
+	return recipes[0], index, true
+}
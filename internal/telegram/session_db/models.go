@@ -21,6 +21,13 @@ type AuditLog struct {
 	CreatedAt       time.Time
 }
 
+type CookingTimer struct {
+	ID      int64
+	ChatID  int64
+	Message string
+	DueAt   time.Time
+}
+
 type ExecutionMetric struct {
 	ID               int64
 	AgentName        string
@@ -39,10 +46,32 @@ type ExecutionToolCall struct {
 	TotalLatencyMs    int64
 }
 
+type MealPlanVersion struct {
+	MealPlanID    int64
+	Version       int64
+	ParentVersion sql.NullInt64
+	Author        string
+	Feedback      string
+	PlanData      string
+	CreatedAt     time.Time
+}
+
+type RatingPrompt struct {
+	MealPlanID int64
+	DayIndex   int64
+	UserID     string
+	SentAt     time.Time
+}
+
 type Recipe struct {
-	ID        string
-	Data      string
-	UpdatedAt time.Time
+	ID          string
+	Data        string
+	UpdatedAt   time.Time
+	PrepMinutes sql.NullInt64
+	Servings    sql.NullInt64
+	Cuisine     sql.NullString
+	MealType    sql.NullString
+	MainProtein sql.NullString
 }
 
 type RecipeEmbedding struct {
@@ -53,11 +82,40 @@ type RecipeEmbedding struct {
 	EmbeddingDimensions int64
 }
 
+type RecipeIngredient struct {
+	RecipeID   string
+	Ingredient string
+}
+
+type RecipeRating struct {
+	UserID       string
+	RecipeID     string
+	Stars        int64
+	Comment      string
+	NeverSuggest bool
+	MealPlanID   int64
+	UpdatedAt    time.Time
+}
+
 type RecipeTag struct {
 	RecipeID string
 	Tag      string
 }
 
+type ReminderDelivery struct {
+	UserID    string
+	Kind      string
+	LocalDate string
+	SentAt    time.Time
+}
+
+type ReminderSetting struct {
+	UserID    string
+	Timezone  string
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
 type ShoppingList struct {
 	ID         int64
 	UserID     string
@@ -66,13 +124,34 @@ type ShoppingList struct {
 	CreatedAt  time.Time
 }
 
+type TagSuggestion struct {
+	Tag         string
+	LabelPt     string
+	RecipeID    string
+	Occurrences int64
+	CreatedAt   time.Time
+}
+
+type TagSynonym struct {
+	Synonym string
+	Tag     string
+}
+
+type TagTaxonomy struct {
+	Tag      string
+	LabelPt  string
+	Category string
+	Parent   sql.NullString
+}
+
 type UserMealPlan struct {
-	ID            int64
-	UserID        string
-	PlanData      string
-	WeekStartDate time.Time
-	Status        string
-	CreatedAt     time.Time
+	ID             int64
+	UserID         string
+	PlanData       string
+	WeekStartDate  time.Time
+	Status         string
+	CreatedAt      time.Time
+	CurrentVersion int64
 }
 
 type UserSession struct {
@@ -84,3 +163,10 @@ type UserSession struct {
 	ExpiresAt   time.Time
 	CreatedAt   time.Time
 }
+
+type UserSetting struct {
+	UserID              string
+	RepeatWindowWeeks   int64
+	FavoriteRepeatWeeks int64
+	UpdatedAt           time.Time
+}
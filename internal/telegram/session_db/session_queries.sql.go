@@ -7,6 +7,7 @@ package sessiondb
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -19,6 +20,22 @@ func (q *Queries) CleanupExpiredSessions(ctx context.Context, expiresAt time.Tim
 	return err
 }
 
+const createCookingTimer = `-- name: CreateCookingTimer :exec
+INSERT INTO cooking_timers (chat_id, message, due_at)
+VALUES (?, ?, ?)
+`
+
+type CreateCookingTimerParams struct {
+	ChatID  int64
+	Message string
+	DueAt   time.Time
+}
+
+func (q *Queries) CreateCookingTimer(ctx context.Context, arg CreateCookingTimerParams) error {
+	_, err := q.db.ExecContext(ctx, createCookingTimer, arg.ChatID, arg.Message, arg.DueAt)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO user_sessions (user_id, session_type, state, context_data, expires_at, created_at)
 VALUES (?, ?, ?, ?, ?, ?)
@@ -48,6 +65,15 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (i
 	return id, err
 }
 
+const deleteCookingTimer = `-- name: DeleteCookingTimer :exec
+DELETE FROM cooking_timers WHERE id = ?
+`
+
+func (q *Queries) DeleteCookingTimer(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCookingTimer, id)
+	return err
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM user_sessions WHERE id = ?
 `
@@ -57,21 +83,91 @@ func (q *Queries) DeleteSession(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteUserSessionsByType = `-- name: DeleteUserSessionsByType :exec
+DELETE FROM user_sessions WHERE user_id = ? AND session_type = ?
+`
+
+type DeleteUserSessionsByTypeParams struct {
+	UserID      string
+	SessionType string
+}
+
+func (q *Queries) DeleteUserSessionsByType(ctx context.Context, arg DeleteUserSessionsByTypeParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserSessionsByType, arg.UserID, arg.SessionType)
+	return err
+}
+
+const extendSession = `-- name: ExtendSession :exec
+UPDATE user_sessions
+SET expires_at = ?
+WHERE id = ?
+`
+
+type ExtendSessionParams struct {
+	ExpiresAt time.Time
+	ID        int64
+}
+
+func (q *Queries) ExtendSession(ctx context.Context, arg ExtendSessionParams) error {
+	_, err := q.db.ExecContext(ctx, extendSession, arg.ExpiresAt, arg.ID)
+	return err
+}
+
 const getActiveSession = `-- name: GetActiveSession :one
 SELECT id, user_id, session_type, state, context_data, expires_at, created_at
 FROM user_sessions
-WHERE user_id = ? AND expires_at > ?
+WHERE user_id = ? AND expires_at > ? AND session_type IN (/*SLICE:session_types*/?)
 ORDER BY created_at DESC
 LIMIT 1
 `
 
 type GetActiveSessionParams struct {
+	UserID       string
+	ExpiresAt    time.Time
+	SessionTypes []string
+}
+
+func (q *Queries) GetActiveSession(ctx context.Context, arg GetActiveSessionParams) (UserSession, error) {
+	query := getActiveSession
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.UserID)
+	queryParams = append(queryParams, arg.ExpiresAt)
+	if len(arg.SessionTypes) > 0 {
+		for _, v := range arg.SessionTypes {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:session_types*/?", strings.Repeat(",?", len(arg.SessionTypes))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:session_types*/?", "NULL", 1)
+	}
+	row := q.db.QueryRowContext(ctx, query, queryParams...)
+	var i UserSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.SessionType,
+		&i.State,
+		&i.ContextData,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSession = `-- name: GetSession :one
+SELECT id, user_id, session_type, state, context_data, expires_at, created_at
+FROM user_sessions
+WHERE id = ? AND user_id = ? AND expires_at > ?
+`
+
+type GetSessionParams struct {
+	ID        int64
 	UserID    string
 	ExpiresAt time.Time
 }
 
-func (q *Queries) GetActiveSession(ctx context.Context, arg GetActiveSessionParams) (UserSession, error) {
-	row := q.db.QueryRowContext(ctx, getActiveSession, arg.UserID, arg.ExpiresAt)
+func (q *Queries) GetSession(ctx context.Context, arg GetSessionParams) (UserSession, error) {
+	row := q.db.QueryRowContext(ctx, getSession, arg.ID, arg.UserID, arg.ExpiresAt)
 	var i UserSession
 	err := row.Scan(
 		&i.ID,
@@ -85,6 +181,41 @@ func (q *Queries) GetActiveSession(ctx context.Context, arg GetActiveSessionPara
 	return i, err
 }
 
+const listDueCookingTimers = `-- name: ListDueCookingTimers :many
+SELECT id, chat_id, message, due_at
+FROM cooking_timers
+WHERE due_at <= ?
+ORDER BY due_at
+`
+
+func (q *Queries) ListDueCookingTimers(ctx context.Context, dueAt time.Time) ([]CookingTimer, error) {
+	rows, err := q.db.QueryContext(ctx, listDueCookingTimers, dueAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CookingTimer
+	for rows.Next() {
+		var i CookingTimer
+		if err := rows.Scan(
+			&i.ID,
+			&i.ChatID,
+			&i.Message,
+			&i.DueAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateSession = `-- name: UpdateSession :exec
 UPDATE user_sessions
 SET state = ?, context_data = ?
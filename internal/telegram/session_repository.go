@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"ai-meal-planner/internal/clipper"
 	sessiondb "ai-meal-planner/internal/telegram/session_db"
 )
 
@@ -20,10 +21,51 @@ type Session struct {
 	CreatedAt   time.Time
 }
 
+// Session types and the states they can be in
+const (
+	SessionTypeAdjustPlan    = "adjust_plan"
+	SessionTypeEditClip      = "edit_clip"
+	SessionTypeClipPreview   = "clip_preview"
+	SessionTypeCooking       = "cooking"
+	SessionTypeRateRecipe    = "rate_recipe"
+	SessionTypeConfirmIntent = "confirm_intent"
+	SessionTypeSearch        = "search_results"
+
+	StateAwaitingFeedback = "awaiting_feedback"
+	StateAwaitingTitle    = "awaiting_title"
+	StateAwaitingTags     = "awaiting_tags"
+	StateAwaitingConfirm  = "awaiting_confirmation"
+	StateCooking          = "cooking"
+	StateAwaitingComment  = "awaiting_comment"
+	StateBrowsing         = "browsing"
+)
+
 // SessionContextData holds structured data stored in the context_data JSON field
 type SessionContextData struct {
 	PlanID          int64  `json:"plan_id"`
 	OriginalRequest string `json:"original_request"`
+	PostID          string `json:"post_id,omitempty"`
+
+	// Clip preview: the extracted recipe waiting for confirmation
+	Recipe    *clipper.ExtractedRecipe `json:"recipe,omitempty"`
+	SourceURL string                   `json:"source_url,omitempty"`
+	Tags      []string                 `json:"tags,omitempty"`
+
+	// Cooking mode: the recipe being cooked and the page shown (0 is the ingredient list).
+	// Rating comments reuse RecipeID for the recipe being rated.
+	RecipeID string `json:"recipe_id,omitempty"`
+	Step     int    `json:"step,omitempty"`
+
+	// Plan adjustment: the version the conversation started from, restored on Cancel,
+	// and the feedback already applied, oldest first
+	StartVersion int      `json:"start_version,omitempty"`
+	Feedback     []string `json:"feedback,omitempty"`
+
+	// Intent confirmation: the intent waiting for a yes, with its request in OriginalRequest
+	Intent string `json:"intent,omitempty"`
+
+	// Recipe search: the result IDs in ranking order, with the query in OriginalRequest
+	RecipeIDs []string `json:"recipe_ids,omitempty"`
 }
 
 // SessionRepository provides access to session persistence operations
@@ -65,9 +107,36 @@ func (sr *SessionRepository) Create(ctx context.Context, userID, sessionType, st
 	return result, nil
 }
 
-// GetActive retrieves the most recent active session for a user (non-expired)
-func (sr *SessionRepository) GetActive(ctx context.Context, userID string, now time.Time) (*Session, error) {
+// GetActive retrieves the most recent non-expired session of one of the given types for a
+// user, so a long session like cooking does not hide the one a message answers.
+func (sr *SessionRepository) GetActive(ctx context.Context, userID string, now time.Time, sessionTypes ...string) (*Session, error) {
 	row, err := sr.queries.GetActiveSession(ctx, sessiondb.GetActiveSessionParams{
+		UserID:       userID,
+		ExpiresAt:    now,
+		SessionTypes: sessionTypes,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &Session{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		SessionType: row.SessionType,
+		State:       row.State,
+		ContextData: row.ContextData,
+		ExpiresAt:   row.ExpiresAt,
+		CreatedAt:   row.CreatedAt,
+	}, nil
+}
+
+// GetByID retrieves a user's session by ID, returning nil if it does not exist or has expired
+func (sr *SessionRepository) GetByID(ctx context.Context, sessionID int64, userID string, now time.Time) (*Session, error) {
+	row, err := sr.queries.GetSession(ctx, sessiondb.GetSessionParams{
+		ID:        sessionID,
 		UserID:    userID,
 		ExpiresAt: now,
 	})
@@ -110,12 +179,64 @@ func (sr *SessionRepository) Update(ctx context.Context, sessionID int64, state
 	})
 }
 
+// Extend keeps a session alive for ttlSeconds from now
+func (sr *SessionRepository) Extend(ctx context.Context, sessionID int64, ttlSeconds int) error {
+	return sr.queries.ExtendSession(ctx, sessiondb.ExtendSessionParams{
+		ExpiresAt: time.Now().Add(time.Duration(ttlSeconds) * time.Second),
+		ID:        sessionID,
+	})
+}
+
 // Delete removes a session
 func (sr *SessionRepository) Delete(ctx context.Context, sessionID int64) error {
 	return sr.queries.DeleteSession(ctx, sessionID)
 }
 
+// DeleteByType removes the user's sessions of one type
+func (sr *SessionRepository) DeleteByType(ctx context.Context, userID, sessionType string) error {
+	return sr.queries.DeleteUserSessionsByType(ctx, sessiondb.DeleteUserSessionsByTypeParams{
+		UserID:      userID,
+		SessionType: sessionType,
+	})
+}
+
 // CleanupExpired removes all expired sessions (optional maintenance task)
 func (sr *SessionRepository) CleanupExpired(ctx context.Context) error {
 	return sr.queries.CleanupExpiredSessions(ctx, time.Now())
 }
+
+// CookingTimer is a timer started in cooking mode, with the message sent when it rings.
+type CookingTimer struct {
+	ID      int64
+	ChatID  int64
+	Message string
+	DueAt   time.Time
+}
+
+// AddCookingTimer stores a timer so it still rings after a restart.
+func (sr *SessionRepository) AddCookingTimer(ctx context.Context, chatID int64, message string, dueAt time.Time) error {
+	return sr.queries.CreateCookingTimer(ctx, sessiondb.CreateCookingTimerParams{
+		ChatID:  chatID,
+		Message: message,
+		DueAt:   dueAt.UTC(),
+	})
+}
+
+// DueCookingTimers returns the timers due at now, earliest first.
+func (sr *SessionRepository) DueCookingTimers(ctx context.Context, now time.Time) ([]CookingTimer, error) {
+	rows, err := sr.queries.ListDueCookingTimers(ctx, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	timers := make([]CookingTimer, 0, len(rows))
+	for _, row := range rows {
+		timers = append(timers, CookingTimer{ID: row.ID, ChatID: row.ChatID, Message: row.Message, DueAt: row.DueAt})
+	}
+	return timers, nil
+}
+
+// DeleteCookingTimer removes a timer that has rung.
+func (sr *SessionRepository) DeleteCookingTimer(ctx context.Context, timerID int64) error {
+	return sr.queries.DeleteCookingTimer(ctx, timerID)
+}
@@ -0,0 +1,150 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/reminder"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// repeatCommand shows or changes how soon recipes may come back in new plans.
+const repeatCommand = "/repeat"
+
+// handleRepeatCommand answers "/repeat" with the current repetition settings and
+// "/repeat <weeks> [favorite weeks]" by saving new ones.
+func (b *Bot) handleRepeatCommand(ctx context.Context, msg *tgbotapi.Message) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+	args := strings.Fields(strings.TrimPrefix(msg.Text, repeatCommand))
+
+	settings, err := b.planRepo.GetRepetitionSettings(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading repetition settings: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not load your settings."))
+		return
+	}
+
+	if len(args) > 0 {
+		updated, err := parseRepeatArgs(args, settings)
+		if err == nil {
+			err = b.planRepo.SaveRepetitionSettings(ctx, userID, updated)
+		}
+		if err != nil {
+			b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("❌ %v\nUsage: /repeat <weeks> [favorite weeks]", err)))
+			return
+		}
+		settings = updated
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, formatRepeatSettings(settings))
+	reply.ParseMode = "Markdown"
+	b.api.Send(reply)
+}
+
+// parseRepeatArgs reads the repeat window and, optionally, the favorite window.
+// A lone repeat window keeps the favorite window, capped to the new repeat window.
+func parseRepeatArgs(args []string, current planner.RepetitionSettings) (planner.RepetitionSettings, error) {
+	if len(args) > 2 {
+		return current, fmt.Errorf("expected at most two numbers")
+	}
+	weeks := make([]int, len(args))
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return current, fmt.Errorf("%q is not a number of weeks", arg)
+		}
+		weeks[i] = n
+	}
+
+	updated := planner.RepetitionSettings{
+		RepeatWindowWeeks:   weeks[0],
+		FavoriteRepeatWeeks: min(current.FavoriteRepeatWeeks, weeks[0]),
+	}
+	if len(weeks) == 2 {
+		updated.FavoriteRepeatWeeks = weeks[1]
+	}
+	return updated, updated.Validate()
+}
+
+func formatRepeatSettings(s planner.RepetitionSettings) string {
+	if s.RepeatWindowWeeks == 0 {
+		return "🔁 Recipes may repeat every week.\n\nChange it with `/repeat <weeks> [favorite weeks]`."
+	}
+	return fmt.Sprintf(
+		"🔁 Recipes from confirmed plans don't repeat within *%d* week(s); favorites may come back after *%d*.\n\nChange it with `/repeat <weeks> [favorite weeks]`.",
+		s.RepeatWindowWeeks, s.FavoriteRepeatWeeks,
+	)
+}
+
+// remindersCommand shows or changes the daily meal reminders.
+const remindersCommand = "/reminders"
+
+// handleRemindersCommand answers "/reminders" with the current reminder settings and
+// "/reminders on|off" or "/reminders <timezone>" by saving new ones.
+func (b *Bot) handleRemindersCommand(ctx context.Context, msg *tgbotapi.Message) {
+	userID := fmt.Sprintf("%d", msg.From.ID)
+	args := strings.Fields(strings.TrimPrefix(msg.Text, remindersCommand))
+
+	settings, err := b.reminderRepo.GetSettings(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading reminder settings: %v", err)
+		b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, "❌ Could not load your settings."))
+		return
+	}
+
+	if len(args) > 0 {
+		updated, err := parseRemindersArgs(args, settings)
+		if err == nil {
+			err = b.reminderRepo.SaveSettings(ctx, userID, updated)
+		}
+		if err != nil {
+			b.api.Send(tgbotapi.NewMessage(msg.Chat.ID, fmt.Sprintf("❌ %v\nUsage: /reminders on|off|<timezone>", err)))
+			return
+		}
+		settings = updated
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, formatReminderSettings(settings, b.defaultLocation()))
+	reply.ParseMode = "Markdown"
+	b.api.Send(reply)
+}
+
+// parseRemindersArgs turns reminders on or off, or sets the timezone they follow.
+func parseRemindersArgs(args []string, current reminder.Settings) (reminder.Settings, error) {
+	if len(args) != 1 {
+		return current, fmt.Errorf("expected on, off or a timezone")
+	}
+	updated := current
+	switch strings.ToLower(args[0]) {
+	case "on":
+		updated.Enabled = true
+	case "off":
+		updated.Enabled = false
+	default:
+		if _, err := reminder.LoadLocation(args[0], nil); err != nil {
+			return current, err
+		}
+		updated.Timezone = args[0]
+	}
+	return updated, nil
+}
+
+func formatReminderSettings(s reminder.Settings, fallback *time.Location) string {
+	timezone := s.Timezone
+	if timezone == "" {
+		timezone = fallback.String()
+	}
+	if !s.Enabled {
+		return fmt.Sprintf("⏰ Reminders are *off* (timezone %s).\n\nTurn them on with `/reminders on`.", escapeMarkdown(timezone))
+	}
+	return fmt.Sprintf(
+		"⏰ Every morning at *%02d:00* (%s) I send today's dinner and what to prepare for tomorrow. On %ss I remind you if next week has no plan.\n\nChange it with `/reminders off` or `/reminders <timezone>`, e.g. `/reminders Europe/Berlin`.",
+		dailyReminderHour, escapeMarkdown(timezone), planNudgeWeekday,
+	)
+}
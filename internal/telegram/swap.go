@@ -0,0 +1,232 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ai-meal-planner/internal/audit"
+	"ai-meal-planner/internal/planner"
+	"ai-meal-planner/internal/value"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// swapOptions is how many alternative recipes a swap offers.
+const swapOptions = 3
+
+// swapButtonsPerRow keeps the per-day swap buttons of the draft keyboard readable.
+const swapButtonsPerRow = 4
+
+// maxButtonTitle truncates recipe titles on buttons so they stay on one line.
+const maxButtonTitle = 32
+
+// swapKeyboardRows adds a "🔀 <day>" button for every unlocked Cook day of a draft plan.
+func swapKeyboardRows(plan *planner.MealPlan) [][]tgbotapi.InlineKeyboardButton {
+	var buttons []tgbotapi.InlineKeyboardButton
+	for i, day := range plan.Plan {
+		if day.RecipeID == "" || day.IsReuse() || day.Locked {
+			continue
+		}
+		label := "🔀 " + shortDay(day.Day)
+		data := fmt.Sprintf("swap|%d|%d|%s", plan.ID, i, planner.SwapSimilar)
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(label, data))
+	}
+
+	return dayButtonRows(buttons)
+}
+
+// dayButtonRows lays out per-day buttons swapButtonsPerRow to a row.
+func dayButtonRows(buttons []tgbotapi.InlineKeyboardButton) [][]tgbotapi.InlineKeyboardButton {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for start := 0; start < len(buttons); start += swapButtonsPerRow {
+		rows = append(rows, buttons[start:min(start+swapButtonsPerRow, len(buttons))])
+	}
+	return rows
+}
+
+// shortDay abbreviates a weekday name for a button label, keeping the meal of weekend
+// days so their buttons differ: "Saturday (Lunch)" is "Sat L".
+func shortDay(day string) string {
+	name, meal, _ := strings.Cut(strings.TrimSpace(day), "(")
+	name = strings.TrimSpace(name)
+	if len(name) > 3 {
+		name = name[:3]
+	}
+	if meal = strings.TrimSpace(meal); meal != "" {
+		return name + " " + meal[:1]
+	}
+	return name
+}
+
+// handleSwapOptions shows alternatives for one Cook day. Format: "swap|planID|day|mode".
+func (b *Bot) handleSwapOptions(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 4 {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+	plan, dayIndex, ok := b.loadSwapDay(ctx, query, userID, parts[1], parts[2])
+	if !ok {
+		return
+	}
+	mode := planner.SwapMode(parts[3])
+
+	pCtx := planner.PlanningContext{
+		Adults:           b.cfg.DefaultAdults,
+		Children:         b.cfg.DefaultChildren,
+		ChildrenAges:     b.cfg.DefaultChildrenAges,
+		CookingFrequency: b.cfg.DefaultCookingFrequency,
+	}
+	alternatives, err := b.planner.SwapAlternatives(ctx, userID, plan, dayIndex, mode, swapOptions, pCtx)
+	if err != nil {
+		log.Printf("Error finding swap alternatives: %v", err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not find alternatives.")
+		return
+	}
+
+	text, keyboard := formatSwapOptions(plan, dayIndex, mode, alternatives)
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = &keyboard
+	b.api.Send(edit)
+}
+
+// formatSwapOptions lists the alternatives with a button each, a toggle between similar
+// and different suggestions, and a way back to the draft.
+func formatSwapOptions(plan *planner.MealPlan, dayIndex int, mode planner.SwapMode, alternatives []value.Recipe) (string, tgbotapi.InlineKeyboardMarkup) {
+	day := plan.Plan[dayIndex]
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔀 *Swap %s* — now _%s_\n\n", escapeMarkdown(day.Day), escapeMarkdown(strings.TrimPrefix(day.RecipeTitle, "Cook: "))))
+	if len(alternatives) == 0 {
+		sb.WriteString("_No alternatives left that fit your plan and history._")
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, rec := range alternatives {
+		sb.WriteString(fmt.Sprintf("%d. %s", i+1, escapeMarkdown(rec.Title)))
+		if rec.PrepTime != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", escapeMarkdown(rec.PrepTime)))
+		}
+		sb.WriteString("\n")
+
+		label := fmt.Sprintf("%d. %s", i+1, truncateTitle(rec.Title))
+		data := fmt.Sprintf("swapto|%d|%d|%d|%s", plan.ID, plan.Version, dayIndex, rec.ID)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, data)))
+	}
+
+	toggleLabel, toggleMode := "🎲 Something different", planner.SwapDifferent
+	if mode == planner.SwapDifferent {
+		toggleLabel, toggleMode = "🎯 Something similar", planner.SwapSimilar
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(toggleLabel, fmt.Sprintf("swap|%d|%d|%s", plan.ID, dayIndex, toggleMode)),
+		tgbotapi.NewInlineKeyboardButtonData("⬅️ Back", fmt.Sprintf("swapback|%d", plan.ID)),
+	))
+	return sb.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func truncateTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) <= maxButtonTitle {
+		return title
+	}
+	return string(runes[:maxButtonTitle-1]) + "…"
+}
+
+// handleSwapChoose puts the chosen recipe in the day and its leftovers days and saves the
+// result as a new draft version. Format: "swapto|planID|version|day|recipeID".
+func (b *Bot) handleSwapChoose(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	if len(parts) < 5 {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+	plan, dayIndex, ok := b.loadSwapDay(ctx, query, userID, parts[1], parts[3])
+	if !ok {
+		return
+	}
+	if version, _ := strconv.Atoi(parts[2]); version != plan.Version {
+		b.answerCallback(query, "The plan changed since these options were shown")
+		b.sendDraftPlan(chatID, messageID, plan)
+		return
+	}
+
+	recipes, err := b.planner.RecipeSearcher.GetByIds(ctx, []string{parts[4]})
+	if err != nil || len(recipes) == 0 {
+		log.Printf("Error retrieving swap recipe %s: %v", parts[4], err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not retrieve that recipe.")
+		return
+	}
+
+	if err := b.swapPlanDay(ctx, userID, plan, dayIndex, recipes[0]); err != nil {
+		log.Printf("Error swapping day %d of plan %d: %v", dayIndex, plan.ID, err)
+		b.editMarkdown(chatID, messageID, "❌ *Error:* Could not swap that day.")
+		return
+	}
+
+	b.sendDraftPlan(chatID, messageID, plan)
+}
+
+// swapPlanDay puts rec in a Cook day of the plan and its leftovers days, saves the result
+// as a new draft version and logs the swap, so it can be undone.
+func (b *Bot) swapPlanDay(ctx context.Context, userID string, plan *planner.MealPlan, dayIndex int, rec value.Recipe) error {
+	previous := *plan
+	previous.Plan = slices.Clone(plan.Plan)
+	if err := planner.SwapDay(plan, dayIndex, rec); err != nil {
+		return err
+	}
+	plan.Status = planner.StatusDraft
+
+	feedback := fmt.Sprintf("Swap %s: %s → %s",
+		previous.Plan[dayIndex].Day,
+		strings.TrimPrefix(previous.Plan[dayIndex].RecipeTitle, "Cook: "),
+		rec.Title,
+	)
+	if _, err := b.planRepo.SaveVersion(ctx, userID, plan, planner.VersionAuthorSwap, feedback); err != nil {
+		return err
+	}
+	_ = b.auditRepo.LogInteraction(ctx, userID, plan.ID, audit.ActionSwapDay, plan.OriginalRequest, feedback, &previous, plan)
+	return nil
+}
+
+// handleSwapBack returns from the swap options to the draft. Format: "swapback|planID".
+func (b *Bot) handleSwapBack(ctx context.Context, query *tgbotapi.CallbackQuery, userID string, parts []string) {
+	planID, _ := strconv.ParseInt(parts[1], 10, 64)
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || plan == nil {
+		log.Printf("Error retrieving plan %d: %v", planID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not retrieve plan.")
+		return
+	}
+	b.sendDraftPlan(query.Message.Chat.ID, query.Message.MessageID, plan)
+}
+
+// loadSwapDay loads a draft plan and checks that the day can be swapped, answering the
+// callback when it cannot.
+func (b *Bot) loadSwapDay(ctx context.Context, query *tgbotapi.CallbackQuery, userID, planArg, dayArg string) (*planner.MealPlan, int, bool) {
+	planID, _ := strconv.ParseInt(planArg, 10, 64)
+	dayIndex, _ := strconv.Atoi(dayArg)
+
+	plan, err := b.planRepo.GetByIDForUser(ctx, planID, userID)
+	if err != nil || plan == nil {
+		log.Printf("Error retrieving plan %d to swap: %v", planID, err)
+		b.editMarkdown(query.Message.Chat.ID, query.Message.MessageID, "❌ *Error:* Could not retrieve plan.")
+		return nil, 0, false
+	}
+	if plan.Status != planner.StatusDraft {
+		b.answerCallback(query, "Only draft plans can be changed")
+		return nil, 0, false
+	}
+	if dayIndex < 0 || dayIndex >= len(plan.Plan) || plan.Plan[dayIndex].RecipeID == "" || plan.Plan[dayIndex].IsReuse() {
+		b.answerCallback(query, "That day has nothing to swap")
+		return nil, 0, false
+	}
+	if plan.Plan[dayIndex].Locked {
+		b.answerCallback(query, "Unlock the day before swapping it")
+		return nil, 0, false
+	}
+	return plan, dayIndex, true
+}
@@ -0,0 +1,156 @@
+package telegramtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Message is a message the bot sent, or an edit it made to one, as Telegram would show it.
+type Message struct {
+	ChatID    int64
+	MessageID int
+	Text      string
+	Keyboard  *tgbotapi.InlineKeyboardMarkup
+	// Edit is set when the bot changed a message it sent earlier
+	Edit bool
+}
+
+// Button returns the callback data of the first button whose label contains label.
+func (m Message) Button(label string) (string, bool) {
+	if m.Keyboard == nil {
+		return "", false
+	}
+	for _, row := range m.Keyboard.InlineKeyboard {
+		for _, button := range row {
+			if strings.Contains(button.Text, label) && button.CallbackData != nil {
+				return *button.CallbackData, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Messenger is a recording fake of the Telegram Bot API. It keeps every message and
+// edit the bot makes, with its keyboard, and hands out increasing message IDs.
+type Messenger struct {
+	// Updates is returned by GetUpdatesChan for tests of long polling.
+	Updates chan tgbotapi.Update
+	// ShouldError makes Send and Request fail.
+	ShouldError bool
+
+	mu        sync.Mutex
+	lastID    int
+	sent      []Message
+	callbacks []tgbotapi.CallbackConfig
+}
+
+func (m *Messenger) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ShouldError {
+		return tgbotapi.Message{}, fmt.Errorf("mock telegram error")
+	}
+
+	var recorded Message
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
+		m.lastID++
+		recorded = Message{ChatID: msg.ChatID, MessageID: m.lastID, Text: msg.Text, Keyboard: inlineKeyboard(msg.ReplyMarkup)}
+	case tgbotapi.EditMessageTextConfig:
+		recorded = Message{ChatID: msg.ChatID, MessageID: msg.MessageID, Text: msg.Text, Keyboard: msg.ReplyMarkup, Edit: true}
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		// Only the keyboard changes, so the edit carries the text the message already shows
+		current, _ := m.current(msg.ChatID, msg.MessageID)
+		recorded = Message{ChatID: msg.ChatID, MessageID: msg.MessageID, Text: current.Text, Keyboard: msg.ReplyMarkup, Edit: true}
+	case tgbotapi.CallbackConfig:
+		m.callbacks = append(m.callbacks, msg)
+		return tgbotapi.Message{}, nil
+	default:
+		// Photos, documents and other uploads are recorded as empty messages
+		m.lastID++
+		recorded = Message{MessageID: m.lastID}
+	}
+	m.sent = append(m.sent, recorded)
+
+	return tgbotapi.Message{
+		MessageID: recorded.MessageID,
+		Chat:      &tgbotapi.Chat{ID: recorded.ChatID},
+		Text:      recorded.Text,
+	}, nil
+}
+
+func (m *Messenger) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if _, err := m.Send(c); err != nil {
+		return nil, err
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (m *Messenger) GetFileDirectURL(fileID string) (string, error) {
+	return "https://files.example.test/" + fileID, nil
+}
+
+func (m *Messenger) GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Updates == nil {
+		m.Updates = make(chan tgbotapi.Update)
+	}
+	return m.Updates
+}
+
+func (m *Messenger) StopReceivingUpdates() {}
+
+// Sent returns every message and edit so far, oldest first.
+func (m *Messenger) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Last returns the most recent message or edit.
+func (m *Messenger) Last() (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		return Message{}, false
+	}
+	return m.sent[len(m.sent)-1], true
+}
+
+// Current returns a message as it stands after the edits made to it.
+func (m *Messenger) Current(chatID int64, messageID int) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.current(chatID, messageID)
+}
+
+func (m *Messenger) current(chatID int64, messageID int) (Message, bool) {
+	for i := len(m.sent) - 1; i >= 0; i-- {
+		if m.sent[i].ChatID == chatID && m.sent[i].MessageID == messageID {
+			return m.sent[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Callbacks returns the answers given to button presses, oldest first.
+func (m *Messenger) Callbacks() []tgbotapi.CallbackConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]tgbotapi.CallbackConfig(nil), m.callbacks...)
+}
+
+// inlineKeyboard returns a message's reply markup when it is an inline keyboard.
+func inlineKeyboard(markup any) *tgbotapi.InlineKeyboardMarkup {
+	switch k := markup.(type) {
+	case tgbotapi.InlineKeyboardMarkup:
+		return &k
+	case *tgbotapi.InlineKeyboardMarkup:
+		return k
+	}
+	return nil
+}
@@ -7,14 +7,30 @@ import (
 
 // Recipe represents a recipe
 type Recipe struct {
-	ID          string   `json:"id,omitempty"`
-	Title       string   `json:"title,omitempty"`
-	SideDishes  []string `json:"side_dishes,omitempty"`
-	Ingredients []string `json:"ingredients,omitempty"`
-	Tags        []string `json:"tags,omitempty"`
-	PrepTime    string   `json:"prep_time,omitempty"`
-	Servings    string   `json:"servings,omitempty"`
-	UpdatedAt   string   `json:"source_updated_at,omitempty"`
+	ID              string   `json:"id,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	SideDishes      []string `json:"side_dishes,omitempty"`
+	Ingredients     []string `json:"ingredients,omitempty"`
+	IngredientNames []string `json:"ingredient_names,omitempty"` // Bare lowercase names in pt-BR and English, e.g. "camarão", "shrimp"
+	Steps           []string `json:"steps,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	PrepTime        string   `json:"prep_time,omitempty"`
+	TotalMinutes    int      `json:"total_minutes,omitempty"`
+	ActiveMinutes   int      `json:"active_minutes,omitempty"`
+	Servings        string   `json:"servings,omitempty"`
+	Yield           int      `json:"yield,omitempty"`
+	Cuisine         string   `json:"cuisine,omitempty"`
+	MealType        string   `json:"meal_type,omitempty"`
+	MainProtein     string   `json:"main_protein,omitempty"`
+	Equipment       []string `json:"equipment,omitempty"`
+	FeatureImage    string   `json:"feature_image,omitempty"`
+	UpdatedAt       string   `json:"source_updated_at,omitempty"`
+	SourceURL       string   `json:"source_url,omitempty"`
+	PostURL         string   `json:"post_url,omitempty"` // The Ghost post the recipe was extracted from
+
+	// ExtractionVersion records which extractor revision produced the fields above,
+	// so recipes normalized by an older prompt can be found and re-extracted.
+	ExtractionVersion int `json:"extraction_version,omitempty"`
 }
 
 // returns a semantic string representation of the recipe